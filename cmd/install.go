@@ -19,7 +19,10 @@ import (
 )
 
 func newInstallCmd() *cobra.Command {
-	var force bool
+	var (
+		force  bool
+		bundle string
+	)
 	cmd := &cobra.Command{
 		Use:   "install <component1>[:version] [component2...N]",
 		Short: "Install a specific version of a component",
@@ -31,9 +34,20 @@ You can install multiple components at once, or install multiple versions
 of the same component:
 
   tiup install tidb:v3.0.5 tikv pd
-  tiup install tidb:v3.0.5 tidb:v3.0.8 tikv:v3.0.9`,
+  tiup install tidb:v3.0.5 tidb:v3.0.8 tikv:v3.0.9
+
+The components can also be installed from an offline bundle created by
+'tiup mirror bundle', the bundle is verified against the trusted root of
+the local profile and the mirror setting is left unchanged. All components
+in the bundle will be installed if there is no component specified:
+
+  tiup install --from tiup-bundle.tar.gz
+  tiup install --from tiup-bundle.tar.gz tidb`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			env := environment.GlobalEnv()
+			if bundle != "" {
+				return env.InstallBundle(bundle, args, force)
+			}
 			if len(args) == 0 {
 				return cmd.Help()
 			}
@@ -41,5 +55,6 @@ of the same component:
 		},
 	}
 	cmd.Flags().BoolVar(&force, "force", false, "If the specified version was already installed, force a reinstallation")
+	cmd.Flags().StringVar(&bundle, "from", "", "Install the components from the offline bundle instead of the mirror")
 	return cmd
 }
@@ -0,0 +1,91 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/pingcap/tiup/pkg/environment"
+	"github.com/pingcap/tiup/pkg/localdata"
+	"github.com/pingcap/tiup/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newLockCmd() *cobra.Command {
+	var platforms []string
+	cmd := &cobra.Command{
+		Use:   "lock [component1][:version] [component2...N]",
+		Short: "Pin versions of components in the lock file",
+		Long: `Pin versions of components in the lock file, so that the same versions are
+used by 'tiup <component>', 'tiup install' and 'tiup update' between runs.
+The lock file is specified by $TIUP_LOCK_FILE or discovered upward from the
+working directory, a new tiup.lock is created in the working directory if
+there is no one. The latest stable version is pinned if there is no version
+specified, and all components in the lock file are refreshed to the latest
+stable versions if there is no component specified.
+
+The hashes of the tarballs are pinned for the platforms specified, which
+default to the ones in the lock file and the current platform.
+
+  tiup lock cluster ctl:v7.5.0                        # Pin cluster and ctl
+  tiup lock --platform linux/amd64,darwin/arm64 cluster  # Pin cluster for both platforms
+  tiup lock                                            # Refresh the lock file`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			env := environment.GlobalEnv()
+			path, err := environment.FindLockfile()
+			if err != nil {
+				return err
+			}
+			if path == "" {
+				wd, err := os.Getwd()
+				if err != nil {
+					return err
+				}
+				path = filepath.Join(wd, localdata.LockFilename)
+			}
+
+			lock := environment.NewLockfile(path)
+			if utils.IsExist(path) {
+				if lock, err = environment.LoadLockfile(path); err != nil {
+					return err
+				}
+			}
+
+			if len(args) == 0 {
+				if len(lock.Components) == 0 {
+					return cmd.Help()
+				}
+				for _, comp := range lock.Components {
+					args = append(args, comp.Name)
+				}
+			}
+			if len(platforms) == 0 {
+				platforms = lock.Platforms()
+				if current := env.V1Repository().PlatformString(); !slices.Contains(platforms, current) {
+					platforms = append(platforms, current)
+				}
+			}
+			if err := env.LockComponents(lock, args, platforms); err != nil {
+				return err
+			}
+			fmt.Printf("Lock file %s is saved\n", path)
+			return nil
+		},
+	}
+	cmd.Flags().StringSliceVar(&platforms, "platform", nil, "Pin the hashes of tarballs for the platforms, e.g. linux/amd64")
+	return cmd
+}
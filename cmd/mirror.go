@@ -64,6 +64,7 @@ of components or the repository itself.`,
 		newMirrorSignCmd(),
 		newMirrorGenkeyCmd(),
 		newMirrorCloneCmd(),
+		newMirrorBundleCmd(),
 		newMirrorMergeCmd(),
 		newMirrorPublishCmd(),
 		newMirrorShowCmd(),
@@ -912,6 +913,52 @@ func newMirrorMergeCmd() *cobra.Command {
 	return cmd
 }
 
+// the `mirror bundle` sub command
+func newMirrorBundleCmd() *cobra.Command {
+	var (
+		output string
+		goos   string
+		goarch string
+	)
+	cmd := &cobra.Command{
+		Use: "bundle <component1>[:version] [component2...N]",
+		Example: `  tiup mirror bundle tidb:v7.5.0 pd:v7.5.0 tikv:v7.5.0        # Pack the specific versions
+  tiup mirror bundle cluster --os linux --arch arm64 -O cluster.tar.gz  # Pack the latest cluster for linux/arm64`,
+		Short: "Pack components into an offline bundle",
+		Long: `Pack components into an offline bundle which can be installed by
+'tiup install --from <bundle>'. The bundle contains the signed manifests of
+the current mirror as they are, so it is verified against the trusted root
+of the profile installing it. Install the bundle before the timestamp of the
+mirror expires.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+
+			var specs []repository.ComponentSpec
+			for _, arg := range args {
+				component, v := environment.ParseCompVersion(arg)
+				specs = append(specs, repository.ComponentSpec{ID: component, Version: v.String()})
+			}
+
+			repo := environment.GlobalEnv().V1Repository().WithOptions(repository.Options{
+				GOOS:   goos,
+				GOARCH: goarch,
+			})
+			if err := repo.CreateBundle(specs, output); err != nil {
+				return err
+			}
+			fmt.Printf("Bundle %s is created\n", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "O", "tiup-bundle.tar.gz", "Specify the path of the bundle")
+	cmd.Flags().StringVar(&goos, "os", runtime.GOOS, "Specify the target os of the bundle")
+	cmd.Flags().StringVar(&goarch, "arch", runtime.GOARCH, "Specify the target architecture of the bundle")
+	return cmd
+}
+
 // the `mirror clone` sub command
 func newMirrorCloneCmd() *cobra.Command {
 	var (
@@ -192,6 +192,7 @@ the latest stable version will be downloaded from the repository.`,
 
 	rootCmd.AddCommand(
 		newInstallCmd(),
+		newLockCmd(),
 		newListCmd(),
 		newUninstallCmd(),
 		newUpdateCmd(),
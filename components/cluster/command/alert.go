@@ -0,0 +1,76 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tiup/pkg/cluster/manager"
+	"github.com/spf13/cobra"
+)
+
+func newAlertCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alert",
+		Short: "Manage the alerts of the cluster",
+	}
+
+	cmd.AddCommand(newAlertSilenceCmd())
+	return cmd
+}
+
+func newAlertSilenceCmd() *cobra.Command {
+	opt := manager.AlertSilenceOptions{}
+	cmd := &cobra.Command{
+		Use:         "silence <cluster-name>",
+		Short:       "Silence alerts of the cluster in Alertmanager",
+		Annotations: map[string]string{annotationMetaRead: "0"},
+		Long: `Silence alerts of the cluster in Alertmanager.
+
+A silence matching the alerts of the cluster is created with the Alertmanager
+API, additional label matchers narrow it down. The ID of the silence is printed
+on success:
+
+  tiup cluster alert silence <cluster-name> --duration 2h
+  tiup cluster alert silence <cluster-name> -m alertname=TiKV_server_is_down -m instance=~"10.0.1.1:.*"`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return cmd.Help()
+			}
+
+			id, err := cm.AlertSilence(args[0], opt, gOpt)
+			if err != nil {
+				return err
+			}
+			fmt.Println(id)
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return shellCompGetClusterName(cm, toComplete)
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&opt.Matchers, "matcher", "m", nil, "Label matcher of the alerts to silence, like name=value, name!=value, name=~regex or name!~regex")
+	cmd.Flags().DurationVar(&opt.Duration, "duration", 2*time.Hour, "How long the silence lasts")
+	cmd.Flags().StringVar(&opt.Comment, "comment", "", "Comment of the silence")
+	cmd.Flags().StringVar(&opt.Author, "author", "", "Creator of the silence, defaults to the current user")
+
+	return cmd
+}
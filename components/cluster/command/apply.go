@@ -0,0 +1,147 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pingcap/tiup/pkg/cluster/manager"
+	"github.com/pingcap/tiup/pkg/tui"
+	"github.com/pingcap/tiup/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newApplyCmd() *cobra.Command {
+	opt := manager.DeployOptions{
+		IdentityFile: filepath.Join(utils.UserHome(), ".ssh", "id_rsa"),
+	}
+	var (
+		version string
+		dryRun  bool
+	)
+	cmd := &cobra.Command{
+		Use:         "apply <cluster-name> <topology.yaml>",
+		Short:       "Reconcile the cluster with a topology file",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
+		Long: `Reconcile the cluster with a topology file.
+
+The topology file is compared with the current topology of the cluster, and
+the changes are applied with the existing flows in the following order:
+
+  1. scale-out the instances only in the topology file
+  2. upgrade if the version or component_versions are changed
+  3. save the changed configs and reload the affected instances
+  4. scale-in the instances not in the topology file`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return cmd.Help()
+			}
+			clusterName := args[0]
+
+			// hold the lock from planning to the last step, so that the
+			// cluster is not changed by others in the middle
+			unlock, err := cm.LockCluster(clusterName)
+			if err != nil {
+				return err
+			}
+			defer unlock()
+
+			plan, err := cm.PlanApply(clusterName, args[1], version)
+			if err != nil {
+				return err
+			}
+			cm.DisplayApplyPlan(clusterName, plan)
+			if plan.IsEmpty() || dryRun {
+				return nil
+			}
+			if !skipConfirm {
+				if err := tui.PromptForConfirmOrAbortError("Do you want to apply the plan? [y/N]: "); err != nil {
+					return err
+				}
+			}
+
+			if len(plan.Added) > 0 {
+				data, err := plan.ScaleOutTopology()
+				if err != nil {
+					return err
+				}
+				f, err := os.CreateTemp("", "tiup-cluster-apply-*.yaml")
+				if err != nil {
+					return err
+				}
+				defer os.Remove(f.Name())
+				if _, err := f.Write(data); err != nil {
+					f.Close()
+					return err
+				}
+				if err := f.Close(); err != nil {
+					return err
+				}
+				if err := cm.ScaleOut(clusterName, f.Name(), postScaleOutHook, final, opt, true, gOpt); err != nil {
+					return err
+				}
+			}
+
+			if plan.NeedUpgrade() {
+				if err := cm.Upgrade(clusterName, plan.TargetVersion(), plan.ComponentVersions, gOpt, true, false, false, 0); err != nil {
+					return err
+				}
+			}
+
+			if plan.NeedReload() {
+				nodes, err := cm.ApplyTopologyConfig(clusterName, plan)
+				if err != nil {
+					return err
+				}
+				reloadOpt := gOpt
+				reloadOpt.Nodes = nodes
+				if err := cm.Reload(clusterName, reloadOpt, false, true); err != nil {
+					return err
+				}
+			}
+
+			if len(plan.Removed) > 0 {
+				scaleInOpt := gOpt
+				scaleInOpt.Nodes = plan.RemovedNodes()
+				if err := cm.ScaleIn(clusterName, true, scaleInOpt, scaleInHook(clusterName, scaleInOpt)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return shellCompGetClusterName(cm, toComplete)
+			case 1:
+				return nil, cobra.ShellCompDirectiveDefault
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&version, "version", "", "The target version of the cluster, the current version is kept if not set")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Only print the plan without applying it")
+	cmd.Flags().StringVarP(&opt.User, "user", "u", utils.CurrentUser(), "The user name to login via SSH when scaling out. The user must has root (or sudo) privilege.")
+	cmd.Flags().StringVarP(&opt.IdentityFile, "identity_file", "i", opt.IdentityFile, "The path of the SSH identity file. If specified, public key authentication will be used.")
+	cmd.Flags().BoolVarP(&opt.UsePassword, "password", "p", false, "Use password of target hosts. If specified, password authentication will be used.")
+	cmd.Flags().BoolVarP(&opt.NoLabels, "no-labels", "", false, "Don't check TiKV labels")
+	cmd.Flags().BoolVar(&opt.NoRollback, "no-rollback", false, "Keep the changes made on hosts when failed, the completed steps are undone by default")
+	cmd.Flags().Uint64Var(&gOpt.APITimeout, "transfer-timeout", 600, "Timeout in seconds when transferring PD and TiKV store leaders, also for TiCDC drain one capture")
+
+	return cmd
+}
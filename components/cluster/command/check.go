@@ -28,8 +28,9 @@ func newCheckCmd() *cobra.Command {
 		IdentityFile: path.Join(utils.UserHome(), ".ssh", "id_rsa"),
 	}
 	cmd := &cobra.Command{
-		Use:   "check <topology.yml | cluster-name> [scale-out.yml]",
-		Short: "Perform preflight checks for the cluster.",
+		Use:         "check <topology.yml | cluster-name> [scale-out.yml]",
+		Short:       "Perform preflight checks for the cluster.",
+		Annotations: map[string]string{annotationMetaRead: "0"},
 		Long: `Perform preflight checks for the cluster. By default, it checks deploy servers
 before a cluster is deployed, the input is the topology.yaml for the cluster.
 If '--cluster' is set, it will perform checks for an existing cluster, the input
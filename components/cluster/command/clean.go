@@ -23,8 +23,9 @@ func newCleanCmd() *cobra.Command {
 	cleanALl := false
 
 	cmd := &cobra.Command{
-		Use:   "clean <cluster-name>",
-		Short: "(EXPERIMENTAL) Cleanup a specified cluster",
+		Use:         "clean <cluster-name>",
+		Short:       "(EXPERIMENTAL) Cleanup a specified cluster",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		Long: `EXPERIMENTAL: This is an experimental feature, things may or may not work,
 please backup your data before process.
 
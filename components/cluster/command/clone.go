@@ -0,0 +1,61 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newCloneCmd() *cobra.Command {
+	var (
+		hostsMap string
+		output   string
+	)
+	cmd := &cobra.Command{
+		Use:         "clone <src-cluster-name> <new-cluster-name>",
+		Short:       "Generate a topology with the same configs of a cluster on different hosts",
+		Annotations: map[string]string{annotationMetaRead: "0,1"},
+		Long: `Generate a topology with the same configs of a cluster on different hosts.
+
+The hosts map is a yaml file mapping each host of the source cluster to a new
+host, e.g.:
+
+    172.16.5.1: 172.16.6.1
+    172.16.5.2: 172.16.6.2
+
+The generated topology can be deployed as the new cluster with the deploy
+command.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return cmd.Help()
+			}
+
+			return cm.CloneCluster(args[0], args[1], hostsMap, output)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return shellCompGetClusterName(cm, toComplete)
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&hostsMap, "hosts-map", "", "The yaml file mapping the hosts of the source cluster to the new hosts")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "The file to write the topology to, print it if not specified")
+	_ = cmd.MarkFlagRequired("hosts-map")
+
+	return cmd
+}
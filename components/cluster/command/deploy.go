@@ -33,6 +33,7 @@ func newDeploy() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:          "deploy <cluster-name> <version> <topology.yaml>",
 		Short:        "Deploy a cluster for production",
+		Annotations:  map[string]string{annotationMetaWrite: "0"},
 		Long:         "Deploy a cluster for production. SSH connection will be used to deploy files, as well as creating system users for running the service.",
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -70,6 +71,7 @@ func newDeploy() *cobra.Command {
 	cmd.Flags().BoolVarP(&opt.UsePassword, "password", "p", false, "Use password of target hosts. If specified, password authentication will be used.")
 	cmd.Flags().BoolVarP(&gOpt.IgnoreConfigCheck, "ignore-config-check", "", false, "Ignore the config check result of components")
 	cmd.Flags().BoolVarP(&opt.NoLabels, "no-labels", "", false, "Don't check TiKV labels")
+	cmd.Flags().BoolVar(&opt.NoRollback, "no-rollback", false, "Keep the changes made on hosts when failed, the completed steps are undone by default")
 
 	return cmd
 }
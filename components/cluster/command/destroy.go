@@ -24,14 +24,27 @@ import (
 func newDestroyCmd() *cobra.Command {
 	destroyOpt := operator.Options{}
 	cmd := &cobra.Command{
-		Use:   "destroy <cluster-name>",
-		Short: "Destroy a specified cluster",
+		Use:         "destroy <cluster-name>",
+		Short:       "Destroy a specified cluster",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		Long: `Destroy a specified cluster, which will clean the deployment binaries and data.
 You can retain some nodes and roles data when destroy cluster, eg:
 
   $ tiup cluster destroy <cluster-name> --retain-role-data prometheus
   $ tiup cluster destroy <cluster-name> --retain-node-data 172.16.13.11:9000
-  $ tiup cluster destroy <cluster-name> --retain-node-data 172.16.13.12`,
+  $ tiup cluster destroy <cluster-name> --retain-node-data 172.16.13.12
+
+You can also archive the data and log directories of all nodes before
+destroying, to a local directory, a directory on a remote host or S3:
+
+  $ tiup cluster destroy <cluster-name> --archive-to /data/archive
+  $ tiup cluster destroy <cluster-name> --archive-to 172.16.13.20:/data/archive
+  $ tiup cluster destroy <cluster-name> --archive-to "s3://bucket/prefix?endpoint=172.16.13.21:9000"
+
+The remote host is connected as the deploy user of the cluster, with the SSH
+key of the cluster unless --archive-ssh-key is specified. The cluster is not
+destroyed if any directory fails to be archived, the tarballs already written
+are kept with a manifest marked as partial.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
@@ -63,6 +76,9 @@ You can retain some nodes and roles data when destroy cluster, eg:
 
 	cmd.Flags().StringArrayVar(&destroyOpt.RetainDataNodes, "retain-node-data", nil, "Specify the nodes or hosts whose data will be retained")
 	cmd.Flags().StringArrayVar(&destroyOpt.RetainDataRoles, "retain-role-data", nil, "Specify the roles whose data will be retained")
+	cmd.Flags().StringVar(&destroyOpt.ArchiveTo, "archive-to", "", "Archive data and log directories to <path>, <host:path> or <s3://bucket/prefix> before destroying")
+	cmd.Flags().IntVar(&destroyOpt.ArchiveSSHPort, "archive-ssh-port", 22, "The SSH port of the host of the archive target")
+	cmd.Flags().StringVar(&destroyOpt.ArchiveSSHKey, "archive-ssh-key", "", "The SSH private key to connect the host of the archive target, the key of the cluster is used if not specified")
 	cmd.Flags().BoolVar(&destroyOpt.Force, "force", false, "Force will ignore remote error while destroy the cluster")
 
 	return cmd
@@ -0,0 +1,83 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"time"
+
+	"github.com/docker/go-units"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/manager"
+	"github.com/spf13/cobra"
+)
+
+func newDiagCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diag",
+		Short: "Collect diagnostic data of the cluster",
+	}
+
+	cmd.AddCommand(newDiagCollectCmd())
+	return cmd
+}
+
+func newDiagCollectCmd() *cobra.Command {
+	opt := manager.DiagOptions{}
+	var fileSizeLimit, sizeLimit string
+	cmd := &cobra.Command{
+		Use:         "collect <cluster-name>",
+		Short:       "Collect topology, configs, system info and logs into a tarball",
+		Annotations: map[string]string{annotationMetaRead: "0"},
+		Long: `Collect topology, configs, system info and logs into a tarball.
+
+The bundle contains the metadata of the cluster, rendered configs and run
+scripts of instances, system info and kernel logs of hosts, stores, config
+and members from PD, and recent log files. Passwords, tokens and other
+secrets are redacted before being written to the bundle.
+
+  tiup cluster diag collect <cluster-name> --out bundle.tar.gz --since 2h`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return cmd.Help()
+			}
+
+			var err error
+			if opt.FileSizeLimit, err = units.RAMInBytes(fileSizeLimit); err != nil {
+				return perrs.Annotatef(err, "invalid --file-size-limit")
+			}
+			if opt.SizeLimit, err = units.RAMInBytes(sizeLimit); err != nil {
+				return perrs.Annotatef(err, "invalid --size-limit")
+			}
+
+			return cm.DiagCollect(args[0], opt, gOpt)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return shellCompGetClusterName(cm, toComplete)
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&opt.Output, "out", "o", "", "Path of the output tarball (default \"<cluster-name>-diag-<time>.tar.gz\")")
+	cmd.Flags().StringSliceVarP(&gOpt.Roles, "role", "R", nil, "Only collect data of specified roles")
+	cmd.Flags().StringSliceVarP(&gOpt.Nodes, "node", "N", nil, "Only collect data of specified nodes")
+	cmd.Flags().DurationVar(&opt.Since, "since", time.Hour, "Collect log files modified in the duration, only current log files are collected if set to 0")
+	cmd.Flags().StringVar(&fileSizeLimit, "file-size-limit", "20MiB", "Max size collected from a single file, only the tail is kept for larger files")
+	cmd.Flags().StringVar(&sizeLimit, "size-limit", "500MiB", "Max total size of the collected files, set to 0 for unlimited")
+
+	return cmd
+}
@@ -19,8 +19,9 @@ import (
 
 func newDisableCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "disable <cluster-name>",
-		Short: "Disable automatic enabling of TiDB clusters at boot",
+		Use:         "disable <cluster-name>",
+		Short:       "Disable automatic enabling of TiDB clusters at boot",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
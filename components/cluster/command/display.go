@@ -35,8 +35,9 @@ func newDisplayCmd() *cobra.Command {
 		dopt              manager.DisplayOption
 	)
 	cmd := &cobra.Command{
-		Use:   "display <cluster-name>",
-		Short: "Display information of a TiDB cluster",
+		Use:         "display <cluster-name>",
+		Short:       "Display information of a TiDB cluster",
+		Annotations: map[string]string{annotationMetaRead: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
@@ -75,6 +76,9 @@ func newDisplayCmd() *cobra.Command {
 			if showTiKVLabels {
 				return cm.DisplayTiKVLabels(dopt, gOpt)
 			}
+			if dopt.ShowCapacity {
+				return cm.DisplayCapacity(dopt, gOpt)
+			}
 			return cm.Display(dopt, gOpt)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -97,6 +101,8 @@ func newDisplayCmd() *cobra.Command {
 	cmd.Flags().BoolVar(&dopt.ShowManageHost, "manage-host", false, "display manage host of nodes")
 	cmd.Flags().BoolVar(&dopt.ShowNuma, "numa", false, "display numa information of nodes")
 	cmd.Flags().BoolVar(&dopt.ShowVersions, "versions", false, "display component version of instances")
+	cmd.Flags().BoolVar(&dopt.ShowCapacity, "capacity", false, "display disk capacity and usage of stores and hosts")
+	cmd.Flags().DurationVar(&dopt.CapacitySampleInterval, "capacity-sample-interval", 10*time.Second, "Sample region size from PD twice in the interval to estimate store growth and ETA, set to 0 to take a single sample")
 	cmd.Flags().Uint64Var(&statusTimeout, "status-timeout", 10, "Timeout in seconds when getting node status")
 
 	return cmd
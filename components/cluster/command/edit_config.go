@@ -21,9 +21,10 @@ import (
 func newEditConfigCmd() *cobra.Command {
 	opt := manager.EditConfigOptions{}
 	cmd := &cobra.Command{
-		Use:   "edit-config <cluster-name>",
-		Short: "Edit TiDB cluster config",
-		Long:  "Edit TiDB cluster config. Will use editor from environment variable `EDITOR`, default use vi",
+		Use:         "edit-config <cluster-name>",
+		Short:       "Edit TiDB cluster config",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
+		Long:        "Edit TiDB cluster config. Will use editor from environment variable `EDITOR`, default use vi",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
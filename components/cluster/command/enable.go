@@ -19,8 +19,9 @@ import (
 
 func newEnableCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "enable <cluster-name>",
-		Short: "Enable a TiDB cluster automatically at boot",
+		Use:         "enable <cluster-name>",
+		Short:       "Enable a TiDB cluster automatically at boot",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
@@ -14,6 +14,8 @@
 package command
 
 import (
+	"errors"
+
 	"github.com/pingcap/tiup/pkg/cluster/manager"
 	"github.com/spf13/cobra"
 )
@@ -21,9 +23,10 @@ import (
 func newExecCmd() *cobra.Command {
 	opt := manager.ExecOptions{}
 	cmd := &cobra.Command{
-		Use:    "exec <cluster-name>",
-		Short:  "Run shell command on host in the tidb cluster",
-		Hidden: true,
+		Use:         "exec <cluster-name>",
+		Short:       "Run shell command on host in the tidb cluster",
+		Annotations: map[string]string{annotationMetaRead: "0"},
+		Hidden:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
@@ -31,6 +34,14 @@ func newExecCmd() *cobra.Command {
 
 			clusterName := args[0]
 
+			if opt.Script != "" && cmd.Flags().Changed("command") {
+				return errors.New("--command and --script can not be used together")
+			}
+			// open a login shell by default in tty mode
+			if opt.TTY && !cmd.Flags().Changed("command") {
+				opt.Command = ""
+			}
+
 			return cm.Exec(clusterName, opt, gOpt)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -45,6 +56,9 @@ func newExecCmd() *cobra.Command {
 
 	cmd.Flags().StringVar(&opt.Command, "command", "ls", "the command run on cluster host")
 	cmd.Flags().BoolVar(&opt.Sudo, "sudo", false, "use root permissions (default false)")
+	cmd.Flags().StringVar(&opt.Script, "script", "", "the local script uploaded to and run on cluster host, instead of --command")
+	cmd.Flags().DurationVar(&opt.Timeout, "timeout", 0, "the timeout of running the command on each host (default 60s)")
+	cmd.Flags().BoolVar(&opt.TTY, "tty", false, "open an interactive session with a PTY on the host selected, runs --command in it if specified")
 	cmd.Flags().StringSliceVarP(&gOpt.Roles, "role", "R", nil, "Only exec on host with specified roles")
 	cmd.Flags().StringSliceVarP(&gOpt.Nodes, "node", "N", nil, "Only exec on host with specified nodes")
 
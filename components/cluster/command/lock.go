@@ -0,0 +1,88 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newLockCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lock",
+		Short: "Inspect and release the locks preventing concurrent operations",
+		Long: `Inspect and release the locks preventing concurrent operations.
+
+A cluster is locked while it is being operated, e.g. deployed, scaled,
+upgraded or reloaded, so that two operations can not change the cluster at
+the same time. The lock of the meta backend is also shown if the metadata is
+stored remotely. Locks left by exited processes on this machine are released
+automatically, the others have to be released manually.`,
+	}
+
+	cmd.AddCommand(
+		newLockStatusCmd(),
+		newLockReleaseCmd(),
+	)
+	return cmd
+}
+
+func newLockStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "status <cluster-name>",
+		Short:       "Show who is operating the cluster",
+		Annotations: map[string]string{annotationNoMetaCheckout: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return cmd.Help()
+			}
+
+			return cm.LockStatus(args[0])
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return shellCompGetClusterName(cm, toComplete)
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	return cmd
+}
+
+func newLockReleaseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "release <cluster-name>",
+		Short:       "Release the locks of the cluster left by interrupted operations",
+		Annotations: map[string]string{annotationNoMetaCheckout: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return cmd.Help()
+			}
+
+			return cm.ReleaseLock(args[0], skipConfirm)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return shellCompGetClusterName(cm, toComplete)
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	return cmd
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"github.com/pingcap/tiup/pkg/cluster/manager"
+	"github.com/spf13/cobra"
+)
+
+func newLogsCmd() *cobra.Command {
+	opt := manager.LogsOptions{}
+	cmd := &cobra.Command{
+		Use:         "logs <cluster-name>",
+		Short:       "Search and collect logs of instances in the cluster",
+		Annotations: map[string]string{annotationMetaRead: "0"},
+		Long: `Search and collect logs of instances in the cluster.
+
+Matching lines of all selected instances are printed with the instance as
+prefix, or the raw log files are packed into a tarball with --bundle:
+
+  tiup cluster logs <cluster-name> -R tikv --since 1h --grep "panic|slow"
+  tiup cluster logs <cluster-name> -R tidb --follow
+  tiup cluster logs <cluster-name> --since 24h --bundle logs.tar.gz`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return cmd.Help()
+			}
+			if opt.Follow && opt.Bundle != "" {
+				return cmd.Help()
+			}
+
+			return cm.Logs(args[0], opt, gOpt)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return shellCompGetClusterName(cm, toComplete)
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&gOpt.Roles, "role", "R", nil, "Only search logs of specified roles")
+	cmd.Flags().StringSliceVarP(&gOpt.Nodes, "node", "N", nil, "Only search logs of specified nodes")
+	cmd.Flags().DurationVar(&opt.Since, "since", 0, "Only show logs newer than a relative duration like 30m or 2h, rotated log files are included")
+	cmd.Flags().StringVar(&opt.Grep, "grep", "", "Only show lines matching the regular expression in Go (RE2) syntax")
+	cmd.Flags().BoolVarP(&opt.Follow, "follow", "f", false, "Keep printing new lines of the logs")
+	cmd.Flags().StringVar(&opt.Bundle, "bundle", "", "Pack the raw log files and metadata into the specified tarball instead of printing")
+
+	return cmd
+}
@@ -0,0 +1,72 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pingcap/tiup/pkg/cluster/manager"
+	"github.com/spf13/cobra"
+)
+
+func newMaintenanceCmd() *cobra.Command {
+	opt := manager.MaintenanceOptions{}
+	cmd := &cobra.Command{
+		Use:         "maintenance <cluster-name> on|off",
+		Short:       "Turn on or off the maintenance mode of the cluster",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
+		Long: `Turn on or off the maintenance mode of the cluster.
+
+When it's turned on, the alerts of the specified nodes (or the whole cluster)
+are silenced in Alertmanager, the balance schedulers of PD are paused and the
+store limit is set if specified. The changes expire after --duration even if
+the maintenance mode is not turned off, and they are reverted when it's turned
+off:
+
+  tiup cluster maintenance <cluster-name> on -N 10.0.1.1:20160 --duration 1h
+  tiup cluster maintenance <cluster-name> off`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return cmd.Help()
+			}
+
+			switch args[1] {
+			case "on":
+				return cm.MaintenanceOn(args[0], opt, gOpt)
+			case "off":
+				return cm.MaintenanceOff(args[0], gOpt)
+			default:
+				return fmt.Errorf("unknown action %s, should be on or off", args[1])
+			}
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return shellCompGetClusterName(cm, toComplete)
+			case 1:
+				return []string{"on", "off"}, cobra.ShellCompDirectiveNoFileComp
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&gOpt.Nodes, "node", "N", nil, "Only silence the alerts of the hosts of specified nodes")
+	cmd.Flags().DurationVar(&opt.Duration, "duration", 2*time.Hour, "How long the maintenance lasts")
+	cmd.Flags().IntVar(&opt.StoreLimit, "store-limit", 0, "Set the store limit of all stores during the maintenance, it's not changed if not specified")
+	cmd.Flags().StringVar(&opt.Comment, "comment", "", "Comment of the maintenance")
+
+	return cmd
+}
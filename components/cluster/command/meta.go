@@ -25,13 +25,29 @@ func newMetaCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "meta",
 		Short: "backup/restore meta information",
+		Long: `Backup/restore meta information.
+
+The metadata of clusters, including the SSH keys and TLS certificates, can be
+stored in a meta backend instead of plain files on the control machine, by
+setting the environment variable TIUP_CLUSTER_META_BACKEND to:
+
+    file:///path/to/dir                              encrypted on local disk
+    s3://bucket/prefix?endpoint=host:port&region=    S3-compatible storage
+    etcd://host1:2379,host2:2379/prefix              etcd
+
+The metadata is encrypted with the keyfile in TIUP_CLUSTER_META_KEYFILE or the
+passphrase in TIUP_CLUSTER_META_PASSPHRASE if any is set, it is required by
+the file backend. The metadata of a cluster is locked while a command is
+operating on it, so several operators can share the clusters safely. Existing
+clusters are uploaded to the backend the first time they are operated on.`,
 	}
 
 	var filePath string
 
 	var metaBackupCmd = &cobra.Command{
-		Use:   "backup <cluster-name>",
-		Short: "backup topology and other information of cluster",
+		Use:         "backup <cluster-name>",
+		Short:       "backup topology and other information of cluster",
+		Annotations: map[string]string{annotationMetaRead: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return fmt.Errorf("please input cluster-name")
@@ -49,8 +65,9 @@ func newMetaCmd() *cobra.Command {
 	metaBackupCmd.Flags().StringVar(&filePath, "file", "", "filepath of output tarball")
 
 	var metaRestoreCmd = &cobra.Command{
-		Use:   "restore <cluster-name> <backup-file>",
-		Short: "restore topology and other information of cluster",
+		Use:         "restore <cluster-name> <backup-file>",
+		Short:       "restore topology and other information of cluster",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 2 {
 				return fmt.Errorf("please input cluster-name and backup-file")
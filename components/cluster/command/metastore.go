@@ -0,0 +1,139 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/tui"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// metaStoreMu guards committing the metadata from the signal handler
+var metaStoreMu sync.Mutex
+
+const (
+	// annotationNoMetaCheckout marks the commands not to check out the
+	// metadata from the meta backend, e.g. the commands inspecting the locks
+	annotationNoMetaCheckout = "no-meta-checkout"
+	// annotationMetaRead and annotationMetaWrite declare the arguments which
+	// are the clusters the command reads or changes, in comma separated
+	// indexes like "0,1", the last one may be suffixed by "..." to include
+	// the arguments after it. The clusters changed are locked in the meta
+	// backend and committed back after the command, the ones only read are
+	// checked out as read only working copies.
+	annotationMetaRead  = "meta-read"
+	annotationMetaWrite = "meta-write"
+)
+
+// checkoutClusterMeta fetches the metadata of the clusters the command
+// operates on from the meta backend, if one is configured
+func checkoutClusterMeta(cmd *cobra.Command, args []string) error {
+	if metaStore == nil || cmd.Annotations[annotationNoMetaCheckout] != "" {
+		return nil
+	}
+	ctx := context.Background()
+	if cmd.Parent() == rootCmd && cmd.Name() == "list" {
+		return metaStore.CheckoutAll(ctx)
+	}
+
+	writes := clusterNameArgs(cmd.Annotations[annotationMetaWrite], args)
+	info := spec.NewOperationLock(tui.OsArgs())
+	for _, name := range writes {
+		if err := metaStore.Checkout(ctx, name, true, info); err != nil {
+			return err
+		}
+	}
+	for _, name := range clusterNameArgs(cmd.Annotations[annotationMetaRead], args) {
+		if slices.Contains(writes, name) {
+			continue
+		}
+		if err := metaStore.Checkout(ctx, name, false, spec.OperationLock{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commitClusterMeta saves the metadata checked out by checkoutClusterMeta
+// back to the meta backend, it is called once either after the command or
+// when the process is interrupted
+func commitClusterMeta() error {
+	metaStoreMu.Lock()
+	defer metaStoreMu.Unlock()
+	if metaStore == nil {
+		return nil
+	}
+	defer func() {
+		_ = metaStore.Close()
+		metaStore = nil
+	}()
+	return metaStore.Commit(context.Background())
+}
+
+// trapSignals commits the metadata and exits if the process is interrupted,
+// so that the locks in the meta backend are released and the decrypted
+// working copies are not left on disk
+func trapSignals() {
+	sc := make(chan os.Signal, 1)
+	signal.Notify(sc, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-sc
+		zap.L().Info("Commit cluster metadata on signal", zap.String("signal", sig.String()))
+		if err := commitClusterMeta(); err != nil {
+			fmt.Fprintf(os.Stderr, "Commit cluster metadata failed: %s\n", err)
+		}
+		os.Exit(1)
+	}()
+}
+
+// clusterNameArgs returns the cluster names in the arguments at the indexes
+// declared by the annotation of the command
+func clusterNameArgs(indexes string, args []string) []string {
+	var names []string
+	add := func(i int) {
+		// invalid names are reported by the command itself
+		if i >= len(args) || clusterutil.ValidateClusterNameOrError(args[i]) != nil ||
+			slices.Contains(names, args[i]) {
+			return
+		}
+		names = append(names, args[i])
+	}
+	for _, field := range strings.Split(indexes, ",") {
+		if field == "" {
+			continue
+		}
+		index, rest := strings.CutSuffix(field, "...")
+		i, err := strconv.Atoi(index)
+		if err != nil {
+			continue
+		}
+		add(i)
+		for i++; rest && i < len(args); i++ {
+			add(i)
+		}
+	}
+	return names
+}
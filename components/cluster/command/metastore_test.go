@@ -0,0 +1,57 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterNameArgs(t *testing.T) {
+	args := []string{"central", "member-1", "../invalid", "member-1", "member-2"}
+	assert.Nil(t, clusterNameArgs("", args))
+	assert.Equal(t, []string{"central"}, clusterNameArgs("0", args))
+	assert.Equal(t, []string{"central", "member-1"}, clusterNameArgs("0,1", args))
+	assert.Equal(t, []string{"member-1", "member-2"}, clusterNameArgs("1...", args))
+	assert.Equal(t, []string{"central", "member-1", "member-2"}, clusterNameArgs("0...", args))
+	// the arguments not given are ignored
+	assert.Equal(t, []string{"central"}, clusterNameArgs("0,9", args[:1]))
+}
+
+func TestMetaAnnotations(t *testing.T) {
+	clusterArg := regexp.MustCompile(`<[^>]*cluster[^>]*>`)
+	index := regexp.MustCompile(`^\d+(\.\.\.)?$`)
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+		if !clusterArg.MatchString(cmd.Use) || cmd.Annotations[annotationNoMetaCheckout] != "" {
+			return
+		}
+		read, write := cmd.Annotations[annotationMetaRead], cmd.Annotations[annotationMetaWrite]
+		assert.True(t, read != "" || write != "", "%s does not declare the clusters it accesses", cmd.CommandPath())
+		for _, field := range strings.Split(read+","+write, ",") {
+			if field != "" {
+				assert.Regexp(t, index, field, cmd.CommandPath())
+			}
+		}
+	}
+	walk(rootCmd)
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/spf13/cobra"
+)
+
+func newMonitorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "Manage the monitoring of clusters",
+	}
+
+	cmd.AddCommand(newMonitorFederateCmd())
+	return cmd
+}
+
+func newMonitorFederateCmd() *cobra.Command {
+	mode := spec.FederationModeRemoteWrite
+	cmd := &cobra.Command{
+		Use:         "federate <central-cluster> <member-cluster>...",
+		Short:       "Collect metrics of member clusters into the central cluster",
+		Annotations: map[string]string{annotationMetaWrite: "0..."},
+		Long: `Collect metrics of member clusters into the Prometheus servers of the central cluster.
+
+With --mode remote-write, the Prometheus servers of member clusters push metrics
+to the central ones. With --mode federate, the central Prometheus servers scrape
+the /federate endpoint of the ones of member clusters. The metrics are labeled
+with tidb_cluster=<member-cluster>, and tidb_cluster=<central-cluster> for the
+central cluster itself, which is selectable in the dashboards of the central
+cluster.
+
+The topologies of the clusters are updated, and Prometheus servers of them and
+Grafana servers of the central cluster are reloaded:
+
+  tiup cluster monitor federate central prod-1 prod-2
+  tiup cluster monitor federate central prod-3 --mode federate`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) < 2 {
+				return cmd.Help()
+			}
+
+			return cm.MonitorFederate(args[0], args[1:], mode, gOpt, skipConfirm)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return shellCompGetClusterName(cm, toComplete)
+		},
+	}
+
+	cmd.Flags().StringVar(&mode, "mode", mode, "How metrics are collected, remote-write or federate")
+
+	return cmd
+}
@@ -24,8 +24,9 @@ func newPatchCmd() *cobra.Command {
 		offlineMode bool
 	)
 	cmd := &cobra.Command{
-		Use:   "patch <cluster-name> <package-path>",
-		Short: "Replace the remote package with a specified package and restart the service",
+		Use:         "patch <cluster-name> <package-path>",
+		Short:       "Replace the remote package with a specified package and restart the service",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 2 {
 				return cmd.Help()
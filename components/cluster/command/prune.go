@@ -19,8 +19,9 @@ import (
 
 func newPruneCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "prune <cluster-name>",
-		Short: "Destroy and remove instances that is in tombstone state",
+		Use:         "prune <cluster-name>",
+		Short:       "Destroy and remove instances that is in tombstone state",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
@@ -24,8 +24,9 @@ import (
 func newReloadCmd() *cobra.Command {
 	var skipRestart bool
 	cmd := &cobra.Command{
-		Use:   "reload <cluster-name>",
-		Short: "Reload a TiDB cluster's config and restart if needed",
+		Use:         "reload <cluster-name>",
+		Short:       "Reload a TiDB cluster's config and restart if needed",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
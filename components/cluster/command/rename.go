@@ -19,8 +19,9 @@ import (
 
 func newRenameCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "rename <old-cluster-name> <new-cluster-name>",
-		Short: "Rename the cluster",
+		Use:         "rename <old-cluster-name> <new-cluster-name>",
+		Short:       "Rename the cluster",
+		Annotations: map[string]string{annotationMetaWrite: "0,1"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 2 {
 				return cmd.Help()
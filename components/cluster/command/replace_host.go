@@ -0,0 +1,82 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/pingcap/tiup/pkg/cluster/manager"
+	"github.com/pingcap/tiup/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+func newReplaceHostCmd() *cobra.Command {
+	opt := manager.ReplaceHostOptions{
+		Deploy: manager.DeployOptions{
+			IdentityFile: filepath.Join(utils.UserHome(), ".ssh", "id_rsa"),
+		},
+	}
+	cmd := &cobra.Command{
+		Use:         "replace-host <cluster-name> <old-host> <new-host>",
+		Short:       "Replace all instances on a host with the same instances on a new host",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
+		Long: `Replace all instances on a host with the same instances on a new host.
+
+The instances on the old host are cloned to the new host with the same ports,
+directories, labels and other settings, then the cloned instances are scaled
+out, the old instances are scaled in and pruned after their data are migrated,
+and the monitoring is refreshed at last. If it is interrupted, run it again
+with the same hosts to resume.
+
+Use --force when the old host is permanently offline, the old instances are
+removed from the cluster directly without migrating their data.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 3 {
+				return cmd.Help()
+			}
+
+			return cm.ReplaceHost(
+				args[0],
+				args[1],
+				args[2],
+				opt,
+				postScaleOutHook,
+				final,
+				scaleInHook,
+				skipConfirm,
+				gOpt,
+			)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return shellCompGetClusterName(cm, toComplete)
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&opt.Deploy.User, "user", "u", utils.CurrentUser(), "The user name to login via SSH. The user must has root (or sudo) privilege.")
+	cmd.Flags().StringVarP(&opt.Deploy.IdentityFile, "identity_file", "i", opt.Deploy.IdentityFile, "The path of the SSH identity file. If specified, public key authentication will be used.")
+	cmd.Flags().BoolVarP(&opt.Deploy.UsePassword, "password", "p", false, "Use password of target hosts. If specified, password authentication will be used.")
+	cmd.Flags().BoolVarP(&opt.Deploy.NoLabels, "no-labels", "", false, "Don't check TiKV labels")
+	cmd.Flags().Uint64Var(&gOpt.APITimeout, "transfer-timeout", 600, "Timeout in seconds when transferring PD and TiKV store leaders, also for TiCDC drain one capture")
+	cmd.Flags().BoolVar(&opt.Force, "force", false, "Force scale in the old instances without waiting for their data to be migrated, only use it when the old host is permanently offline")
+	cmd.Flags().DurationVar(&opt.Interval, "interval", 10*time.Second, "Interval to poll the progress of offlining the old instances")
+
+	return cmd
+}
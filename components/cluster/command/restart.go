@@ -14,13 +14,21 @@
 package command
 
 import (
+	"time"
+
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
 	"github.com/spf13/cobra"
 )
 
 func newRestartCmd() *cobra.Command {
+	var (
+		safe bool
+		gate operator.RegionHealthGate
+	)
 	cmd := &cobra.Command{
-		Use:   "restart <cluster-name>",
-		Short: "Restart a TiDB cluster",
+		Use:         "restart <cluster-name>",
+		Short:       "Restart a TiDB cluster",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
@@ -32,6 +40,9 @@ func newRestartCmd() *cobra.Command {
 
 			clusterName := args[0]
 
+			if safe {
+				return cm.SafeRestartCluster(clusterName, gate, gOpt, skipConfirm)
+			}
 			return cm.RestartCluster(clusterName, gOpt, skipConfirm)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -46,6 +57,13 @@ func newRestartCmd() *cobra.Command {
 
 	cmd.Flags().StringSliceVarP(&gOpt.Roles, "role", "R", nil, "Only restart specified roles")
 	cmd.Flags().StringSliceVarP(&gOpt.Nodes, "node", "N", nil, "Only restart specified nodes")
+	cmd.Flags().BoolVar(&safe, "safe", false, "Restart instances one by one, and wait for the regions to be healthy between restarting two TiKV stores")
+	cmd.Flags().Uint64Var(&gOpt.APITimeout, "transfer-timeout", 600, "Timeout in seconds when transferring PD and TiKV store leaders, used with --safe")
+	cmd.Flags().IntVar(&gate.MaxMissPeer, "max-miss-peer", 0, "Max number of regions missing peers allowed to restart the next store, used with --safe")
+	cmd.Flags().IntVar(&gate.MaxDownPeer, "max-down-peer", 0, "Max number of regions with down peers allowed to restart the next store, used with --safe")
+	cmd.Flags().IntVar(&gate.MaxPendingPeer, "max-pending-peer", 0, "Max number of regions with pending peers allowed to restart the next store, used with --safe")
+	cmd.Flags().DurationVar(&gate.MaxWait, "max-wait", 30*time.Minute, "Max time to wait for the regions to be healthy after restarting a store, used with --safe")
+	cmd.Flags().DurationVar(&gate.Interval, "check-interval", 10*time.Second, "Interval to check the regions, used with --safe")
 
 	return cmd
 }
@@ -28,6 +28,7 @@ import (
 	perrs "github.com/pingcap/errors"
 	"github.com/pingcap/tiup/pkg/cluster/executor"
 	"github.com/pingcap/tiup/pkg/cluster/manager"
+	"github.com/pingcap/tiup/pkg/cluster/metastore"
 	operator "github.com/pingcap/tiup/pkg/cluster/operation"
 	"github.com/pingcap/tiup/pkg/cluster/spec"
 	tiupmeta "github.com/pingcap/tiup/pkg/environment"
@@ -55,8 +56,9 @@ var (
 )
 
 var (
-	tidbSpec *spec.SpecManager
-	cm       *manager.Manager
+	tidbSpec  *spec.SpecManager
+	cm        *manager.Manager
+	metaStore *metastore.Store
 )
 
 func init() {
@@ -93,6 +95,18 @@ func init() {
 			cm = manager.NewManager("tidb", tidbSpec, log)
 			if cmd.Name() != "__complete" {
 				logger.EnableAuditLog(spec.AuditDir())
+
+				if metaStore, err = metastore.FromEnv(spec.ProfilePath(spec.TiUPClusterDir)); err != nil {
+					return err
+				}
+				if metaStore != nil {
+					spec.SetClusterBaseDir(metaStore.WorkDir())
+					cm.SetMetaLocker(metaStore)
+					trapSignals()
+				}
+				if err := checkoutClusterMeta(cmd, args); err != nil {
+					return err
+				}
 			}
 
 			// Running in other OS/ARCH Should be fine we only download manifest file.
@@ -168,16 +182,20 @@ func init() {
 		newStopCmd(),
 		newRestartCmd(),
 		newScaleInCmd(),
+		newReplaceHostCmd(),
 		newScaleOutCmd(),
 		newDestroyCmd(),
 		newCleanCmd(),
 		newUpgradeCmd(),
 		newDisplayCmd(),
+		newWatchCmd(),
 		newPruneCmd(),
 		newListCmd(),
 		newAuditCmd(),
 		newEditConfigCmd(),
 		newShowConfigCmd(),
+		newDiffCmd(),
+		newCloneCmd(),
 		newReloadCmd(),
 		newPatchCmd(),
 		newRenameCmd(),
@@ -186,11 +204,18 @@ func init() {
 		newExecCmd(),
 		newPullCmd(),
 		newPushCmd(),
+		newLogsCmd(),
+		newDiagCmd(),
+		newApplyCmd(),
+		newAlertCmd(),
+		newMaintenanceCmd(),
+		newMonitorCmd(),
 		newTestCmd(), // hidden command for test internally
 		newReplayCmd(),
 		newTemplateCmd(),
 		newTLSCmd(),
 		newMetaCmd(),
+		newLockCmd(),
 		newRotateSSHCmd(),
 	)
 }
@@ -318,6 +343,13 @@ func Execute() {
 
 	code := 0
 	err := rootCmd.Execute()
+	if cerr := commitClusterMeta(); cerr != nil {
+		if err == nil {
+			err = cerr
+		} else {
+			zap.L().Warn("Commit cluster metadata failed", zap.Error(cerr))
+		}
+	}
 	if err != nil {
 		code = 1
 	}
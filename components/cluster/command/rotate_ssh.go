@@ -19,8 +19,9 @@ import (
 
 func newRotateSSHCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "rotatessh <cluster-name>",
-		Short: "rotate ssh keys on all nodes",
+		Use:         "rotatessh <cluster-name>",
+		Short:       "rotate ssh keys on all nodes",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
@@ -15,6 +15,9 @@ package command
 
 import (
 	"crypto/tls"
+	"time"
+
+	"github.com/pingcap/tiup/pkg/cluster/manager"
 
 	operator "github.com/pingcap/tiup/pkg/cluster/operation"
 	"github.com/pingcap/tiup/pkg/cluster/spec"
@@ -23,9 +26,20 @@ import (
 )
 
 func newScaleInCmd() *cobra.Command {
+	var statusOpt manager.ScaleInStatusOptions
 	cmd := &cobra.Command{
-		Use:   "scale-in <cluster-name>",
-		Short: "Scale in a TiDB cluster",
+		Use:         "scale-in <cluster-name>",
+		Short:       "Scale in a TiDB cluster",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
+		Long: `Scale in a TiDB cluster.
+
+TiKV, TiFlash and binlog nodes are offlined asynchronously, use --wait to wait
+until they are offline and prune them. The progress of the nodes being offlined,
+including the remaining regions, the migration rate and ETA of each store, can
+be shown with the status subcommand at any time:
+
+  tiup cluster scale-in <cluster-name> -N 172.16.5.1:20160 --wait
+  tiup cluster scale-in status <cluster-name> [--wait]`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
@@ -33,20 +47,15 @@ func newScaleInCmd() *cobra.Command {
 
 			clusterName := args[0]
 
-			scale := func(b *task.Builder, imetadata spec.Metadata, tlsCfg *tls.Config) {
-				metadata := imetadata.(*spec.ClusterMeta)
-
-				nodes := gOpt.Nodes
-				if !gOpt.Force {
-					nodes = operator.AsyncNodes(metadata.Topology, nodes, false)
-				}
-
-				b.ClusterOperate(metadata.Topology, operator.ScaleInOperation, gOpt, tlsCfg).
-					UpdateMeta(clusterName, metadata, nodes).
-					UpdateTopology(clusterName, tidbSpec.Path(clusterName), metadata, nodes)
+			if err := cm.ScaleIn(clusterName, skipConfirm, gOpt, scaleInHook(clusterName, gOpt)); err != nil {
+				return err
 			}
-
-			return cm.ScaleIn(clusterName, skipConfirm, gOpt, scale)
+			if !statusOpt.Wait || gOpt.Force {
+				return nil
+			}
+			// the nodes are confirmed to be destroyed already, prune them
+			// without asking again once they become Tombstone
+			return cm.ScaleInStatus(clusterName, statusOpt, gOpt, true)
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 			switch len(args) {
@@ -61,8 +70,60 @@ func newScaleInCmd() *cobra.Command {
 	cmd.Flags().StringSliceVarP(&gOpt.Nodes, "node", "N", nil, "Specify the nodes (required)")
 	cmd.Flags().Uint64Var(&gOpt.APITimeout, "transfer-timeout", 600, "Timeout in seconds when transferring PD and TiKV store leaders, also for TiCDC drain one capture")
 	cmd.Flags().BoolVar(&gOpt.Force, "force", false, "Force just try stop and destroy instance before removing the instance from topo")
+	cmd.Flags().BoolVar(&statusOpt.Wait, "wait", false, "Wait until the TiKV, TiFlash and binlog nodes are offline and prune them")
+	cmd.Flags().DurationVar(&statusOpt.Interval, "interval", 10*time.Second, "Interval to poll the progress of offlining when --wait is set")
 
 	_ = cmd.MarkFlagRequired("node")
 
+	cmd.AddCommand(newScaleInStatusCmd())
+
+	return cmd
+}
+
+func newScaleInStatusCmd() *cobra.Command {
+	var opt manager.ScaleInStatusOptions
+	cmd := &cobra.Command{
+		Use:         "status <cluster-name>",
+		Short:       "Show the progress of the nodes being offlined by scale-in",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
+		Long: `Show the progress of the TiKV, TiFlash and binlog nodes being offlined
+by scale-in, including the remaining regions, the migration rate and ETA of
+each store. The nodes are pruned once they become Tombstone.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return cmd.Help()
+			}
+
+			return cm.ScaleInStatus(args[0], opt, gOpt, skipConfirm)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return shellCompGetClusterName(cm, toComplete)
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&opt.Wait, "wait", false, "Keep polling until all the nodes are offline and pruned")
+	cmd.Flags().DurationVar(&opt.Interval, "interval", 10*time.Second, "Interval to poll the progress, the migration rate is sampled within it")
+
 	return cmd
 }
+
+// scaleInHook returns the tasks to scale in the nodes in options
+func scaleInHook(clusterName string, gOpt operator.Options) func(b *task.Builder, imetadata spec.Metadata, tlsCfg *tls.Config) {
+	return func(b *task.Builder, imetadata spec.Metadata, tlsCfg *tls.Config) {
+		metadata := imetadata.(*spec.ClusterMeta)
+
+		nodes := gOpt.Nodes
+		if !gOpt.Force {
+			nodes = operator.AsyncNodes(metadata.Topology, nodes, false)
+		}
+
+		b.ClusterOperate(metadata.Topology, operator.ScaleInOperation, gOpt, tlsCfg).
+			UpdateMeta(clusterName, metadata, nodes).
+			UpdateTopology(clusterName, tidbSpec.Path(clusterName), metadata, nodes)
+	}
+}
@@ -31,6 +31,7 @@ func newScaleOutCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:          "scale-out <cluster-name> [topology.yaml]",
 		Short:        "Scale out a TiDB cluster",
+		Annotations:  map[string]string{annotationMetaWrite: "0"},
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var (
@@ -83,6 +84,7 @@ func newScaleOutCmd() *cobra.Command {
 	cmd.Flags().StringVarP(&opt.IdentityFile, "identity_file", "i", opt.IdentityFile, "The path of the SSH identity file. If specified, public key authentication will be used.")
 	cmd.Flags().BoolVarP(&opt.UsePassword, "password", "p", false, "Use password of target hosts. If specified, password authentication will be used.")
 	cmd.Flags().BoolVarP(&opt.NoLabels, "no-labels", "", false, "Don't check TiKV labels")
+	cmd.Flags().BoolVar(&opt.NoRollback, "no-rollback", false, "Keep the changes made on hosts when failed, the completed steps are undone by default")
 	cmd.Flags().BoolVarP(&opt.Stage1, "stage1", "", false, "Don't start the new instance after scale-out, need to manually execute cluster scale-out --stage2")
 	cmd.Flags().BoolVarP(&opt.Stage2, "stage2", "", false, "Start the new instance and init config after scale-out --stage1")
 
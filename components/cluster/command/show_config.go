@@ -19,8 +19,9 @@ import (
 
 func newShowConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "show-config <cluster-name>",
-		Short: "Show TiDB cluster config",
+		Use:         "show-config <cluster-name>",
+		Short:       "Show TiDB cluster config",
+		Annotations: map[string]string{annotationMetaRead: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
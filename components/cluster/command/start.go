@@ -37,8 +37,9 @@ func newStartCmd() *cobra.Command {
 	)
 
 	cmd := &cobra.Command{
-		Use:   "start <cluster-name>",
-		Short: "Start a TiDB cluster",
+		Use:         "start <cluster-name>",
+		Short:       "Start a TiDB cluster",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
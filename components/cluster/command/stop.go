@@ -21,8 +21,9 @@ func newStopCmd() *cobra.Command {
 	var evictLeader bool
 
 	cmd := &cobra.Command{
-		Use:   "stop <cluster-name>",
-		Short: "Stop a TiDB cluster",
+		Use:         "stop <cluster-name>",
+		Short:       "Stop a TiDB cluster",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 1 {
 				return cmd.Help()
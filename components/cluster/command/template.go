@@ -28,6 +28,7 @@ import (
 type TemplateOptions struct {
 	Full    bool // print full template
 	MultiDC bool // print template for deploying to multiple data center
+	Monitor bool // print template for deploying monitoring components only
 	Local   bool // print and render local template
 }
 
@@ -70,8 +71,8 @@ func newTemplateCmd() *cobra.Command {
 		Use:   "template",
 		Short: "Print topology template",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if sumBool(opt.Full, opt.MultiDC, opt.Local) > 1 {
-				return errors.New("at most one of 'full', 'multi-dc', or 'local' can be specified")
+			if sumBool(opt.Full, opt.MultiDC, opt.Monitor, opt.Local) > 1 {
+				return errors.New("at most one of 'full', 'multi-dc', 'monitoring-only', or 'local' can be specified")
 			}
 			name := "minimal.yaml"
 			switch {
@@ -79,6 +80,8 @@ func newTemplateCmd() *cobra.Command {
 				name = "topology.example.yaml"
 			case opt.MultiDC:
 				name = "multi-dc.yaml"
+			case opt.Monitor:
+				name = "monitoring-only.yaml"
 			case opt.Local:
 				name = "local.tpl"
 			}
@@ -119,6 +122,7 @@ func newTemplateCmd() *cobra.Command {
 
 	cmd.Flags().BoolVar(&opt.Full, "full", false, "Print the full topology template for TiDB cluster.")
 	cmd.Flags().BoolVar(&opt.MultiDC, "multi-dc", false, "Print template for deploying to multiple data center.")
+	cmd.Flags().BoolVar(&opt.Monitor, "monitoring-only", false, "Print template for deploying monitoring components for a cluster not managed by TiUP.")
 	cmd.Flags().BoolVar(&opt.Local, "local", false, "Print and render template for deploying a simple cluster locally.")
 
 	// template values for rendering
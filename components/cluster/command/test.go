@@ -32,9 +32,10 @@ import (
 
 func newTestCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:    "_test <cluster-name>",
-		Short:  "test toolkit",
-		Hidden: true,
+		Use:         "_test <cluster-name>",
+		Short:       "test toolkit",
+		Hidden:      true,
+		Annotations: map[string]string{annotationMetaRead: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) < 1 {
 				return cmd.Help()
@@ -28,8 +28,9 @@ func newTLSCmd() *cobra.Command {
 	)
 
 	cmd := &cobra.Command{
-		Use:   "tls <cluster-name> <enable/disable>",
-		Short: "Enable/Disable TLS between TiDB components",
+		Use:         "tls <cluster-name> <enable/disable>",
+		Short:       "Enable/Disable TLS between TiDB components",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 2 {
 				return cmd.Help()
@@ -28,9 +28,10 @@ import (
 func newPullCmd() *cobra.Command {
 	opt := manager.TransferOptions{Pull: true}
 	cmd := &cobra.Command{
-		Use:    "pull <cluster-name> <remote-path> <local-path>",
-		Short:  "(EXPERIMENTAL) Transfer files or directories from host in the tidb cluster to local",
-		Hidden: true,
+		Use:         "pull <cluster-name> <remote-path> <local-path>",
+		Short:       "(EXPERIMENTAL) Transfer files or directories from host in the tidb cluster to local",
+		Annotations: map[string]string{annotationMetaRead: "0"},
+		Hidden:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 3 {
 				return cmd.Help()
@@ -55,9 +56,10 @@ func newPullCmd() *cobra.Command {
 func newPushCmd() *cobra.Command {
 	opt := manager.TransferOptions{Pull: false}
 	cmd := &cobra.Command{
-		Use:    "push <cluster-name> <local-path> <remote-path>",
-		Short:  "(EXPERIMENTAL) Transfer files or directories from local to host in the tidb cluster",
-		Hidden: true,
+		Use:         "push <cluster-name> <local-path> <remote-path>",
+		Short:       "(EXPERIMENTAL) Transfer files or directories from local to host in the tidb cluster",
+		Annotations: map[string]string{annotationMetaRead: "0"},
+		Hidden:      true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 3 {
 				return cmd.Help()
@@ -28,8 +28,9 @@ func newUpgradeCmd() *cobra.Command {
 	var restartTimeout time.Duration
 
 	cmd := &cobra.Command{
-		Use:   "upgrade <cluster-name> <version>",
-		Short: "Upgrade a specified TiDB cluster",
+		Use:         "upgrade <cluster-name> <version>",
+		Short:       "Upgrade a specified TiDB cluster",
+		Annotations: map[string]string{annotationMetaWrite: "0"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) != 2 {
 				return cmd.Help()
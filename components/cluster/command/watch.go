@@ -0,0 +1,77 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"time"
+
+	"github.com/pingcap/tiup/pkg/cluster/manager"
+	"github.com/spf13/cobra"
+)
+
+func newWatchCmd() *cobra.Command {
+	opt := manager.WatchOptions{}
+	var backoff []string
+	cmd := &cobra.Command{
+		Use:         "watch <cluster-name>",
+		Short:       "Watch the instances and report the ones crashing in a loop or staying down",
+		Annotations: map[string]string{annotationMetaRead: "0"},
+		Long: `Watch the instances of the cluster by polling the systemd services and the
+status APIs. The instances restarting in a loop or staying down past the
+threshold are reported with snippets of their journals, the events are
+recorded in the cluster directory and sent to the hooks if specified.
+The topology is reloaded every round, the instances being scaled in and the
+ones in the maintenance window are not watched.
+
+With --auto-recover, the instances staying down are started again, the
+attempts are delayed by the per-role backoff policies and made holding the
+operation lock of the cluster, e.g.:
+
+  tiup cluster watch <cluster-name> --auto-recover --backoff tikv=1m:30m --backoff tidb=10s`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return cmd.Help()
+			}
+
+			var err error
+			if opt.Backoff, err = manager.ParseBackoffPolicies(backoff); err != nil {
+				return err
+			}
+
+			return cm.Watch(args[0], opt, gOpt)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return shellCompGetClusterName(cm, toComplete)
+			default:
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&gOpt.Roles, "role", "R", nil, "Only watch specified roles")
+	cmd.Flags().StringSliceVarP(&gOpt.Nodes, "node", "N", nil, "Only watch specified nodes")
+	cmd.Flags().DurationVar(&opt.Interval, "interval", 30*time.Second, "Interval between two polls")
+	cmd.Flags().IntVar(&opt.FlapCount, "flap-count", 3, "Report an instance as flapping if it restarts this many times within --flap-window")
+	cmd.Flags().DurationVar(&opt.FlapWindow, "flap-window", 10*time.Minute, "The window to count the restarts of an instance")
+	cmd.Flags().DurationVar(&opt.DownThreshold, "down-threshold", 2*time.Minute, "Report an instance as down if it stays down longer than this")
+	cmd.Flags().BoolVar(&opt.AutoRecover, "auto-recover", false, "Start the instances staying down past --down-threshold")
+	cmd.Flags().StringSliceVar(&backoff, "backoff", nil, "The per-role backoff policy of recovering in format of role=initial[:max] (default 30s:10m)")
+	cmd.Flags().IntVar(&opt.JournalLines, "journal-lines", 20, "The number of journal lines attached to the events")
+	cmd.Flags().StringVar(&opt.Webhook, "webhook", "", "The URL to post the events of flapping and down instances to")
+	cmd.Flags().StringVar(&opt.Hook, "hook", "", "The local script to run with the events of flapping and down instances, the event is passed by stdin in JSON")
+
+	return cmd
+}
@@ -649,6 +649,14 @@ func (s *Specification) Validate() error {
 		return err
 	}
 
+	if err := spec.ValidateResourceControl(s); err != nil {
+		return err
+	}
+
+	if err := spec.ValidateGrafanaProvisioning(s); err != nil {
+		return err
+	}
+
 	return spec.RelativePathDetect(s, isSkipField)
 }
 
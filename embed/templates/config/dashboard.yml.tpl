@@ -7,4 +7,14 @@ providers:
     editable: true
     updateIntervalSeconds: 30
     options:
-      path: {{.DeployDir}}/dashboards
\ No newline at end of file
+      path: {{.DeployDir}}/dashboards
+{{- range .Folders}}
+  - name: {{printf "%s-%s" $.ClusterName .Name | printf "%q"}}
+    folder: {{printf "%q" .Name}}
+    type: file
+    disableDeletion: false
+    editable: true
+    updateIntervalSeconds: 30
+    options:
+      path: {{.Path}}
+{{- end}}
@@ -11,4 +11,7 @@ datasources:
     tlsAuthWithCACert: false
     version: 1
     editable: true
+{{- if .JSONData}}
+    jsonData: {{toJSON .JSONData}}
+{{- end}}
 {{- end}}
\ No newline at end of file
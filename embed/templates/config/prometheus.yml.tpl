@@ -538,6 +538,22 @@ scrape_configs:
     {{- end}}
 {{- end}}
 
+{{- range .FederateJobs}}
+  - job_name: "federate-{{.Cluster}}"
+    honor_labels: true # keep the labels of the federated metrics
+    metrics_path: /federate
+    params:
+      'match[]':
+        - '{job=~".+"}'
+    static_configs:
+    - targets:
+    {{- range .Targets}}
+      - '{{.}}'
+    {{- end}}
+      labels:
+        {{.Label}}: '{{.Cluster}}'
+{{- end}}
+
 {{- if .RemoteConfig}}
 {{.RemoteConfig}}
 {{- end}}
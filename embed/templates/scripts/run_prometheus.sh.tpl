@@ -49,6 +49,9 @@ exec bin/prometheus/prometheus \
 {{- if .EnablePromAgentMode}}
     --enable-feature=agent \
 {{- end}}
+{{- if .EnableRemoteWriteReceiver}}
+    --web.enable-remote-write-receiver \
+{{- end}}
 {{- if .AdditionalArgs}}
 {{- range .AdditionalArgs}}
     {{.}} \
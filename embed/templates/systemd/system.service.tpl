@@ -6,20 +6,57 @@ After=syslog.target network.target remote-fs.target nss-lookup.target
 {{- if .MemoryLimit}}
 MemoryLimit={{.MemoryLimit}}
 {{- end}}
+{{- if .MemoryHigh}}
+MemoryHigh={{.MemoryHigh}}
+{{- end}}
+{{- if .MemorySwapMax}}
+MemorySwapMax={{.MemorySwapMax}}
+{{- end}}
 {{- if .CPUQuota}}
 CPUQuota={{.CPUQuota}}
 {{- end}}
+{{- if .AllowedCPUs}}
+AllowedCPUs={{.AllowedCPUs}}
+{{- end}}
+{{- if .CPUAffinity}}
+CPUAffinity={{.CPUAffinity}}
+{{- end}}
 {{- if .IOReadBandwidthMax}}
 IOReadBandwidthMax={{.IOReadBandwidthMax}}
 {{- end}}
 {{- if .IOWriteBandwidthMax}}
 IOWriteBandwidthMax={{.IOWriteBandwidthMax}}
 {{- end}}
+{{- if .IODeviceWeight}}
+IODeviceWeight={{.IODeviceWeight}}
+{{- end}}
+{{- if .TasksMax}}
+TasksMax={{.TasksMax}}
+{{- end}}
 {{- if .LimitCORE}}
 LimitCORE={{.LimitCORE}}
 {{- end}}
+{{- if .LimitNOFILE}}
+LimitNOFILE={{.LimitNOFILE}}
+{{- else}}
 LimitNOFILE=1000000
+{{- end}}
 LimitSTACK=10485760
+{{- if .OOMScoreAdjust}}
+OOMScoreAdjust={{.OOMScoreAdjust}}
+{{- end}}
+{{- if .ProtectSystem}}
+ProtectSystem={{.ProtectSystem}}
+{{- if eq .ProtectSystem "strict"}}
+ReadWritePaths={{range $i, $p := .ReadWritePaths}}{{if $i}} {{end}}{{$p}}{{end}}
+{{- end}}
+{{- end}}
+{{- if .PrivateTmp}}
+PrivateTmp=yes
+{{- end}}
+{{- if .NoNewPrivileges}}
+NoNewPrivileges=yes
+{{- end}}
 
 {{- if and .GrantCapNetRaw (eq .SystemdMode "system")}}
 AmbientCapabilities=CAP_NET_RAW
@@ -27,6 +64,9 @@ AmbientCapabilities=CAP_NET_RAW
 {{- if eq .SystemdMode "system"}}
 User={{.User}}
 {{- end}}
+{{- if .EnvironmentFile}}
+EnvironmentFile=-{{.EnvironmentFile}}
+{{- end}}
 ExecStart=/bin/bash -c '{{.DeployDir}}/scripts/run_{{.ServiceName}}.sh'
 {{- if eq .ServiceName "prometheus"}}
 ExecReload=/bin/bash -c 'kill -HUP $MAINPID $(pidof {{.DeployDir}}/bin/ng-monitoring-server)'
@@ -0,0 +1,112 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// AlertmanagerClient is client for access the Alertmanager API v2
+type AlertmanagerClient struct {
+	urls   []string
+	client *utils.HTTPClient
+	ctx    context.Context
+}
+
+// SilenceMatcher is a matcher of the labels of alerts to silence
+type SilenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// Silence is a silence of alerts in Alertmanager
+type Silence struct {
+	ID        string           `json:"id,omitempty"`
+	Matchers  []SilenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+}
+
+// NewAlertmanagerClient return a `AlertmanagerClient`
+func NewAlertmanagerClient(ctx context.Context, addresses []string, timeout time.Duration, tlsConfig *tls.Config) *AlertmanagerClient {
+	httpPrefix := "http"
+	if tlsConfig != nil {
+		httpPrefix = "https"
+	}
+	urls := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		urls = append(urls, fmt.Sprintf("%s://%s", httpPrefix, addr))
+	}
+
+	return &AlertmanagerClient{
+		urls:   urls,
+		client: utils.NewHTTPClient(timeout, tlsConfig),
+		ctx:    ctx,
+	}
+}
+
+func (c *AlertmanagerClient) getEndpoints(api string) (endpoints []string) {
+	for _, url := range c.urls {
+		endpoints = append(endpoints, fmt.Sprintf("%s%s", url, api))
+	}
+	return endpoints
+}
+
+// CreateSilence creates a silence and returns the ID of it. The Alertmanager
+// instances of a cluster form a gossip cluster, so creating the silence on
+// any of them is enough.
+func (c *AlertmanagerClient) CreateSilence(silence *Silence) (string, error) {
+	body, err := json.Marshal(silence)
+	if err != nil {
+		return "", err
+	}
+
+	endpoints := c.getEndpoints("/api/v2/silences")
+	data, err := tryURLs(endpoints, func(endpoint string) ([]byte, error) {
+		return c.client.Post(c.ctx, endpoint, bytes.NewReader(body))
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp := struct {
+		SilenceID string `json:"silenceID"`
+	}{}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", errors.Annotatef(err, "unexpected response: %s", string(data))
+	}
+	return resp.SilenceID, nil
+}
+
+// ExpireSilence expires the silence with the ID
+func (c *AlertmanagerClient) ExpireSilence(id string) error {
+	endpoints := c.getEndpoints("/api/v2/silence/" + id)
+	_, err := tryURLs(endpoints, func(endpoint string) ([]byte, error) {
+		body, _, err := c.client.Delete(c.ctx, endpoint, nil)
+		return body, err
+	})
+	return err
+}
@@ -57,6 +57,8 @@ type LabelInfo struct {
 	Capacity  string `json:"capacity"`
 	Available string `json:"available"`
 	Labels    string `json:"labels"`
+	// Mismatch records why the labels of the store are not as expected
+	Mismatch []string `json:"mismatch,omitempty"`
 }
 
 // NewPDClient returns a new PDClient, the context must have
@@ -1017,6 +1019,60 @@ func (pc *PDClient) SetAllStoreLimits(value int) error {
 	return pc.updateConfig(pdStoresLimitURI, bytes.NewBuffer(body))
 }
 
+// StoreLimit is the limit of scheduling operations on a store
+type StoreLimit struct {
+	AddPeer    float64 `json:"add-peer"`
+	RemovePeer float64 `json:"remove-peer"`
+}
+
+// GetStoreLimits returns the store limits keyed by the store IDs, it has the
+// same effect as `pd-ctl store limit`
+func (pc *PDClient) GetStoreLimits() (map[string]StoreLimit, error) {
+	endpoints := pc.getEndpoints(pdStoresLimitURI)
+	limits := make(map[string]StoreLimit)
+	_, err := tryURLs(endpoints, func(endpoint string) ([]byte, error) {
+		body, err := pc.httpClient.Get(pc.ctx, endpoint)
+		if err != nil {
+			return body, err
+		}
+		return body, json.Unmarshal(body, &limits)
+	})
+	return limits, err
+}
+
+// SetStoreLimit sets the limit of the type (add-peer or remove-peer) of the
+// store, it has the same effect as `pd-ctl store limit <id> <rate> <type>`
+func (pc *PDClient) SetStoreLimit(storeID string, typ string, rate float64) error {
+	data := map[string]any{"rate": rate, "type": typ}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	pc.l().Debugf("setting %s limit of store %s: %v", typ, storeID, rate)
+	endpoints := pc.getEndpoints(fmt.Sprintf("%s/%s/limit", pdStoreURI, storeID))
+	_, err = tryURLs(endpoints, func(endpoint string) ([]byte, error) {
+		return pc.httpClient.Post(pc.ctx, endpoint, bytes.NewBuffer(body))
+	})
+	return err
+}
+
+// PauseScheduler pauses the scheduler for the duration, the scheduler is
+// resumed if the duration is 0. It has the same effect as
+// `pd-ctl scheduler pause|resume <name>`
+func (pc *PDClient) PauseScheduler(name string, delay time.Duration) error {
+	data := map[string]any{"delay": int64(delay.Seconds())}
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	pc.l().Debugf("pausing scheduler %s for %s", name, delay)
+	endpoints := pc.getEndpoints(fmt.Sprintf("%s/%s", pdSchedulersURI, name))
+	_, err = tryURLs(endpoints, func(endpoint string) ([]byte, error) {
+		return pc.httpClient.Post(pc.ctx, endpoint, bytes.NewBuffer(body))
+	})
+	return err
+}
+
 // GetServicePrimary queries for the primary of a service
 func (pc *PDClient) GetServicePrimary(service string) (string, error) {
 	endpoints := pc.getEndpoints(fmt.Sprintf("%s/%s", pdServicePrimaryURI, service))
@@ -15,6 +15,7 @@ package ctxt
 
 import (
 	"context"
+	"io"
 	"runtime"
 	"sync"
 	"time"
@@ -50,6 +51,15 @@ type (
 		Transfer(ctx context.Context, src, dst string, download bool, limit int, compress bool) error
 	}
 
+	// StreamExecutor is implemented by the executors able to stream the
+	// input and output of a command instead of buffering them in memory.
+	StreamExecutor interface {
+		// ExecuteStream runs the command with its stdin and stdout connected
+		// to the reader and writer, then returns its stderr. The stdin can
+		// be nil if the command does not read from it.
+		ExecuteStream(ctx context.Context, cmd string, sudo bool, stdin io.Reader, stdout io.Writer, timeout ...time.Duration) (stderr []byte, err error)
+	}
+
 	// ExecutorGetter get the executor by host.
 	ExecutorGetter interface {
 		Get(host string) (e Executor)
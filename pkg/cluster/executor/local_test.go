@@ -14,10 +14,12 @@
 package executor
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"os/user"
+	"strings"
 	"testing"
 
 	"github.com/pingcap/tiup/pkg/cluster/ctxt"
@@ -95,3 +97,22 @@ func TestLocalExecuteWithQuotes(t *testing.T) {
 		assert.Nil(err)
 	}
 }
+
+func TestLocalExecuteStream(t *testing.T) {
+	ctx := ctxt.New(context.Background(), 0, logprinter.NewLogger(""))
+
+	assert := require.New(t)
+	user, err := user.Current()
+	assert.Nil(err)
+	local, err := New(SSHTypeNone, false, SSHConfig{Host: "127.0.0.1", User: user.Username})
+	assert.Nil(err)
+
+	stdout := new(bytes.Buffer)
+	_, err = ExecuteStream(ctx, local, `tr a-z A-Z; echo "$?"`, false, strings.NewReader("it's streamed\n"), stdout)
+	assert.Nil(err)
+	assert.Equal("IT'S STREAMED\n0\n", stdout.String())
+
+	stderr, err := ExecuteStream(ctx, local, "echo failed >&2; exit 3", false, nil, stdout)
+	assert.NotNil(err)
+	assert.Equal("failed\n", string(stderr))
+}
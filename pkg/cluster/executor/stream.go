@@ -0,0 +1,204 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	"github.com/pingcap/tiup/pkg/localdata"
+	"github.com/pingcap/tiup/pkg/utils"
+	"go.uber.org/zap"
+)
+
+var (
+	_ ctxt.StreamExecutor = &EasySSHExecutor{}
+	_ ctxt.StreamExecutor = &NativeSSHExecutor{}
+	_ ctxt.StreamExecutor = &Local{}
+	_ ctxt.StreamExecutor = &CheckPointExecutor{}
+)
+
+// ExecuteStream runs the command with the executor streaming its stdin and
+// stdout, an error is returned if the executor does not support streaming.
+func ExecuteStream(ctx context.Context, e ctxt.Executor, cmd string, sudo bool, stdin io.Reader, stdout io.Writer, timeout ...time.Duration) ([]byte, error) {
+	se, ok := e.(ctxt.StreamExecutor)
+	if !ok {
+		return nil, errors.Errorf("executor %T does not support streaming", e)
+	}
+	return se.ExecuteStream(ctx, cmd, sudo, stdin, stdout, timeout...)
+}
+
+// streamCommand wraps the command the same way as Execute, the command is
+// quoted as a whole so that it can contain any shell syntax when using sudo.
+func streamCommand(cmd string, sudo bool, locale string) string {
+	if sudo {
+		cmd = "/usr/bin/sudo -H bash -c " + quoteCommand(cmd)
+	}
+	cmd = "PATH=$PATH:/bin:/sbin:/usr/bin:/usr/sbin; " + cmd
+	if locale != "" {
+		cmd = fmt.Sprintf("export LANG=%s; %s", locale, cmd)
+	}
+	return cmd
+}
+
+// quoteCommand quotes the command to be used as a single argument in shell
+func quoteCommand(cmd string) string {
+	return "'" + strings.ReplaceAll(cmd, "'", `'\''`) + "'"
+}
+
+// streamTimeout returns the context canceled after the timeout, the default
+// timeout is used if it is not specified
+func streamTimeout(ctx context.Context, timeout []time.Duration) (context.Context, context.CancelFunc) {
+	if len(timeout) == 0 {
+		timeout = append(timeout, executeDefaultTimeout)
+	}
+	return context.WithTimeout(ctx, timeout[0])
+}
+
+// streamError wraps the error of streaming command like Execute does
+func streamError(err error, cmd string, stderr []byte, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return ErrSSHExecuteFailed.
+		Wrap(err, format, args...).
+		WithProperty(ErrPropSSHCommand, cmd).
+		WithProperty(ErrPropSSHStderr, string(stderr))
+}
+
+// ExecuteStream implements the StreamExecutor interface.
+func (e *EasySSHExecutor) ExecuteStream(ctx context.Context, cmd string, sudo bool, stdin io.Reader, stdout io.Writer, timeout ...time.Duration) ([]byte, error) {
+	cmd = streamCommand(cmd, e.Sudo || sudo, e.Locale)
+	ctx, cancel := streamTimeout(ctx, timeout)
+	defer cancel()
+
+	session, client, err := e.Config.Connect()
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+	defer session.Close()
+
+	stderr := new(bytes.Buffer)
+	session.Stdin = stdin
+	session.Stdout = stdout
+	session.Stderr = stderr
+
+	done := make(chan error, 1)
+	go func() {
+		done <- session.Run(cmd)
+	}()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// closing the client terminates the session and unblocks Run
+		client.Close()
+		<-done
+		err = ctx.Err()
+	}
+
+	zap.L().Info("SSHStreamCommand",
+		zap.String("host", e.Config.Server),
+		zap.String("port", e.Config.Port),
+		zap.String("cmd", cmd),
+		zap.Error(err),
+		zap.String("stderr", stderr.String()))
+
+	return stderr.Bytes(), streamError(err, cmd, stderr.Bytes(),
+		"Failed to execute command over SSH for '%s@%s:%s'", e.Config.User, e.Config.Server, e.Config.Port)
+}
+
+// ExecuteStream implements the StreamExecutor interface.
+func (e *NativeSSHExecutor) ExecuteStream(ctx context.Context, cmd string, sudo bool, stdin io.Reader, stdout io.Writer, timeout ...time.Duration) ([]byte, error) {
+	if e.ConnectionTestResult != nil {
+		return nil, e.ConnectionTestResult
+	}
+	cmd = streamCommand(cmd, e.Sudo || sudo, e.Locale)
+	ctx, cancel := streamTimeout(ctx, timeout)
+	defer cancel()
+
+	ssh := "ssh"
+	if val := os.Getenv(localdata.EnvNameSSHPath); val != "" {
+		if isExec := utils.IsExecBinary(val); !isExec {
+			return nil, fmt.Errorf("specified SSH in the environment variable `%s` does not exist or is not executable", localdata.EnvNameSSHPath)
+		}
+		ssh = val
+	}
+	args := []string{ssh, "-o", "StrictHostKeyChecking=no"}
+	args = e.configArgs(args, false)
+	args = append(args, fmt.Sprintf("%s@%s", e.Config.User, e.Config.Host), cmd)
+
+	stderr := new(bytes.Buffer)
+	command := exec.CommandContext(ctx, args[0], args[1:]...)
+	command.Stdin = stdin
+	command.Stdout = stdout
+	command.Stderr = stderr
+	err := command.Run()
+
+	zap.L().Info("SSHStreamCommand",
+		zap.String("host", e.Config.Host),
+		zap.Int("port", e.Config.Port),
+		zap.String("cmd", cmd),
+		zap.Error(err),
+		zap.String("stderr", stderr.String()))
+
+	return stderr.Bytes(), streamError(err, cmd, stderr.Bytes(),
+		"Failed to execute command over SSH for '%s@%s:%d'", e.Config.User, e.Config.Host, e.Config.Port)
+}
+
+// ExecuteStream implements the StreamExecutor interface.
+func (l *Local) ExecuteStream(ctx context.Context, cmd string, sudo bool, stdin io.Reader, stdout io.Writer, timeout ...time.Duration) ([]byte, error) {
+	current, err := user.Current()
+	if err != nil {
+		return nil, err
+	}
+	cmd = "cd; " + cmd
+	if l.Sudo || sudo {
+		cmd = "/usr/bin/sudo -H -u root bash -c " + quoteCommand(cmd)
+	} else if l.Config.User != current.Username {
+		cmd = fmt.Sprintf("/usr/bin/sudo -H -u %s bash -c %s", l.Config.User, quoteCommand(cmd))
+	}
+	cmd = streamCommand(cmd, false, l.Locale)
+	ctx, cancel := streamTimeout(ctx, timeout)
+	defer cancel()
+
+	stderr := new(bytes.Buffer)
+	command := exec.CommandContext(ctx, "/bin/bash", "-c", cmd)
+	command.Stdin = stdin
+	command.Stdout = stdout
+	command.Stderr = stderr
+	err = command.Run()
+
+	zap.L().Info("LocalStreamCommand",
+		zap.String("cmd", cmd),
+		zap.Error(err),
+		zap.String("stderr", stderr.String()))
+
+	return stderr.Bytes(), streamError(err, cmd, stderr.Bytes(), "Failed to execute command locally")
+}
+
+// ExecuteStream implements the StreamExecutor interface, the streams are
+// not recorded in checkpoints as they can not be replayed.
+func (c *CheckPointExecutor) ExecuteStream(ctx context.Context, cmd string, sudo bool, stdin io.Reader, stdout io.Writer, timeout ...time.Duration) ([]byte, error) {
+	return ExecuteStream(ctx, c.Executor, cmd, sudo, stdin, stdout, timeout...)
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"os/user"
+	"strings"
+	"time"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/api"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// AlertSilenceOptions contains the options of silencing alerts
+type AlertSilenceOptions struct {
+	Matchers []string      // label matchers like name=value, name!=value, name=~regex
+	Duration time.Duration // how long the silence lasts
+	Comment  string
+	Author   string
+}
+
+// AlertSilence creates a silence in the Alertmanager of the cluster, the
+// alerts of the cluster are matched by default.
+func (m *Manager) AlertSilence(name string, opt AlertSilenceOptions, gOpt operator.Options) (string, error) {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return "", err
+	}
+	if opt.Duration <= 0 {
+		return "", perrs.New("the duration of silence must be positive")
+	}
+
+	metadata, err := m.meta(name)
+	if err != nil {
+		return "", err
+	}
+	client, err := m.alertmanagerClient(name, metadata.GetTopology(), gOpt)
+	if err != nil {
+		return "", err
+	}
+
+	matchers, err := parseSilenceMatchers(opt.Matchers)
+	if err != nil {
+		return "", err
+	}
+	hasCluster := false
+	for _, matcher := range matchers {
+		hasCluster = hasCluster || matcher.Name == "cluster"
+	}
+	if !hasCluster {
+		matchers = append(matchers, api.SilenceMatcher{Name: "cluster", Value: name, IsEqual: true})
+	}
+
+	author := opt.Author
+	if author == "" {
+		if u, err := user.Current(); err == nil {
+			author = u.Username
+		} else {
+			author = "tiup"
+		}
+	}
+	comment := opt.Comment
+	if comment == "" {
+		comment = fmt.Sprintf("silenced by tiup-cluster for %s", opt.Duration)
+	}
+
+	now := time.Now().UTC()
+	id, err := client.CreateSilence(&api.Silence{
+		Matchers:  matchers,
+		StartsAt:  now,
+		EndsAt:    now.Add(opt.Duration),
+		CreatedBy: author,
+		Comment:   comment,
+	})
+	if err != nil {
+		return "", perrs.Annotate(err, "create silence")
+	}
+	return id, nil
+}
+
+// alertmanagerClient returns the client of the Alertmanager of the cluster
+func (m *Manager) alertmanagerClient(name string, topo spec.Topology, gOpt operator.Options) (*api.AlertmanagerClient, error) {
+	var addrs []string
+	for _, am := range topo.BaseTopo().Alertmanagers {
+		addrs = append(addrs, utils.JoinHostPort(am.GetManageHost(), am.WebPort))
+	}
+	if len(addrs) == 0 {
+		return nil, perrs.Errorf("no alertmanager found in cluster %s", name)
+	}
+
+	// alertmanager always serves plain HTTP even if TLS is enabled for the
+	// cluster, the same as how its status is checked
+	ctx := context.WithValue(context.Background(), logprinter.ContextKeyLogger, m.logger)
+	return api.NewAlertmanagerClient(ctx, addrs, time.Second*time.Duration(gOpt.APITimeout), nil), nil
+}
+
+// parseSilenceMatchers parses matchers like name=value, name!=value,
+// name=~regex and name!~regex
+func parseSilenceMatchers(exprs []string) ([]api.SilenceMatcher, error) {
+	matchers := make([]api.SilenceMatcher, 0, len(exprs))
+	for _, expr := range exprs {
+		var matcher api.SilenceMatcher
+		var op string
+		for _, o := range []string{"!=", "=~", "!~", "="} {
+			if idx := strings.Index(expr, o); idx > 0 {
+				matcher.Name, matcher.Value, op = expr[:idx], expr[idx+len(o):], o
+				break
+			}
+		}
+		matcher.Name = strings.TrimSpace(matcher.Name)
+		if op == "" || matcher.Name == "" {
+			return nil, perrs.Errorf("invalid matcher '%s', should be like name=value, name!=value, name=~regex or name!~regex", expr)
+		}
+		matcher.Value = strings.Trim(strings.TrimSpace(matcher.Value), `"`)
+		matcher.IsRegex = op == "=~" || op == "!~"
+		matcher.IsEqual = op == "=" || op == "=~"
+		matchers = append(matchers, matcher)
+	}
+	return matchers, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/cluster/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSilenceMatchers(t *testing.T) {
+	matchers, err := parseSilenceMatchers([]string{
+		"alertname=TiKV_server_is_down",
+		"env!=test",
+		`instance=~"10.0.1.1:.*"`,
+		"job!~tidb|pd",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []api.SilenceMatcher{
+		{Name: "alertname", Value: "TiKV_server_is_down", IsEqual: true},
+		{Name: "env", Value: "test"},
+		{Name: "instance", Value: "10.0.1.1:.*", IsRegex: true, IsEqual: true},
+		{Name: "job", Value: "tidb|pd", IsRegex: true},
+	}, matchers)
+
+	for _, expr := range []string{"alertname", "=value", " !=value"} {
+		_, err = parseSilenceMatchers([]string{expr})
+		require.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,420 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/set"
+	"github.com/pingcap/tiup/pkg/tui"
+	"github.com/pingcap/tiup/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// componentNGMonitoring is the name of ng-monitoring in the plan, it is not
+// a component of the topology but runs on the hosts of prometheus
+const componentNGMonitoring = "ng-monitoring"
+
+// ApplyInstance is an instance to be changed by apply
+type ApplyInstance struct {
+	Role string
+	ID   string // host:port
+}
+
+// ApplyPlan is the changes to reconcile the cluster with a desired topology,
+// the changes are applied in the order of scale-out, upgrade, reload and
+// scale-in, so that the cluster never runs with less instances than both of
+// the current and the desired topology, and the new configs are loaded by
+// the new version.
+type ApplyPlan struct {
+	Added   []ApplyInstance // instances to scale out
+	Removed []ApplyInstance // instances to scale in
+	Changed []ApplyInstance // instances with config changes
+
+	ConfigRoles   []string // components whose server_configs are changed
+	GlobalChanged bool     // global or monitored options are changed
+
+	Version           string            // target cluster version, empty if unchanged
+	ComponentVersions map[string]string // component name -> target version
+
+	baseVersion string
+	desired     *spec.Specification
+	newPart     map[string]any // yaml field -> added instance specs
+}
+
+// IsEmpty returns true if there is nothing to change
+func (p *ApplyPlan) IsEmpty() bool {
+	return len(p.Added) == 0 && len(p.Removed) == 0 && !p.NeedReload() && !p.NeedUpgrade()
+}
+
+// NeedReload returns true if configs of existing instances are changed
+func (p *ApplyPlan) NeedReload() bool {
+	return len(p.Changed) > 0 || len(p.ConfigRoles) > 0 || p.GlobalChanged
+}
+
+// NeedUpgrade returns true if the cluster or any component version is changed
+func (p *ApplyPlan) NeedUpgrade() bool {
+	return p.Version != "" || len(p.ComponentVersions) > 0
+}
+
+// TargetVersion returns the cluster version to upgrade to
+func (p *ApplyPlan) TargetVersion() string {
+	if p.Version != "" {
+		return p.Version
+	}
+	return p.baseVersion
+}
+
+// RemovedNodes returns the IDs of instances to scale in
+func (p *ApplyPlan) RemovedNodes() []string {
+	nodes := make([]string, 0, len(p.Removed))
+	for _, ins := range p.Removed {
+		nodes = append(nodes, ins.ID)
+	}
+	return nodes
+}
+
+// ScaleOutTopology returns the topology file content of instances to scale out
+func (p *ApplyPlan) ScaleOutTopology() ([]byte, error) {
+	return yaml.Marshal(p.newPart)
+}
+
+// PlanApply compares the topology file with the cluster and returns the
+// changes needed to reconcile the cluster with it.
+func (m *Manager) PlanApply(name, topoFile, version string) (*ApplyPlan, error) {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return nil, err
+	}
+	if err := m.specManager.ScaleOutLockedErr(name); err != nil {
+		return nil, err
+	}
+
+	metadata, err := m.meta(name)
+	if err != nil {
+		return nil, err
+	}
+	cur, ok := metadata.GetTopology().(*spec.Specification)
+	if !ok {
+		return nil, perrs.Errorf("apply is not supported for %s cluster", m.sysName)
+	}
+	base := metadata.GetBaseMeta()
+
+	desired := &spec.Specification{}
+	if err := spec.ParseTopologyYaml(topoFile, desired); err != nil {
+		return nil, err
+	}
+	spec.ExpandRelativeDir(desired)
+
+	targetVersion := base.Version
+	if version != "" {
+		if targetVersion, err = utils.FmtVer(version); err != nil {
+			return nil, err
+		}
+	}
+	desired.AdjustByVersion(targetVersion)
+	if err := desired.ApplyPlacementLabels(); err != nil {
+		return nil, err
+	}
+
+	plan, err := diffTopology(cur, desired)
+	if err != nil {
+		return nil, err
+	}
+	plan.baseVersion = base.Version
+	if targetVersion != base.Version {
+		if err := versionCompare(base.Version, targetVersion); err != nil {
+			return nil, err
+		}
+		plan.Version = targetVersion
+	}
+	return plan, nil
+}
+
+// DisplayApplyPlan prints the plan
+func (m *Manager) DisplayApplyPlan(name string, p *ApplyPlan) {
+	if p.IsEmpty() {
+		m.logger.Infof("Cluster %s is up to date with the topology", color.YellowString(name))
+		return
+	}
+
+	rows := [][]string{{"Step", "Action", "Role", "Instance"}}
+	for _, ins := range p.Added {
+		rows = append(rows, []string{"1", color.GreenString("scale-out"), ins.Role, ins.ID})
+	}
+	if p.Version != "" {
+		rows = append(rows, []string{"2", color.YellowString("upgrade"), "-", p.Version})
+	}
+	comps := make([]string, 0, len(p.ComponentVersions))
+	for comp := range p.ComponentVersions {
+		comps = append(comps, comp)
+	}
+	sort.Strings(comps)
+	for _, comp := range comps {
+		rows = append(rows, []string{"2", color.YellowString("upgrade"), comp, p.ComponentVersions[comp]})
+	}
+	for _, ins := range p.Changed {
+		rows = append(rows, []string{"3", color.CyanString("reload"), ins.Role, ins.ID})
+	}
+	for _, role := range p.ConfigRoles {
+		rows = append(rows, []string{"3", color.CyanString("reload"), role, "server_configs"})
+	}
+	if p.GlobalChanged {
+		rows = append(rows, []string{"3", color.CyanString("reload"), "-", "global/monitored"})
+	}
+	for _, ins := range p.Removed {
+		rows = append(rows, []string{"4", color.RedString("scale-in"), ins.Role, ins.ID})
+	}
+
+	fmt.Printf("Plan to apply the topology to cluster %s:\n", color.HiYellowString(name))
+	tui.PrintTable(rows, true)
+}
+
+// ApplyTopologyConfig saves the configs and options of existing instances
+// in the desired topology to the cluster meta, and returns the nodes need
+// to be reloaded, an empty list means all nodes. Component versions are left
+// to the upgrade step.
+func (m *Manager) ApplyTopologyConfig(name string, p *ApplyPlan) ([]string, error) {
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	metadata, err := m.meta(name)
+	if err != nil {
+		return nil, err
+	}
+	cur := metadata.GetTopology().(*spec.Specification)
+
+	newTopo := alignTopology(cur, p.desired)
+	newTopo.ComponentVersions = cur.ComponentVersions
+	if err := utils.ValidateSpecDiff(cur, newTopo); err != nil {
+		return nil, err
+	}
+	if err := newTopo.CopyTemplates(m.specManager.Path(name, spec.TemplatesDirName)); err != nil {
+		return nil, err
+	}
+	metadata.SetTopology(newTopo)
+	if err := m.specManager.SaveMeta(name, metadata); err != nil {
+		return nil, perrs.Annotate(err, "failed to save meta")
+	}
+
+	if p.GlobalChanged {
+		return nil, nil
+	}
+	nodes := set.NewStringSet()
+	for _, ins := range p.Changed {
+		nodes.Insert(ins.ID)
+	}
+	roles := set.NewStringSet(p.ConfigRoles...)
+	// ng-monitoring is deployed along with prometheus
+	if roles.Exist(componentNGMonitoring) {
+		roles.Insert(spec.ComponentPrometheus)
+	}
+	newTopo.IterInstance(func(ins spec.Instance) {
+		if roles.Exist(ins.ComponentName()) {
+			nodes.Insert(ins.ID())
+		}
+	})
+	return nodes.Slice(), nil
+}
+
+// diffTopology compares the instances, configs and versions of the topologies
+func diffTopology(cur, desired *spec.Specification) (*ApplyPlan, error) {
+	plan := &ApplyPlan{
+		ComponentVersions: make(map[string]string),
+		desired:           desired,
+		newPart:           make(map[string]any),
+	}
+
+	curSpecs := instanceSpecs(cur)
+	desiredSpecs := instanceSpecs(desired)
+
+	// the immutable fields of existing instances must not be changed
+	aligned := alignTopology(cur, desired)
+	aligned.ComponentVersions = cur.ComponentVersions
+	if err := utils.ValidateSpecDiff(cur, aligned); err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(desired).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !isInstanceSlice(field) {
+			continue
+		}
+		added := reflect.MakeSlice(field.Type(), 0, 0)
+		for j := 0; j < field.Len(); j++ {
+			key, ins := instanceSpecKey(field.Index(j))
+			if _, ok := curSpecs[key]; !ok {
+				plan.Added = append(plan.Added, ins)
+				added = reflect.Append(added, field.Index(j))
+			}
+		}
+		if added.Len() > 0 {
+			plan.newPart[yamlFieldName(v.Type().Field(i))] = added.Interface()
+		}
+	}
+
+	v = reflect.ValueOf(cur).Elem()
+	av := reflect.ValueOf(aligned).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !isInstanceSlice(field) {
+			continue
+		}
+		for j := 0; j < field.Len(); j++ {
+			key, ins := instanceSpecKey(field.Index(j))
+			if _, ok := desiredSpecs[key]; !ok {
+				plan.Removed = append(plan.Removed, ins)
+				continue
+			}
+			if !yamlEqual(field.Index(j).Interface(), av.Field(i).Index(j).Interface()) {
+				plan.Changed = append(plan.Changed, ins)
+			}
+		}
+	}
+
+	plan.ConfigRoles = diffFields(reflect.ValueOf(cur.ServerConfigs), reflect.ValueOf(desired.ServerConfigs))
+	cv, dv := reflect.ValueOf(cur.ComponentVersions), reflect.ValueOf(desired.ComponentVersions)
+	for i := 0; i < dv.NumField(); i++ {
+		if ver := dv.Field(i).String(); ver != "" && ver != cv.Field(i).String() {
+			plan.ComponentVersions[componentOfField(yamlFieldName(dv.Type().Field(i)))] = ver
+		}
+	}
+	plan.GlobalChanged = !yamlEqual(cur.GlobalOptions, desired.GlobalOptions) ||
+		!yamlEqual(cur.MonitoredOptions, desired.MonitoredOptions) ||
+		!yamlEqual(cur.Placement, desired.Placement) ||
+		!yamlEqual(cur.Templates, desired.Templates) ||
+		!yamlEqual(cur.ExternalEndpoints, desired.ExternalEndpoints) ||
+		!yamlEqual(cur.AlertOverrides, desired.AlertOverrides)
+
+	return plan, nil
+}
+
+// alignTopology returns a copy of desired with instance lists in the order
+// of cur, the instances only in cur are kept and the ones only in desired
+// are dropped. The fields filled by tiup on deploying (e.g. arch) are
+// inherited from cur if not set in desired.
+func alignTopology(cur, desired *spec.Specification) *spec.Specification {
+	aligned := *desired
+	desiredSpecs := instanceSpecs(desired)
+
+	cv := reflect.ValueOf(cur).Elem()
+	av := reflect.ValueOf(&aligned).Elem()
+	for i := 0; i < cv.NumField(); i++ {
+		field := cv.Field(i)
+		if !isInstanceSlice(field) {
+			continue
+		}
+		list := reflect.MakeSlice(field.Type(), 0, field.Len())
+		for j := 0; j < field.Len(); j++ {
+			key, _ := instanceSpecKey(field.Index(j))
+			d, ok := desiredSpecs[key]
+			if !ok {
+				list = reflect.Append(list, field.Index(j))
+				continue
+			}
+			// copy the spec to avoid changing the desired topology
+			elem := reflect.New(d.Elem().Type())
+			elem.Elem().Set(d.Elem())
+			for _, name := range []string{"OS", "Arch"} {
+				f := elem.Elem().FieldByName(name)
+				if f.IsValid() && f.String() == "" {
+					f.Set(field.Index(j).Elem().FieldByName(name))
+				}
+			}
+			list = reflect.Append(list, elem)
+		}
+		av.Field(i).Set(list)
+	}
+	return &aligned
+}
+
+// instanceSpecs returns the instance specs of the topology indexed by role and ID
+func instanceSpecs(s *spec.Specification) map[string]reflect.Value {
+	specs := make(map[string]reflect.Value)
+	v := reflect.ValueOf(s).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !isInstanceSlice(field) {
+			continue
+		}
+		for j := 0; j < field.Len(); j++ {
+			key, _ := instanceSpecKey(field.Index(j))
+			specs[key] = field.Index(j)
+		}
+	}
+	return specs
+}
+
+func isInstanceSlice(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice &&
+		v.Type().Elem().Implements(reflect.TypeFor[spec.InstanceSpec]())
+}
+
+func instanceSpecKey(v reflect.Value) (string, ApplyInstance) {
+	is := v.Interface().(spec.InstanceSpec)
+	ins := ApplyInstance{
+		Role: is.Role(),
+		ID:   utils.JoinHostPort(v.Elem().FieldByName("Host").String(), is.GetMainPort()),
+	}
+	return ins.Role + "/" + ins.ID, ins
+}
+
+// diffFields returns the components of the struct fields which are different
+func diffFields(a, b reflect.Value) []string {
+	comps := set.NewStringSet()
+	for i := 0; i < a.NumField(); i++ {
+		if !yamlEqual(a.Field(i).Interface(), b.Field(i).Interface()) {
+			comps.Insert(componentOfField(yamlFieldName(a.Type().Field(i))))
+		}
+	}
+	list := comps.Slice()
+	sort.Strings(list)
+	return list
+}
+
+// componentOfField converts the yaml field name of server_configs and
+// component_versions to the component name
+func componentOfField(name string) string {
+	switch name {
+	case "kvcdc":
+		return spec.ComponentTiKVCDC
+	case "tiflash-learner":
+		return spec.ComponentTiFlash
+	case "ng_monitoring":
+		return componentNGMonitoring
+	}
+	return strings.ReplaceAll(name, "_", "-")
+}
+
+func yamlFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	return name
+}
+
+func yamlEqual(a, b any) bool {
+	da, err1 := yaml.Marshal(a)
+	db, err2 := yaml.Marshal(b)
+	return err1 == nil && err2 == nil && bytes.Equal(da, db)
+}
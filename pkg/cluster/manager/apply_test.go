@@ -0,0 +1,141 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func parseApplyTopo(t *testing.T, data string) *spec.Specification {
+	topo := &spec.Specification{}
+	require.NoError(t, yaml.Unmarshal([]byte(data), topo))
+	spec.ExpandRelativeDir(topo)
+	return topo
+}
+
+func TestDiffTopology(t *testing.T) {
+	cur := parseApplyTopo(t, `
+server_configs:
+  tikv:
+    storage.reserve-space: 1GB
+tidb_servers:
+  - host: 172.16.5.1
+    arch: amd64
+    os: linux
+  - host: 172.16.5.2
+    arch: amd64
+    os: linux
+tikv_servers:
+  - host: 172.16.5.1
+    arch: amd64
+    os: linux
+pd_servers:
+  - host: 172.16.5.1
+    arch: amd64
+    os: linux
+`)
+
+	// nothing changed
+	plan, err := diffTopology(cur, parseApplyTopo(t, `
+server_configs:
+  tikv:
+    storage.reserve-space: 1GB
+tidb_servers:
+  - host: 172.16.5.2
+  - host: 172.16.5.1
+tikv_servers:
+  - host: 172.16.5.1
+pd_servers:
+  - host: 172.16.5.1
+`))
+	require.NoError(t, err)
+	require.True(t, plan.IsEmpty())
+
+	desired := parseApplyTopo(t, `
+server_configs:
+  tikv:
+    storage.reserve-space: 2GB
+component_versions:
+  tikv: v8.5.1
+tidb_servers:
+  - host: 172.16.5.1
+    config:
+      log.level: warn
+tikv_servers:
+  - host: 172.16.5.1
+  - host: 172.16.5.3
+pd_servers:
+  - host: 172.16.5.1
+`)
+	plan, err = diffTopology(cur, desired)
+	require.NoError(t, err)
+	require.Equal(t, []ApplyInstance{{Role: "tikv", ID: "172.16.5.3:20160"}}, plan.Added)
+	require.Equal(t, []ApplyInstance{{Role: "tidb", ID: "172.16.5.2:4000"}}, plan.Removed)
+	require.Equal(t, []ApplyInstance{{Role: "tidb", ID: "172.16.5.1:4000"}}, plan.Changed)
+	require.Equal(t, []string{"tikv"}, plan.ConfigRoles)
+	require.Equal(t, map[string]string{"tikv": "v8.5.1"}, plan.ComponentVersions)
+	require.False(t, plan.GlobalChanged)
+	require.Equal(t, []string{"172.16.5.2:4000"}, plan.RemovedNodes())
+
+	data, err := plan.ScaleOutTopology()
+	require.NoError(t, err)
+	newPart := &spec.Specification{}
+	require.NoError(t, yaml.Unmarshal(data, newPart))
+	require.Len(t, newPart.TiKVServers, 1)
+	require.Equal(t, "172.16.5.3", newPart.TiKVServers[0].Host)
+	require.Empty(t, newPart.TiDBServers)
+
+	// the arch of existing instances is inherited and not treated as a change
+	aligned := alignTopology(cur, desired)
+	require.Len(t, aligned.TiDBServers, 2)
+	require.Equal(t, "amd64", aligned.TiDBServers[0].Arch)
+	require.Equal(t, "warn", aligned.TiDBServers[0].Config["log.level"])
+	require.Len(t, aligned.TiKVServers, 1)
+	require.Empty(t, desired.TiDBServers[0].Arch)
+
+	// immutable fields can not be changed
+	_, err = diffTopology(cur, parseApplyTopo(t, `
+tidb_servers:
+  - host: 172.16.5.1
+    deploy_dir: /another/dir
+  - host: 172.16.5.2
+tikv_servers:
+  - host: 172.16.5.1
+pd_servers:
+  - host: 172.16.5.1
+`))
+	require.Error(t, err)
+
+	// ng-monitoring is not taken as prometheus
+	plan, err = diffTopology(cur, parseApplyTopo(t, `
+server_configs:
+  tikv:
+    storage.reserve-space: 1GB
+  ng_monitoring:
+    retention-period: 7
+tidb_servers:
+  - host: 172.16.5.1
+  - host: 172.16.5.2
+tikv_servers:
+  - host: 172.16.5.1
+pd_servers:
+  - host: 172.16.5.1
+`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"ng-monitoring"}, plan.ConfigRoles)
+}
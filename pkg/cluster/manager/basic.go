@@ -44,6 +44,12 @@ func (m *Manager) EnableCluster(name string, gOpt operator.Options, isEnable boo
 		m.logger.Infof("Disabling cluster %s...", name)
 	}
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) {
 		return err
@@ -100,6 +106,12 @@ func (m *Manager) StartCluster(name string, gOpt operator.Options, restoreLeader
 		return err
 	}
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) {
 		return err
@@ -157,6 +169,12 @@ func (m *Manager) StopCluster(
 		return err
 	}
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) {
 		return err
@@ -212,11 +230,27 @@ func (m *Manager) StopCluster(
 
 // RestartCluster restart the cluster.
 func (m *Manager) RestartCluster(name string, gOpt operator.Options, skipConfirm bool) error {
+	return m.restartCluster(name, gOpt, skipConfirm, nil)
+}
+
+// SafeRestartCluster restarts the cluster one instance by one instance, and
+// waits for the regions to be healthy between restarting two TiKV stores.
+func (m *Manager) SafeRestartCluster(name string, gate operator.RegionHealthGate, gOpt operator.Options, skipConfirm bool) error {
+	return m.restartCluster(name, gOpt, skipConfirm, &gate)
+}
+
+func (m *Manager) restartCluster(name string, gOpt operator.Options, skipConfirm bool, gate *operator.RegionHealthGate) error {
 	// check locked
 	if err := m.specManager.ScaleOutLockedErr(name); err != nil {
 		return err
 	}
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) {
 		return err
@@ -230,6 +264,14 @@ func (m *Manager) RestartCluster(name string, gOpt operator.Options, skipConfirm
 		return err
 	}
 
+	var safeTopo *spec.Specification
+	if gate != nil {
+		var ok bool
+		if safeTopo, ok = topo.(*spec.Specification); !ok {
+			return perrs.Errorf("safe restart is not supported for %s cluster", m.sysName)
+		}
+	}
+
 	if !skipConfirm {
 		var availabilityMessage string
 		var rolesToRestart string
@@ -243,6 +285,9 @@ func (m *Manager) RestartCluster(name string, gOpt operator.Options, skipConfirm
 			nodesToRestart = strings.Join(gOpt.Nodes, ",")
 			rolesToRestart = strings.Join(gOpt.Roles, ",")
 		}
+		if gate != nil {
+			availabilityMessage = "Instances will be restarted one by one, and it waits for the regions to be healthy between restarting two stores"
+		}
 
 		confirmationMessage := fmt.Sprintf("Will restart the cluster %s with nodes: %s roles: %s.\n%s\nDo you want to continue? [y/N]:",
 			color.HiYellowString(name),
@@ -261,6 +306,9 @@ func (m *Manager) RestartCluster(name string, gOpt operator.Options, skipConfirm
 	}
 	t := b.
 		Func("RestartCluster", func(ctx context.Context) error {
+			if gate != nil {
+				return operator.SafeRestart(ctx, safeTopo, gOpt, tlsCfg, base.Version, *gate)
+			}
 			return operator.Restart(ctx, topo, gOpt, tlsCfg)
 		}).
 		Build()
@@ -382,10 +430,26 @@ func (m *Manager) RestoreClusterMeta(clusterName, filePath string, skipConfirm b
 		}
 		m.logger.Infof("Restoring cluster meta files...")
 	}
-	err = os.RemoveAll(m.specManager.Path(clusterName))
+
+	unlock, err := m.lockCluster(clusterName)
 	if err != nil {
 		return err
 	}
+	defer unlock()
+
+	// keep the operation lock while replacing the metadata
+	entries, err := os.ReadDir(m.specManager.Path(clusterName))
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.Name() == spec.OperationLockName {
+			continue
+		}
+		if err := os.RemoveAll(m.specManager.Path(clusterName, e.Name())); err != nil {
+			return err
+		}
+	}
 	f, err := os.Open(filePath)
 	if err != nil {
 		return err
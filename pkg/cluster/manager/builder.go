@@ -328,9 +328,15 @@ func buildScaleOutTask(
 		afterDeploy(builder, newPart, gOpt)
 	}
 
-	builder.Func("Save meta", func(_ context.Context) error {
+	builder.Func("Save meta", func(ctx context.Context) error {
 		metadata.SetTopology(mergedTopo)
-		return m.specManager.SaveMeta(name, metadata)
+		if err := m.specManager.SaveMeta(name, metadata); err != nil {
+			return err
+		}
+		// the new instances are in the meta now, keep them on failure so
+		// that they can be fixed or scaled in by the user
+		task.CommitJournal(ctx)
+		return nil
 	})
 
 	// don't start the new instance
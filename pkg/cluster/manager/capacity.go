@@ -0,0 +1,375 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/fatih/color"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/api"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/pingcap/tiup/pkg/meta"
+	"github.com/pingcap/tiup/pkg/tui"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+const (
+	// defaultLowSpaceRatio is the default value of PD schedule.low-space-ratio
+	defaultLowSpaceRatio = 0.8
+	// nearLowSpaceMargin is how close to low-space-ratio a store should be
+	// highlighted as a warning
+	nearLowSpaceMargin = 0.05
+)
+
+// StoreCapacityInfo represents the capacity info of a TiKV or TiFlash store
+type StoreCapacityInfo struct {
+	ID         string  `json:"id"`
+	Role       string  `json:"role"`
+	Host       string  `json:"host"`
+	Store      uint64  `json:"store"`
+	Status     string  `json:"status"`
+	Capacity   uint64  `json:"capacity"`
+	Available  uint64  `json:"available"`
+	UsedSize   uint64  `json:"used_size"`
+	RegionSize uint64  `json:"region_size"`
+	GrowthRate float64 `json:"growth_rate"` // bytes per hour
+	Headroom   uint64  `json:"headroom"`
+	ETA        string  `json:"eta"`
+	LowSpace   bool    `json:"low_space"`
+	NearLow    bool    `json:"near_low_space"`
+}
+
+// UsedRatio returns the used percentage of the store in range [0, 1]
+func (s StoreCapacityInfo) UsedRatio() float64 {
+	if s.Capacity == 0 {
+		return 0
+	}
+	return float64(s.Capacity-s.Available) / float64(s.Capacity)
+}
+
+// HostCapacityInfo represents the usage of a filesystem holding data dirs on a host
+type HostCapacityInfo struct {
+	Host      string   `json:"host"`
+	Instances []string `json:"instances"`
+	operator.DiskUsage
+}
+
+// CapacityJSONOutput holds the structure for the JSON output of `tiup cluster display --capacity`
+type CapacityJSONOutput struct {
+	ClusterName   string              `json:"cluster_name"`
+	LowSpaceRatio float64             `json:"low_space_ratio"`
+	Stores        []StoreCapacityInfo `json:"stores"`
+	Hosts         []HostCapacityInfo  `json:"hosts"`
+}
+
+// DisplayCapacity display the capacity of stores and the filesystem usage of data dirs
+func (m *Manager) DisplayCapacity(dopt DisplayOption, opt operator.Options) error {
+	name := dopt.ClusterName
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return err
+	}
+
+	metadata, err := m.meta(name)
+	if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) &&
+		!errors.Is(perrs.Cause(err), spec.ErrNoTiSparkMaster) {
+		return err
+	}
+	topo, ok := metadata.GetTopology().(*spec.Specification)
+	if !ok {
+		return perrs.Errorf("capacity view is not supported for %s cluster", m.sysName)
+	}
+	base := metadata.GetBaseMeta()
+
+	ctx, err := m.clusterSSHContext(name, topo, base.User, opt)
+	if err != nil {
+		return err
+	}
+
+	tlsCfg, err := topo.TLSConfig(m.specManager.Path(name, spec.TLSCertKeyDir))
+	if err != nil {
+		return err
+	}
+	statusTimeout := time.Duration(opt.APITimeout) * time.Second
+	pdClient := api.NewPDClient(ctx, topo.GetPDListWithManageHost(), statusTimeout, tlsCfg)
+
+	lowSpaceRatio := defaultLowSpaceRatio
+	if cfg, err := pdClient.GetConfig(); err != nil {
+		m.logger.Debugf("get config from pd failed: %v", err)
+	} else if v, ok := cfg["schedule.low-space-ratio"].(float64); ok && v > 0 {
+		lowSpaceRatio = v
+	}
+
+	// sample the region size twice to estimate the growth trend, unless the
+	// interval is set to 0
+	first, err := pdClient.GetStores()
+	if err != nil {
+		return perrs.Annotate(err, "get stores from pd")
+	}
+	last, elapsed := first, time.Duration(0)
+	if dopt.CapacitySampleInterval > 0 {
+		m.logger.Infof("Sampling region size from PD in %s...", dopt.CapacitySampleInterval)
+		start := time.Now()
+		time.Sleep(dopt.CapacitySampleInterval)
+		if last, err = pdClient.GetStores(); err != nil {
+			return perrs.Annotate(err, "get stores from pd")
+		}
+		elapsed = time.Since(start)
+	}
+
+	instances := filterInstances(topo, opt)
+
+	var storeInfos []StoreCapacityInfo
+	for _, ins := range instances {
+		addr := storeAddress(ins)
+		if addr == "" {
+			continue
+		}
+		info := StoreCapacityInfo{
+			ID:     ins.ID(),
+			Role:   ins.Role(),
+			Host:   ins.GetHost(),
+			Status: "N/A",
+			ETA:    "-",
+		}
+		if store := findStore(last, addr); store != nil {
+			info.Store = store.Store.GetId()
+			info.Status = store.Store.StateName
+			info.Capacity = uint64(store.Status.Capacity)
+			info.Available = uint64(store.Status.Available)
+			info.UsedSize = uint64(store.Status.UsedSize)
+			info.RegionSize = uint64(store.Status.RegionSize) * units.MiB
+			if prev := findStore(first, addr); prev != nil && elapsed > 0 {
+				delta := float64(store.Status.RegionSize-prev.Status.RegionSize) * units.MiB
+				info.GrowthRate = delta / elapsed.Hours()
+			}
+			fillStoreHeadroom(&info, lowSpaceRatio)
+		}
+		storeInfos = append(storeInfos, info)
+	}
+
+	hostInfos := m.collectHostCapacity(ctx, instances, opt.Concurrency)
+
+	if m.logger.GetDisplayMode() == logprinter.DisplayModeJSON {
+		d, err := json.MarshalIndent(CapacityJSONOutput{
+			ClusterName:   name,
+			LowSpaceRatio: lowSpaceRatio,
+			Stores:        storeInfos,
+			Hosts:         hostInfos,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(d))
+		return nil
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold)
+	fmt.Printf("Cluster type:       %s\n", cyan.Sprint(m.sysName))
+	fmt.Printf("Cluster name:       %s\n", cyan.Sprint(name))
+	fmt.Printf("Cluster version:    %s\n", cyan.Sprint(base.Version))
+	fmt.Printf("Low space ratio:    %s\n", cyan.Sprint(strconv.FormatFloat(lowSpaceRatio, 'f', -1, 64)))
+
+	storeTable := [][]string{{"ID", "Role", "Store", "Status", "Capacity", "Available", "Used Size", "Used", "Region Size", "Growth/h", "Headroom", "ETA"}}
+	for _, s := range storeInfos {
+		used := formatUsedRatio(s.UsedRatio())
+		switch {
+		case s.LowSpace:
+			used = color.RedString(used)
+		case s.NearLow:
+			used = color.YellowString(used)
+		}
+		growth := "-"
+		if elapsed > 0 {
+			growth = units.BytesSize(s.GrowthRate)
+		}
+		storeTable = append(storeTable, []string{
+			color.CyanString(s.ID),
+			s.Role,
+			strconv.FormatUint(s.Store, 10),
+			formatInstanceStatus(s.Status),
+			units.BytesSize(float64(s.Capacity)),
+			units.BytesSize(float64(s.Available)),
+			units.BytesSize(float64(s.UsedSize)),
+			used,
+			units.BytesSize(float64(s.RegionSize)),
+			growth,
+			units.BytesSize(float64(s.Headroom)),
+			s.ETA,
+		})
+	}
+	fmt.Println()
+	tui.PrintTable(storeTable, true)
+
+	hostTable := [][]string{{"Host", "Filesystem", "Mounted On", "Size", "Used", "Available", "Use%", "Instances"}}
+	for _, h := range hostInfos {
+		hostTable = append(hostTable, []string{
+			color.CyanString(h.Host),
+			h.Filesystem,
+			h.MountPoint,
+			units.BytesSize(float64(h.Size)),
+			units.BytesSize(float64(h.Used)),
+			units.BytesSize(float64(h.Available)),
+			formatUsedRatio(h.UsedRatio()),
+			strings.Join(h.Instances, ","),
+		})
+	}
+	fmt.Println()
+	tui.PrintTable(hostTable, true)
+
+	for _, s := range storeInfos {
+		if s.LowSpace {
+			color.Red("WARN: store %d (%s) has reached the low-space-ratio %v", s.Store, s.ID, lowSpaceRatio)
+		} else if s.NearLow {
+			color.Yellow("WARN: store %d (%s) is approaching the low-space-ratio %v", s.Store, s.ID, lowSpaceRatio)
+		}
+	}
+
+	return nil
+}
+
+// storeAddress returns the address an instance registers to PD as a store,
+// or empty string if the instance is not a store.
+func storeAddress(ins spec.Instance) string {
+	switch i := ins.(type) {
+	case *spec.TiKVInstance:
+		return utils.JoinHostPort(i.GetHost(), i.GetPort())
+	case *spec.TiFlashInstance:
+		return utils.JoinHostPort(i.GetHost(), i.GetServicePort())
+	}
+	return ""
+}
+
+// findStore returns the non-tombstone store of the address
+func findStore(stores *api.StoresInfo, addr string) *api.StoreInfo {
+	var found *api.StoreInfo
+	for _, s := range stores.Stores {
+		if s.Store.GetAddress() != addr {
+			continue
+		}
+		if found == nil || s.Store.StateName != "Tombstone" {
+			found = s
+		}
+	}
+	return found
+}
+
+// fillStoreHeadroom calculates how much data the store is able to take before
+// reaching the low-space-ratio, and when it will be reached with the current
+// growth rate.
+func fillStoreHeadroom(s *StoreCapacityInfo, lowSpaceRatio float64) {
+	if s.Capacity == 0 {
+		return
+	}
+	reserved := uint64(math.Round(float64(s.Capacity) * (1 - lowSpaceRatio)))
+	if s.Available > reserved {
+		s.Headroom = s.Available - reserved
+	}
+	used := s.UsedRatio()
+	s.LowSpace = s.Headroom == 0
+	s.NearLow = !s.LowSpace && used >= lowSpaceRatio-nearLowSpaceMargin
+
+	switch {
+	case s.LowSpace:
+		s.ETA = "reached"
+	case s.GrowthRate > 0:
+		hours := float64(s.Headroom) / s.GrowthRate
+		s.ETA = formatInstanceSince(time.Duration(hours * float64(time.Hour)).Truncate(time.Minute))
+	}
+}
+
+// collectHostCapacity collects the usage of filesystems the data dirs of
+// instances located on, instances sharing the same filesystem on a host are
+// merged into one record.
+func (m *Manager) collectHostCapacity(ctx context.Context, instances []spec.Instance, concurrency int) []HostCapacityInfo {
+	var (
+		mu      sync.Mutex
+		records = make(map[string]*HostCapacityInfo)
+	)
+
+	forEachInstance(instances, concurrency, func(ins spec.Instance) {
+		e, found := ctxt.GetInner(ctx).GetExecutor(ins.GetManageHost())
+		if !found {
+			return
+		}
+		for _, dir := range instanceDataDirs(ins) {
+			du, err := operator.GetDiskUsage(ctx, e, dir)
+			if err != nil {
+				// directories may not exist if the instance is never
+				// started, just log it and display what we have
+				m.logger.Debugf("get disk usage of %s:%s failed: %v", ins.GetManageHost(), dir, err)
+				continue
+			}
+			mu.Lock()
+			key := ins.GetHost() + "|" + du.Filesystem + "|" + du.MountPoint
+			r, ok := records[key]
+			if !ok {
+				r = &HostCapacityInfo{Host: ins.GetHost(), DiskUsage: *du}
+				records[key] = r
+			}
+			if !slices.Contains(r.Instances, ins.ID()) {
+				r.Instances = append(r.Instances, ins.ID())
+			}
+			mu.Unlock()
+		}
+	})
+
+	result := make([]HostCapacityInfo, 0, len(records))
+	for _, r := range records {
+		sort.Strings(r.Instances)
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Host != result[j].Host {
+			return result[i].Host < result[j].Host
+		}
+		return result[i].MountPoint < result[j].MountPoint
+	})
+	return result
+}
+
+// instanceDataDirs returns the data dirs of the instance, TiFlash may have
+// multiple data dirs separated by comma.
+func instanceDataDirs(ins spec.Instance) []string {
+	dirs := ins.UsedDirs()
+	if len(dirs) < 2 || dirs[1] == "" {
+		return nil
+	}
+	var result []string
+	for dir := range strings.SplitSeq(dirs[1], ",") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			result = append(result, dir)
+		}
+	}
+	return result
+}
+
+func formatUsedRatio(ratio float64) string {
+	return fmt.Sprintf("%.1f%%", ratio*100)
+}
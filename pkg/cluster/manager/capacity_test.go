@@ -0,0 +1,55 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/docker/go-units"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFillStoreHeadroom(t *testing.T) {
+	s := StoreCapacityInfo{
+		Capacity:   100 * units.GiB,
+		Available:  50 * units.GiB,
+		GrowthRate: 10 * units.GiB,
+		ETA:        "-",
+	}
+	fillStoreHeadroom(&s, 0.8)
+	assert.Equal(t, uint64(30*units.GiB), s.Headroom)
+	assert.False(t, s.LowSpace)
+	assert.False(t, s.NearLow)
+	assert.Equal(t, "3h", s.ETA)
+
+	s = StoreCapacityInfo{
+		Capacity:  100 * units.GiB,
+		Available: 22 * units.GiB,
+		ETA:       "-",
+	}
+	fillStoreHeadroom(&s, 0.8)
+	assert.False(t, s.LowSpace)
+	assert.True(t, s.NearLow)
+	assert.Equal(t, "-", s.ETA)
+
+	s = StoreCapacityInfo{
+		Capacity:  100 * units.GiB,
+		Available: 10 * units.GiB,
+		ETA:       "-",
+	}
+	fillStoreHeadroom(&s, 0.8)
+	assert.Equal(t, uint64(0), s.Headroom)
+	assert.True(t, s.LowSpace)
+	assert.Equal(t, "reached", s.ETA)
+}
@@ -0,0 +1,135 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/tui"
+	"gopkg.in/yaml.v3"
+)
+
+// CloneCluster generates a topology file for a new cluster with the same
+// configs as the source cluster, but deployed on the hosts mapped from the
+// hosts of the source cluster. The hosts map file is a yaml map from the hosts
+// of the source cluster to the new hosts. The topology is written to output,
+// or printed if output is empty.
+func (m *Manager) CloneCluster(src, dst, hostsMapFile, output string) error {
+	topo, version, err := m.diffMeta(src)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(hostsMapFile)
+	if err != nil {
+		return perrs.Annotatef(err, "read hosts map %s", hostsMapFile)
+	}
+	hostsMap := make(map[string]string)
+	if err := yaml.Unmarshal(data, &hostsMap); err != nil {
+		return perrs.Annotatef(err, "parse hosts map %s", hostsMapFile)
+	}
+
+	if err := clusterutil.ValidateClusterNameOrError(dst); err != nil {
+		return err
+	}
+	exist, err := m.specManager.Exist(dst)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return errDeployNameDuplicate.
+			New("Cluster name '%s' is duplicated", dst).
+			WithProperty(tui.SuggestionFromFormat("Please specify another cluster name"))
+	}
+
+	cloned, err := cloneTopology(topo, hostsMap)
+	if err != nil {
+		return err
+	}
+	data, err = yaml.Marshal(cloned)
+	if err != nil {
+		return perrs.AddStack(err)
+	}
+
+	// make sure the generated topology is valid to deploy
+	if err := yaml.Unmarshal(data, &spec.Specification{}); err != nil {
+		return perrs.Annotate(err, "the cloned topology is invalid")
+	}
+
+	if output == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return perrs.AddStack(err)
+	}
+	m.logger.Infof("Topology of cluster `%s` is cloned to %s, deploy it with:", src, output)
+	m.logger.Infof("%s", color.CyanString("\t%s deploy %s %s %s", tui.OsArgs0(), dst, version, output))
+	return nil
+}
+
+// cloneTopology returns a deep copy of the topology with the hosts replaced
+// by hostsMap, instances being offlined are not cloned
+func cloneTopology(topo *spec.Specification, hostsMap map[string]string) (*spec.Specification, error) {
+	data, err := yaml.Marshal(topo)
+	if err != nil {
+		return nil, perrs.AddStack(err)
+	}
+	cloned := &spec.Specification{}
+	if err := yaml.Unmarshal(data, cloned); err != nil {
+		return nil, perrs.AddStack(err)
+	}
+
+	unmapped := make(map[string]struct{})
+	err = forEachServerSlice(cloned, func(_ string, servers reflect.Value) error {
+		kept := reflect.MakeSlice(servers.Type(), 0, servers.Len())
+		for i := 0; i < servers.Len(); i++ {
+			s := servers.Index(i).Elem()
+			if offline := s.FieldByName("Offline"); offline.IsValid() && offline.Bool() {
+				continue
+			}
+			host := s.FieldByName("Host").String()
+			newHost, ok := hostsMap[host]
+			if !ok {
+				unmapped[host] = struct{}{}
+				continue
+			}
+			moveSpecHost(s, host, newHost)
+			kept = reflect.Append(kept, s.Addr())
+		}
+		servers.Set(kept)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(unmapped) > 0 {
+		hosts := make([]string, 0, len(unmapped))
+		for h := range unmapped {
+			hosts = append(hosts, h)
+		}
+		sort.Strings(hosts)
+		return nil, perrs.Errorf("hosts %s are not found in the hosts map", strings.Join(hosts, ", "))
+	}
+	return cloned, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCloneTopology(t *testing.T) {
+	topo := &spec.Specification{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+server_configs:
+  tikv:
+    storage.reserve-space: 2GiB
+pd_servers:
+  - host: 172.16.5.1
+tikv_servers:
+  - host: 172.16.5.1
+    config:
+      server.grpc-concurrency: 8
+  - host: 172.16.5.2
+    advertise_addr: 172.16.5.2:20160
+  - host: 172.16.5.3
+    offline: true
+monitoring_servers:
+  - host: 172.16.5.1
+`), topo))
+
+	_, err := cloneTopology(topo, map[string]string{"172.16.5.1": "172.16.6.1"})
+	require.ErrorContains(t, err, "172.16.5.2")
+
+	cloned, err := cloneTopology(topo, map[string]string{
+		"172.16.5.1": "172.16.6.1",
+		"172.16.5.2": "172.16.6.2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2GiB", cloned.ServerConfigs.TiKV["storage.reserve-space"])
+	require.Len(t, cloned.PDServers, 1)
+	assert.Equal(t, "172.16.6.1", cloned.PDServers[0].Host)
+	require.Len(t, cloned.TiKVServers, 2)
+	assert.Equal(t, "172.16.6.1", cloned.TiKVServers[0].Host)
+	assert.Equal(t, 8, cloned.TiKVServers[0].Config["server.grpc-concurrency"])
+	assert.Equal(t, "172.16.6.2", cloned.TiKVServers[1].Host)
+	assert.Equal(t, "172.16.6.2:20160", cloned.TiKVServers[1].AdvertiseAddr)
+	assert.Equal(t, "172.16.6.1", cloned.Monitors[0].Host)
+
+	// the source topology is not changed
+	assert.Equal(t, "172.16.5.2", topo.TiKVServers[1].Host)
+
+	data, err := yaml.Marshal(cloned)
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, &spec.Specification{}))
+}
@@ -45,6 +45,7 @@ type DeployOptions struct {
 	NoLabels       bool   // don't check labels for TiKV instance
 	Stage1         bool   // don't start the new instance, just deploy
 	Stage2         bool   // start instances and init Config after stage1
+	NoRollback     bool   // don't undo the completed steps on failure
 }
 
 // DeployerInstance is a instance can deploy to a target deploy directory.
@@ -78,6 +79,12 @@ func (m *Manager) Deploy(
 			WithProperty(tui.SuggestionFromFormat("Please specify another cluster name"))
 	}
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata := m.specManager.NewMetadata()
 	topo := metadata.GetTopology()
 
@@ -93,7 +100,8 @@ func (m *Manager) Deploy(
 	topo.IterInstance(func(inst spec.Instance) {
 		switch inst.ComponentName() {
 		// monitoring components are only useful when deployed with
-		// core components, we do not support deploying any bare
+		// core components, or with the external endpoints of a cluster
+		// not managed by TiUP, we do not support deploying any bare
 		// monitoring system.
 		case spec.ComponentGrafana,
 			spec.ComponentPrometheus,
@@ -102,6 +110,9 @@ func (m *Manager) Deploy(
 		}
 		instCnt++
 	})
+	if topo, ok := topo.(*spec.Specification); ok && topo.IsMonitoringOnly() {
+		instCnt = len(topo.Monitors)
+	}
 	if instCnt < 1 {
 		return fmt.Errorf("no valid instance found in the input topology, please check your config")
 	}
@@ -115,6 +126,12 @@ func (m *Manager) Deploy(
 
 	if topo, ok := topo.(*spec.Specification); ok {
 		topo.AdjustByVersion(clusterVersion)
+		if err := topo.ApplyPlacementLabels(); err != nil {
+			return err
+		}
+		if err := topo.CheckTemplates(); err != nil {
+			return err
+		}
 		if !opt.NoLabels {
 			// Check if TiKV's label set correctly
 			lbs, err := topo.LocationLabels()
@@ -180,6 +197,11 @@ func (m *Manager) Deploy(
 			Wrap(err, "Failed to create cluster metadata directory '%s'", m.specManager.Path(name)).
 			WithProperty(tui.SuggestionFromString("Please check file system permissions and try again."))
 	}
+	if topo, ok := topo.(*spec.Specification); ok {
+		if err := topo.CopyTemplates(m.specManager.Path(name, spec.TemplatesDirName)); err != nil {
+			return err
+		}
+	}
 
 	var (
 		envInitTasks      []*task.StepDisplay // tasks which are used to initialize environment
@@ -395,7 +417,9 @@ func (m *Manager) Deploy(
 		gOpt.Concurrency,
 		m.logger,
 	)
+	ctx, journal := task.WithJournal(ctx)
 	if err := t.Execute(ctx); err != nil {
+		m.rollback(ctx, journal, opt.NoRollback)
 		if errorx.Cast(err) != nil {
 			// FIXME: Map possible task errors and give suggestions.
 			return err
@@ -418,3 +442,20 @@ func (m *Manager) Deploy(
 	m.logger.Infof("Cluster `%s` deployed successfully, you can start it with command: `%s`", name, hint)
 	return nil
 }
+
+// rollback undoes the changes recorded in the journal after a failure
+func (m *Manager) rollback(ctx context.Context, journal *task.Journal, noRollback bool) {
+	if journal.Len() == 0 {
+		return
+	}
+	if noRollback {
+		m.logger.Warnf("Skip rolling back %d changes made on hosts as --no-rollback is set", journal.Len())
+		return
+	}
+	m.logger.Warnf("Rolling back %d changes made on hosts, use --no-rollback to keep them for debugging", journal.Len())
+	if err := journal.Rollback(ctx); err != nil {
+		m.logger.Errorf("%s", err)
+		return
+	}
+	m.logger.Infof("Rollback finished")
+}
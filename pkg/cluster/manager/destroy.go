@@ -37,6 +37,12 @@ func (m *Manager) DestroyCluster(name string, gOpt operator.Options, destroyOpt
 		return err
 	}
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) &&
 		!errors.Is(perrs.Cause(err), spec.ErrNoTiSparkMaster) &&
@@ -53,7 +59,21 @@ func (m *Manager) DestroyCluster(name string, gOpt operator.Options, destroyOpt
 		return err
 	}
 
+	var archiveTarget *archiveTarget
+	if destroyOpt.ArchiveTo != "" {
+		if archiveTarget, err = parseArchiveTarget(destroyOpt.ArchiveTo); err != nil {
+			return err
+		}
+		archiveTarget.SSHPort = destroyOpt.ArchiveSSHPort
+		archiveTarget.SSHKey = destroyOpt.ArchiveSSHKey
+	}
+
 	if !skipConfirm {
+		archiveNote := ""
+		if archiveTarget != nil {
+			archiveNote = fmt.Sprintf("\nData and logs will be archived to %s before destroying.",
+				color.HiYellowString(archiveTarget.String()))
+		}
 		m.logger.Warnf("%s", color.HiRedString(tui.ASCIIArtWarning))
 		if err := tui.PromptForAnswerOrAbortError(
 			"Yes, I know my cluster and data will be deleted.",
@@ -61,7 +81,7 @@ func (m *Manager) DestroyCluster(name string, gOpt operator.Options, destroyOpt
 				m.sysName,
 				color.HiYellowString(base.Version),
 				color.HiYellowString(name),
-			)+"\nAre you sure to continue?",
+			)+archiveNote+"\nAre you sure to continue?",
 		); err != nil {
 			return err
 		}
@@ -72,16 +92,21 @@ func (m *Manager) DestroyCluster(name string, gOpt operator.Options, destroyOpt
 	if err != nil {
 		return err
 	}
+	b.Func("StopCluster", func(ctx context.Context) error {
+		return operator.Stop(
+			ctx,
+			topo,
+			operator.Options{Force: destroyOpt.Force},
+			false, /* eviceLeader */
+			tlsCfg,
+		)
+	})
+	if archiveTarget != nil {
+		b.Func("ArchiveCluster", func(ctx context.Context) error {
+			return m.archiveCluster(ctx, name, base.User, base.Version, topo, archiveTarget, gOpt)
+		})
+	}
 	t := b.
-		Func("StopCluster", func(ctx context.Context) error {
-			return operator.Stop(
-				ctx,
-				topo,
-				operator.Options{Force: destroyOpt.Force},
-				false, /* eviceLeader */
-				tlsCfg,
-			)
-		}).
 		Func("DestroyCluster", func(ctx context.Context) error {
 			return operator.Destroy(ctx, topo, destroyOpt)
 		}).
@@ -114,6 +139,12 @@ func (m *Manager) DestroyTombstone(
 	gOpt operator.Options,
 	skipConfirm bool,
 ) error {
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	// allow specific validation errors so that user can recover a broken
 	// cluster if it is somehow in a bad state.
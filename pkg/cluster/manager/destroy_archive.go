@@ -0,0 +1,594 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	"github.com/pingcap/tiup/pkg/cluster/executor"
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+const (
+	// archiveManifestName is the manifest file listing the archived tarballs
+	archiveManifestName = "manifest.json"
+	// archiveTimeout is the timeout of packing and checksumming a directory,
+	// which may take long for large data directories
+	archiveTimeout = 12 * time.Hour
+)
+
+// ArchiveEntry is a tarball of a directory of an instance
+type ArchiveEntry struct {
+	Instance string `json:"instance"`
+	Role     string `json:"role"`
+	Host     string `json:"host"`
+	Kind     string `json:"kind"` // data or log
+	Dir      string `json:"dir"`
+	File     string `json:"file"`
+	Size     int64  `json:"size"`
+	SHA256   string `json:"sha256"`
+	// Missing is set if the directory does not exist on the host
+	Missing bool `json:"missing,omitempty"`
+}
+
+// ArchiveManifest describes the archive of a destroyed cluster
+type ArchiveManifest struct {
+	Cluster   string         `json:"cluster"`
+	Version   string         `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Entries   []ArchiveEntry `json:"entries"`
+	// Partial is set if the archiving fails, the entries are the ones
+	// archived before the failure and the cluster is not destroyed
+	Partial bool `json:"partial,omitempty"`
+}
+
+// verify checks that every data and log directory of the instances is
+// either archived or missing on its host
+func (a *ArchiveManifest) verify(instances []spec.Instance) error {
+	covered := make(map[string]bool, len(a.Entries))
+	for _, entry := range a.Entries {
+		covered[entry.Instance+":"+entry.Dir] = true
+	}
+	for _, ins := range instances {
+		for _, entry := range archiveDirs(ins) {
+			if !covered[entry.Instance+":"+entry.Dir] {
+				return perrs.Errorf("%s dir %s of %s is not archived", entry.Kind, entry.Dir, entry.Instance)
+			}
+		}
+	}
+	return nil
+}
+
+// archiveTarget is where the archive is saved to, one of a local directory,
+// a directory on a remote host or an S3 bucket
+type archiveTarget struct {
+	Host string
+	Path string
+	S3   *url.URL
+	// SSHPort and SSHKey are used to connect the remote host, the port 22
+	// and the SSH key of the cluster are used if not set
+	SSHPort int
+	SSHKey  string
+}
+
+// parseArchiveTarget parses the target in the form of /path, host:/path or
+// s3://bucket/prefix?endpoint=host:port
+func parseArchiveTarget(target string) (*archiveTarget, error) {
+	if strings.HasPrefix(target, "s3://") {
+		u, err := url.Parse(target)
+		if err != nil {
+			return nil, perrs.Annotatef(err, "parse archive target %s", target)
+		}
+		if u.Host == "" {
+			return nil, perrs.Errorf("bucket of the archive target %s is not specified", target)
+		}
+		return &archiveTarget{S3: u}, nil
+	}
+
+	host, dir := "", target
+	if i := strings.LastIndex(target, ":"); i >= 0 {
+		host, dir = target[:i], target[i+1:]
+	}
+	if !strings.HasPrefix(dir, "/") {
+		return nil, perrs.Errorf("the path of archive target %s must be absolute", target)
+	}
+	return &archiveTarget{Host: strings.Trim(host, "[]"), Path: filepath.Clean(dir)}, nil
+}
+
+// String implements the fmt.Stringer interface
+func (t *archiveTarget) String() string {
+	switch {
+	case t.S3 != nil:
+		// the credentials may be passed in parameters
+		u := *t.S3
+		params := u.Query()
+		params.Del("access-key")
+		params.Del("secret-access-key")
+		u.RawQuery = params.Encode()
+		return u.Redacted()
+	case t.Host != "":
+		return t.Host + ":" + t.Path
+	default:
+		return t.Path
+	}
+}
+
+// archiveSink saves the files streamed to the target
+type archiveSink interface {
+	// create starts writing the file to the target
+	create(ctx context.Context, file string) (archiveWriter, error)
+	close() error
+}
+
+// archiveWriter writes a file to the target, the file is saved only after
+// it's committed
+type archiveWriter interface {
+	io.Writer
+	// commit finishes the file, the entry is nil for the manifest
+	commit(ctx context.Context, entry *ArchiveEntry) error
+	// abort drops the file written partially
+	abort()
+}
+
+// newArchiveSink creates the sink of the target, executors of remote hosts
+// use the SSH port and key of the target, or the SSH key of the cluster
+func (m *Manager) newArchiveSink(ctx context.Context, name, user string, target *archiveTarget, gOpt operator.Options, topo spec.Topology) (archiveSink, error) {
+	switch {
+	case target.S3 != nil:
+		return newS3ArchiveSink(target.S3)
+	case target.Host == "":
+		if err := utils.MkdirAll(target.Path, 0755); err != nil {
+			return nil, perrs.Annotatef(err, "create archive directory %s", target.Path)
+		}
+		return &localArchiveSink{dir: target.Path}, nil
+	}
+
+	sshType := gOpt.SSHType
+	if sshType == "" {
+		sshType = topo.BaseTopo().GlobalOptions.SSHType
+	}
+	port := target.SSHPort
+	if port == 0 {
+		port = 22
+	}
+	keyFile := target.SSHKey
+	if keyFile == "" {
+		keyFile = m.specManager.Path(name, "ssh", "id_rsa")
+	}
+	e, err := executor.New(sshType, false, executor.SSHConfig{
+		Host:    target.Host,
+		Port:    port,
+		KeyFile: keyFile,
+		User:    user,
+		Timeout: time.Second * time.Duration(gOpt.SSHTimeout),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, stderr, err := e.Execute(ctx, "mkdir -p "+shellQuote(target.Path), false); err != nil {
+		return nil, perrs.Annotatef(err, "create archive directory %s: %s", target, stderr)
+	}
+	return &hostArchiveSink{dir: target.Path, exec: e}, nil
+}
+
+// localArchiveSink saves the files to a local directory
+type localArchiveSink struct {
+	dir string
+}
+
+func (s *localArchiveSink) create(_ context.Context, file string) (archiveWriter, error) {
+	dst := filepath.Join(s.dir, file)
+	f, err := os.Create(dst + ".tmp")
+	if err != nil {
+		return nil, perrs.AddStack(err)
+	}
+	return &localArchiveWriter{File: f, dst: dst}, nil
+}
+
+func (s *localArchiveSink) close() error {
+	return nil
+}
+
+// localArchiveWriter writes to a temporary file renamed when committed
+type localArchiveWriter struct {
+	*os.File
+	dst string
+}
+
+func (w *localArchiveWriter) commit(context.Context, *ArchiveEntry) error {
+	if err := w.Close(); err != nil {
+		w.abort()
+		return perrs.AddStack(err)
+	}
+	return perrs.AddStack(os.Rename(w.Name(), w.dst))
+}
+
+func (w *localArchiveWriter) abort() {
+	_ = w.Close()
+	_ = os.Remove(w.Name())
+}
+
+// pipeArchiveWriter passes the data written to the upload running in
+// background
+type pipeArchiveWriter struct {
+	*io.PipeWriter
+	done chan error
+	// finish is called after the upload succeeds, drop is called if the
+	// file is aborted or fails to be finished
+	finish func(ctx context.Context, entry *ArchiveEntry) error
+	drop   func()
+}
+
+func newPipeArchiveWriter(upload func(r io.Reader) error) *pipeArchiveWriter {
+	pr, pw := io.Pipe()
+	w := &pipeArchiveWriter{
+		PipeWriter: pw,
+		done:       make(chan error, 1),
+		finish:     func(context.Context, *ArchiveEntry) error { return nil },
+		drop:       func() {},
+	}
+	go func() {
+		err := upload(pr)
+		// fail the writes if the upload stops early
+		pr.CloseWithError(err)
+		w.done <- err
+	}()
+	return w
+}
+
+func (w *pipeArchiveWriter) commit(ctx context.Context, entry *ArchiveEntry) error {
+	_ = w.Close()
+	err := <-w.done
+	if err == nil {
+		err = w.finish(ctx, entry)
+	}
+	if err != nil {
+		w.drop()
+	}
+	return err
+}
+
+func (w *pipeArchiveWriter) abort() {
+	_ = w.CloseWithError(errArchiveAborted)
+	<-w.done
+	w.drop()
+}
+
+// errArchiveAborted is the error read by the upload if the file is aborted
+var errArchiveAborted = perrs.New("archive aborted")
+
+// hostArchiveSink streams the files to a directory on a remote host
+type hostArchiveSink struct {
+	dir  string
+	exec ctxt.Executor
+}
+
+func (s *hostArchiveSink) create(ctx context.Context, file string) (archiveWriter, error) {
+	dst := path.Join(s.dir, file)
+	tmp := dst + ".tmp"
+	w := newPipeArchiveWriter(func(r io.Reader) error {
+		stderr, err := executor.ExecuteStream(ctx, s.exec, "cat > "+shellQuote(tmp), false, r, io.Discard, archiveTimeout)
+		return perrs.Annotatef(err, "write %s to archive target: %s", file, stderr)
+	})
+	w.finish = func(ctx context.Context, entry *ArchiveEntry) error {
+		if entry != nil {
+			sum, _, err := remoteChecksum(ctx, s.exec, tmp, false)
+			if err != nil {
+				return err
+			}
+			if sum != entry.SHA256 {
+				return perrs.Errorf("checksum mismatch of %s written to archive target, expected %s but got %s", file, entry.SHA256, sum)
+			}
+		}
+		_, stderr, err := s.exec.Execute(ctx, fmt.Sprintf("mv -f %s %s", shellQuote(tmp), shellQuote(dst)), false)
+		return perrs.Annotatef(err, "save %s to archive target: %s", file, stderr)
+	}
+	w.drop = func() {
+		_, _, _ = s.exec.Execute(context.Background(), "rm -f "+shellQuote(tmp), false)
+	}
+	return w, nil
+}
+
+func (s *hostArchiveSink) close() error {
+	return nil
+}
+
+// archivePartSize is the part size of multipart uploads to S3, the size of
+// the data streamed is unknown so it limits the object to 10000 parts
+const archivePartSize = 128 << 20
+
+// s3ArchiveSink streams the files to an S3-compatible object storage
+type s3ArchiveSink struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func newS3ArchiveSink(u *url.URL) (*s3ArchiveSink, error) {
+	params := u.Query()
+	endpoint := params.Get("endpoint")
+	if endpoint == "" {
+		endpoint = "s3.amazonaws.com"
+	}
+	accessKey := params.Get("access-key")
+	if accessKey == "" {
+		accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	secretKey := params.Get("secret-access-key")
+	if secretKey == "" {
+		secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: params.Get("insecure") != "true",
+		Region: params.Get("region"),
+	})
+	if err != nil {
+		return nil, perrs.Annotatef(err, "connect to s3 endpoint %s", endpoint)
+	}
+	return &s3ArchiveSink{
+		client: client,
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (s *s3ArchiveSink) create(ctx context.Context, file string) (archiveWriter, error) {
+	key := path.Join(s.prefix, file)
+	// the multipart upload is aborted if it fails, and the object is not
+	// visible until the upload completes
+	w := newPipeArchiveWriter(func(r io.Reader) error {
+		_, err := s.client.PutObject(ctx, s.bucket, key, r, -1, minio.PutObjectOptions{PartSize: archivePartSize})
+		return perrs.Annotatef(err, "upload %s to s3://%s/%s", file, s.bucket, key)
+	})
+	w.finish = func(ctx context.Context, entry *ArchiveEntry) error {
+		if entry == nil {
+			return nil
+		}
+		info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+		if err != nil {
+			return perrs.Annotatef(err, "stat s3://%s/%s", s.bucket, key)
+		}
+		if info.Size != entry.Size {
+			return perrs.Errorf("size mismatch of s3://%s/%s, expected %d but got %d", s.bucket, key, entry.Size, info.Size)
+		}
+		return nil
+	}
+	w.drop = func() {
+		_ = s.client.RemoveObject(context.Background(), s.bucket, key, minio.RemoveObjectOptions{})
+	}
+	return w, nil
+}
+
+func (s *s3ArchiveSink) close() error {
+	return nil
+}
+
+// remoteChecksum returns the sha256 and size of the file on the host
+func remoteChecksum(ctx context.Context, e ctxt.Executor, file string, sudo bool) (string, int64, error) {
+	cmd := fmt.Sprintf("sha256sum %s && stat -c %%s %s", shellQuote(file), shellQuote(file))
+	stdout, stderr, err := e.Execute(ctx, cmd, sudo, archiveTimeout)
+	if err != nil {
+		return "", 0, perrs.Annotatef(err, "checksum %s: %s", file, stderr)
+	}
+	fields := strings.Fields(string(stdout))
+	if len(fields) < 3 {
+		return "", 0, perrs.Errorf("unexpected output of checksum %s: %s", file, stdout)
+	}
+	size, err := strconv.ParseInt(fields[len(fields)-1], 10, 64)
+	if err != nil {
+		return "", 0, perrs.Annotatef(err, "parse size of %s", file)
+	}
+	return fields[0], size, nil
+}
+
+// archiveDirs returns the data and log directories of the instance to archive
+func archiveDirs(ins spec.Instance) []ArchiveEntry {
+	r := strings.NewReplacer(":", "-", "[", "", "]", "")
+	prefix := fmt.Sprintf("%s-%s", ins.Role(), r.Replace(ins.ID()))
+
+	var entries []ArchiveEntry
+	add := func(kind, dir, file string) {
+		entries = append(entries, ArchiveEntry{
+			Instance: ins.ID(),
+			Role:     ins.Role(),
+			Host:     ins.GetManageHost(),
+			Kind:     kind,
+			Dir:      dir,
+			File:     file,
+		})
+	}
+
+	var dataDirs []string
+	for dir := range strings.SplitSeq(ins.DataDir(), ",") {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			dataDirs = append(dataDirs, dir)
+		}
+	}
+	for i, dir := range dataDirs {
+		file := prefix + "-data.tar.gz"
+		if len(dataDirs) > 1 {
+			file = fmt.Sprintf("%s-data-%d.tar.gz", prefix, i)
+		}
+		add("data", dir, file)
+	}
+	if dir := ins.LogDir(); dir != "" {
+		add("log", dir, prefix+"-log.tar.gz")
+	}
+	return entries
+}
+
+// archiveCluster archives the data and log directories of all instances of
+// the stopped cluster to the target, with a manifest listing the checksums
+// of the tarballs. Each directory is packed by tar on the host and streamed
+// to the target directly, the checksum is calculated from the stream.
+func (m *Manager) archiveCluster(ctx context.Context, name, user, version string, topo spec.Topology, target *archiveTarget, gOpt operator.Options) error {
+	sink, err := m.newArchiveSink(ctx, name, user, target, gOpt, topo)
+	if err != nil {
+		return err
+	}
+	defer sink.close()
+
+	var instances []spec.Instance
+	topo.IterInstance(func(ins spec.Instance) {
+		instances = append(instances, ins)
+	})
+
+	manifest := ArchiveManifest{
+		Cluster:   name,
+		Version:   version,
+		CreatedAt: time.Now(),
+	}
+	var (
+		mu       sync.Mutex
+		firstErr error
+	)
+	forEachInstance(instances, gOpt.Concurrency, func(ins spec.Instance) {
+		e, found := ctxt.GetInner(ctx).GetExecutor(ins.GetManageHost())
+		if !found {
+			mu.Lock()
+			firstErr = perrs.Errorf("no executor found for host %s", ins.GetManageHost())
+			mu.Unlock()
+			return
+		}
+		for _, entry := range archiveDirs(ins) {
+			archived, err := m.archiveDir(ctx, e, sink, entry)
+			mu.Lock()
+			if err != nil && firstErr == nil {
+				firstErr = perrs.Annotatef(err, "archive %s dir of %s", entry.Kind, entry.Instance)
+			}
+			if archived != nil {
+				manifest.Entries = append(manifest.Entries, *archived)
+			}
+			mu.Unlock()
+		}
+	})
+	if firstErr == nil {
+		firstErr = manifest.verify(instances)
+	}
+
+	// the tarballs written are kept with a partial manifest if it fails,
+	// and the cluster is not destroyed
+	manifest.Partial = firstErr != nil
+	sort.Slice(manifest.Entries, func(i, j int) bool {
+		return manifest.Entries[i].File < manifest.Entries[j].File
+	})
+	if err := writeArchiveManifest(ctx, sink, &manifest); err != nil {
+		if firstErr != nil {
+			m.logger.Warnf("Failed to write the partial manifest of the archive: %v", err)
+			return firstErr
+		}
+		return err
+	}
+	if firstErr != nil {
+		return perrs.Annotatef(firstErr, "archive of cluster %s to %s is partial, refuse to destroy it", name, target)
+	}
+
+	archived := 0
+	for _, entry := range manifest.Entries {
+		if !entry.Missing {
+			archived++
+		}
+	}
+	m.logger.Infof("Archived %d directories of cluster `%s` to %s", archived, name, target)
+	return nil
+}
+
+// writeArchiveManifest writes the manifest to the sink
+func writeArchiveManifest(ctx context.Context, sink archiveSink, manifest *ArchiveManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return perrs.AddStack(err)
+	}
+	w, err := sink.create(ctx, archiveManifestName)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.abort()
+		return perrs.Annotatef(err, "write %s", archiveManifestName)
+	}
+	return w.commit(ctx, nil)
+}
+
+// archiveDir archives the directory of the entry to the sink, the entry is
+// marked missing if the directory does not exist
+func (m *Manager) archiveDir(ctx context.Context, e ctxt.Executor, sink archiveSink, entry ArchiveEntry) (*ArchiveEntry, error) {
+	// only the failure of test itself, i.e. exit status 1, means the
+	// directory does not exist, other errors fail the command
+	cmd := fmt.Sprintf("if test -d %s; then echo exist; else echo missing; fi", shellQuote(entry.Dir))
+	stdout, stderr, err := e.Execute(ctx, cmd, true)
+	if err != nil {
+		return nil, perrs.Annotatef(err, "check %s: %s", entry.Dir, stderr)
+	}
+	switch strings.TrimSpace(string(stdout)) {
+	case "exist":
+	case "missing":
+		m.logger.Warnf("Skip archiving %s dir %s of %s as it does not exist", entry.Kind, entry.Dir, entry.Instance)
+		entry.Missing = true
+		return &entry, nil
+	default:
+		return nil, perrs.Errorf("unexpected output of checking %s: %s", entry.Dir, stdout)
+	}
+
+	w, err := sink.create(ctx, entry.File)
+	if err != nil {
+		return nil, err
+	}
+	hash := sha256.New()
+	size := &countWriter{}
+	cmd = fmt.Sprintf("tar -czf - -C %s %s", shellQuote(filepath.Dir(entry.Dir)), shellQuote(filepath.Base(entry.Dir)))
+	stderr, err = executor.ExecuteStream(ctx, e, cmd, true, nil, io.MultiWriter(w, hash, size), archiveTimeout)
+	if err != nil {
+		w.abort()
+		return nil, perrs.Annotatef(err, "pack %s: %s", entry.Dir, stderr)
+	}
+	entry.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	entry.Size = size.n
+	if err := w.commit(ctx, &entry); err != nil {
+		return nil, err
+	}
+	m.logger.Infof("Archived %s dir %s of %s (%s)", entry.Kind, entry.Dir, entry.Instance, entry.File)
+	return &entry, nil
+}
+
+// countWriter counts the bytes written
+type countWriter struct {
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseArchiveTarget(t *testing.T) {
+	target, err := parseArchiveTarget("/data/archive/")
+	require.NoError(t, err)
+	assert.Equal(t, &archiveTarget{Path: "/data/archive"}, target)
+
+	target, err = parseArchiveTarget("172.16.5.1:/data/archive")
+	require.NoError(t, err)
+	assert.Equal(t, &archiveTarget{Host: "172.16.5.1", Path: "/data/archive"}, target)
+	assert.Equal(t, "172.16.5.1:/data/archive", target.String())
+
+	target, err = parseArchiveTarget("[fe80::1]:/data/archive")
+	require.NoError(t, err)
+	assert.Equal(t, &archiveTarget{Host: "fe80::1", Path: "/data/archive"}, target)
+
+	target, err = parseArchiveTarget("s3://bucket/prefix?endpoint=172.16.5.1:9000")
+	require.NoError(t, err)
+	require.NotNil(t, target.S3)
+	assert.Equal(t, "bucket", target.S3.Host)
+	assert.Equal(t, "/prefix", target.S3.Path)
+
+	// the credentials are not printed
+	target, err = parseArchiveTarget("s3://bucket/prefix?endpoint=172.16.5.1:9000&access-key=ak&secret-access-key=sk")
+	require.NoError(t, err)
+	assert.Equal(t, "s3://bucket/prefix?endpoint=172.16.5.1%3A9000", target.String())
+
+	_, err = parseArchiveTarget("172.16.5.1:data/archive")
+	require.Error(t, err)
+	_, err = parseArchiveTarget("archive")
+	require.Error(t, err)
+	_, err = parseArchiveTarget("s3:///prefix")
+	require.Error(t, err)
+}
+
+func TestArchiveDirs(t *testing.T) {
+	topo := &spec.Specification{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+tikv_servers:
+  - host: 172.16.5.1
+    data_dir: /data/tikv
+    log_dir: /log/tikv
+tiflash_servers:
+  - host: 172.16.5.2
+    data_dir: /data1/tiflash,/data2/tiflash
+    log_dir: /log/tiflash
+`), topo))
+
+	var files, dirs []string
+	topo.IterInstance(func(ins spec.Instance) {
+		for _, entry := range archiveDirs(ins) {
+			files = append(files, entry.File)
+			dirs = append(dirs, entry.Kind+":"+entry.Dir)
+		}
+	})
+	assert.Equal(t, []string{
+		"tikv-172.16.5.1-20160-data.tar.gz",
+		"tikv-172.16.5.1-20160-log.tar.gz",
+		"tiflash-172.16.5.2-9000-data-0.tar.gz",
+		"tiflash-172.16.5.2-9000-data-1.tar.gz",
+		"tiflash-172.16.5.2-9000-log.tar.gz",
+	}, files)
+	assert.Equal(t, []string{
+		"data:/data/tikv",
+		"log:/log/tikv",
+		"data:/data1/tiflash",
+		"data:/data2/tiflash",
+		"log:/log/tiflash",
+	}, dirs)
+}
+
+func TestArchiveWriters(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	sink := &localArchiveSink{dir: dir}
+
+	w, err := sink.create(ctx, "aborted.tar.gz")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("partial"))
+	require.NoError(t, err)
+	w.abort()
+	w, err = sink.create(ctx, "data.tar.gz")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, w.commit(ctx, nil))
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "data.tar.gz", files[0].Name())
+
+	// the upload gets the data written until it's committed or aborted
+	var uploaded bytes.Buffer
+	var uploadErr error
+	dropped := false
+	newWriter := func() *pipeArchiveWriter {
+		uploaded.Reset()
+		dropped = false
+		pw := newPipeArchiveWriter(func(r io.Reader) error {
+			_, uploadErr = io.Copy(&uploaded, r)
+			return uploadErr
+		})
+		pw.drop = func() { dropped = true }
+		return pw
+	}
+	pw := newWriter()
+	_, err = pw.Write([]byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, pw.commit(ctx, nil))
+	assert.Equal(t, "data", uploaded.String())
+	assert.False(t, dropped)
+
+	pw = newWriter()
+	_, err = pw.Write([]byte("partial"))
+	require.NoError(t, err)
+	pw.abort()
+	assert.Equal(t, errArchiveAborted, uploadErr)
+	assert.True(t, dropped)
+
+	// the file is dropped if it fails to be finished
+	pw = newWriter()
+	pw.finish = func(context.Context, *ArchiveEntry) error { return errArchiveAborted }
+	require.Error(t, pw.commit(ctx, &ArchiveEntry{}))
+	assert.True(t, dropped)
+}
+
+func TestArchiveManifestVerify(t *testing.T) {
+	topo := &spec.Specification{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+tikv_servers:
+  - host: 172.16.5.1
+    data_dir: /data/tikv
+    log_dir: /log/tikv
+`), topo))
+	var instances []spec.Instance
+	topo.IterInstance(func(ins spec.Instance) {
+		instances = append(instances, ins)
+	})
+
+	manifest := &ArchiveManifest{Entries: []ArchiveEntry{
+		{Instance: "172.16.5.1:20160", Kind: "data", Dir: "/data/tikv"},
+	}}
+	require.ErrorContains(t, manifest.verify(instances), "log dir /log/tikv of 172.16.5.1:20160 is not archived")
+
+	// the missing directories are covered as well
+	manifest.Entries = append(manifest.Entries, ArchiveEntry{Instance: "172.16.5.1:20160", Kind: "log", Dir: "/log/tikv", Missing: true})
+	require.NoError(t, manifest.verify(instances))
+}
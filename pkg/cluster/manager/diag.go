@@ -0,0 +1,405 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/api"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/cluster/task"
+	"github.com/pingcap/tiup/pkg/environment"
+	"github.com/pingcap/tiup/pkg/meta"
+	"github.com/pingcap/tiup/pkg/set"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// diagToolsDir is the temporary dir on remote hosts to run the collector
+const diagToolsDir = "/tmp/tiup-diag"
+
+// DiagOptions represents the options of diag collect command
+type DiagOptions struct {
+	Output        string        // path of the output tarball
+	Since         time.Duration // collect log files modified in the duration
+	FileSizeLimit int64         // max bytes collected from a single file, the tail is kept
+	SizeLimit     int64         // max bytes of all collected files
+}
+
+// DiagBundleMeta is the manifest of a diagnostic bundle
+type DiagBundleMeta struct {
+	ClusterName    string           `json:"cluster_name"`
+	ClusterVersion string           `json:"cluster_version"`
+	CreatedAt      time.Time        `json:"created_at"`
+	Since          string           `json:"since,omitempty"`
+	Files          []DiagBundleFile `json:"files"`
+	Errors         []string         `json:"errors,omitempty"`
+}
+
+// DiagBundleFile records a file collected into the bundle
+type DiagBundleFile struct {
+	Path      string `json:"path"`
+	Source    string `json:"source,omitempty"`
+	Size      int    `json:"size"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Redacted  bool   `json:"redacted,omitempty"`
+}
+
+// diagCollector saves collected data into the bundle dir and keeps the
+// manifest, it's safe to be used concurrently.
+type diagCollector struct {
+	dir       string
+	fileLimit int64
+	sizeLimit int64
+
+	mu    sync.Mutex
+	total int64
+	meta  DiagBundleMeta
+}
+
+// add saves data to path relative to the bundle dir, the data is redacted if
+// needed and only the tail is kept if it exceeds the file size limit.
+func (c *diagCollector) add(path, source string, data []byte, redact bool) {
+	record := DiagBundleFile{
+		Path:     path,
+		Source:   source,
+		Redacted: redact,
+	}
+	// the tail may start in the middle of a line, which is dropped before
+	// redacting as the key of a sensitive value may be cut off
+	truncate := func() {
+		if c.fileLimit > 0 && int64(len(data)) > c.fileLimit {
+			data = data[int64(len(data))-c.fileLimit:]
+			if i := bytes.IndexByte(data, '\n'); i >= 0 {
+				data = data[i+1:]
+			} else {
+				data = nil
+			}
+			record.Truncated = true
+		}
+	}
+	truncate()
+	if redact {
+		data = environment.HideSensitiveConfig(data)
+		// the redacted value may be longer than the origin one
+		truncate()
+	}
+
+	c.mu.Lock()
+	if c.sizeLimit > 0 && c.total+int64(len(data)) > c.sizeLimit {
+		c.mu.Unlock()
+		c.fail("skip %s: total size limit reached", path)
+		return
+	}
+	c.total += int64(len(data))
+	c.mu.Unlock()
+
+	dst := filepath.Join(c.dir, path)
+	if err := utils.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		c.fail("write %s: %s", path, err)
+		return
+	}
+	if err := utils.WriteFile(dst, data, 0644); err != nil {
+		c.fail("write %s: %s", path, err)
+		return
+	}
+	record.Size = len(data)
+
+	c.mu.Lock()
+	c.meta.Files = append(c.meta.Files, record)
+	c.mu.Unlock()
+}
+
+// addJSON saves the value as an indented JSON file
+func (c *diagCollector) addJSON(path, source string, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		c.fail("marshal %s: %s", path, err)
+		return
+	}
+	c.add(path, source, data, true)
+}
+
+// fail records an error of collecting, collecting errors don't abort the
+// whole procedure as a partial bundle is still helpful.
+func (c *diagCollector) fail(format string, args ...any) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.meta.Errors = append(c.meta.Errors, fmt.Sprintf(format, args...))
+}
+
+// DiagCollect gathers topology, configs, system info, PD status and recent
+// logs of the cluster into a tarball for troubleshooting.
+func (m *Manager) DiagCollect(name string, opt DiagOptions, gOpt operator.Options) error {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return err
+	}
+	if opt.Output == "" {
+		opt.Output = fmt.Sprintf("%s-diag-%s.tar.gz", name, time.Now().Format("20060102150405"))
+	}
+
+	metadata, err := m.meta(name)
+	if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) &&
+		!errors.Is(perrs.Cause(err), spec.ErrNoTiSparkMaster) {
+		return err
+	}
+	topo := metadata.GetTopology()
+	base := metadata.GetBaseMeta()
+
+	ctx, err := m.clusterSSHContext(name, topo, base.User, gOpt)
+	if err != nil {
+		return err
+	}
+
+	instances := filterInstances(topo, gOpt)
+	if len(instances) == 0 {
+		return perrs.New("no instance matches the specified roles and nodes")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "tiup-cluster-diag-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	c := &diagCollector{
+		dir:       tmpDir,
+		fileLimit: opt.FileSizeLimit,
+		sizeLimit: opt.SizeLimit,
+		meta: DiagBundleMeta{
+			ClusterName:    name,
+			ClusterVersion: base.Version,
+			CreatedAt:      time.Now(),
+		},
+	}
+	if opt.Since > 0 {
+		c.meta.Since = opt.Since.String()
+	}
+
+	// local metadata of the cluster
+	if data, err := os.ReadFile(m.specManager.Path(name, "meta.yaml")); err != nil {
+		c.fail("read meta.yaml: %s", err)
+	} else {
+		c.add("meta.yaml", "", data, true)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		m.collectDiagInstances(ctx, c, instances, opt, gOpt)
+	}()
+	go func() {
+		defer wg.Done()
+		m.collectDiagHosts(ctx, c, topo, base.User, instances)
+	}()
+	go func() {
+		defer wg.Done()
+		m.collectDiagPD(ctx, c, name, topo, gOpt)
+	}()
+	wg.Wait()
+
+	sort.Slice(c.meta.Files, func(i, j int) bool {
+		return c.meta.Files[i].Path < c.meta.Files[j].Path
+	})
+	sort.Strings(c.meta.Errors)
+	data, err := json.MarshalIndent(c.meta, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := utils.WriteFile(filepath.Join(tmpDir, "meta.json"), data, 0644); err != nil {
+		return err
+	}
+	if err := writeTarball(opt.Output, tmpDir); err != nil {
+		return err
+	}
+
+	for _, e := range c.meta.Errors {
+		m.logger.Warnf("%s", e)
+	}
+	m.logger.Infof("Diagnostic data of cluster %s is saved to %s", name, color.YellowString(opt.Output))
+	return nil
+}
+
+// collectDiagInstances collects rendered configs, run scripts and recent logs of instances
+func (m *Manager) collectDiagInstances(ctx context.Context, c *diagCollector, instances []spec.Instance, opt DiagOptions, gOpt operator.Options) {
+	timeout := time.Duration(gOpt.OptTimeout) * time.Second
+	forEachInstance(instances, gOpt.Concurrency, func(ins spec.Instance) {
+		e, found := ctxt.GetInner(ctx).GetExecutor(ins.GetManageHost())
+		if !found {
+			c.fail("%s: no executor found for host %s", ins.ID(), ins.GetManageHost())
+			return
+		}
+		dir := filepath.Join("instances", instanceBundleDir(ins))
+
+		fetch := func(files []string, sub string) {
+			for _, f := range files {
+				stdout, stderr, err := e.Execute(ctx, readTailCommand(f, opt.FileSizeLimit), false, timeout)
+				if err != nil {
+					c.fail("%s: read %s: %s, %s", ins.ID(), f, err, strings.TrimSpace(string(stderr)))
+					continue
+				}
+				c.add(filepath.Join(dir, sub, filepath.Base(f)), ins.GetManageHost()+":"+f, stdout, true)
+			}
+		}
+
+		cmd := fmt.Sprintf("find %s %s -maxdepth 1 -type f 2>/dev/null; true",
+			shellQuote(filepath.Join(ins.DeployDir(), "conf")),
+			shellQuote(filepath.Join(ins.DeployDir(), "scripts")),
+		)
+		stdout, _, err := e.Execute(ctx, cmd, false, timeout)
+		if err != nil {
+			c.fail("%s: list config files: %s", ins.ID(), err)
+		} else {
+			fetch(splitLines(stdout), "conf")
+		}
+
+		stdout, stderr, err := e.Execute(ctx, listLogCommand(ins.LogDir(), opt.Since), false, timeout)
+		if err != nil {
+			c.fail("%s: list log files: %s, %s", ins.ID(), err, strings.TrimSpace(string(stderr)))
+			return
+		}
+		fetch(splitLines(stdout), "log")
+		m.logger.Debugf("Collected diagnostic data of %s", ins.ID())
+	})
+}
+
+// collectDiagHosts runs the insight collector on hosts to get system info,
+// kernel configs and kernel logs
+func (m *Manager) collectDiagHosts(ctx context.Context, c *diagCollector, topo spec.Topology, user string, instances []spec.Instance) {
+	systemdMode := topo.BaseTopo().GlobalOptions.SystemdMode
+	sudo := systemdMode != spec.UserMode
+
+	var (
+		downloadTasks []*task.StepDisplay
+		collectTasks  []*task.StepDisplay
+		cleanTasks    []*task.StepDisplay
+	)
+	uniqueArch := set.NewStringSet()
+	uniqueHosts := set.NewStringSet()
+	var hosts []string
+	for _, ins := range instances {
+		archKey := fmt.Sprintf("%s-%s", ins.OS(), ins.Arch())
+		if !uniqueArch.Exist(archKey) {
+			uniqueArch.Insert(archKey)
+			downloadTasks = append(downloadTasks, task.NewBuilder(m.logger).
+				Download(spec.ComponentCheckCollector, ins.OS(), ins.Arch(), "").
+				BuildAsStep(fmt.Sprintf("  - Downloading insight for %s/%s", ins.OS(), ins.Arch())))
+		}
+
+		host := ins.GetManageHost()
+		if uniqueHosts.Exist(host) {
+			continue
+		}
+		uniqueHosts.Insert(host)
+		hosts = append(hosts, host)
+		collectTasks = append(collectTasks, task.NewBuilder(m.logger).
+			Mkdir(user, host, sudo, filepath.Join(diagToolsDir, "bin")).
+			CopyComponent(spec.ComponentCheckCollector, ins.OS(), ins.Arch(), "", "", host, diagToolsDir).
+			// reading /dev/kmsg requires root privilege
+			Shell(host, filepath.Join(diagToolsDir, "bin", "insight")+" -syscfg -dmesg", diagInsightID(host), sudo).
+			BuildAsStep("  - Collecting system info of "+host))
+		cleanTasks = append(cleanTasks, task.NewBuilder(m.logger).
+			Rmdir(host, diagToolsDir).
+			BuildAsStep("  - Cleanup collector on "+host))
+	}
+
+	t := task.NewBuilder(m.logger).
+		ParallelStep("+ Download collector", false, downloadTasks...).
+		ParallelStep("+ Collect system information", true, collectTasks...).
+		ParallelStep("+ Cleanup collector", true, cleanTasks...).
+		Build()
+	if err := t.Execute(ctx); err != nil {
+		c.fail("collect system info: %s", err)
+		return
+	}
+
+	for _, host := range hosts {
+		stdout, stderr, ok := ctxt.GetInner(ctx).GetOutputs(diagInsightID(host))
+		if !ok || len(stdout) == 0 {
+			c.fail("%s: no system info collected, %s", host, strings.TrimSpace(string(stderr)))
+			continue
+		}
+		// the output is kept as is, truncating it makes the JSON invalid
+		c.add(filepath.Join("hosts", host, "insight.json"), host, stdout, false)
+	}
+}
+
+// collectDiagPD saves stores, config and members from PD
+func (m *Manager) collectDiagPD(ctx context.Context, c *diagCollector, name string, topo spec.Topology, gOpt operator.Options) {
+	s, ok := topo.(*spec.Specification)
+	if !ok || len(s.PDServers) == 0 {
+		return
+	}
+	tlsCfg, err := topo.TLSConfig(m.specManager.Path(name, spec.TLSCertKeyDir))
+	if err != nil {
+		c.fail("pd: %s", err)
+		return
+	}
+	pdClient := api.NewPDClient(ctx, s.GetPDListWithManageHost(), time.Duration(gOpt.APITimeout)*time.Second, tlsCfg)
+
+	if stores, err := pdClient.GetStores(); err != nil {
+		c.fail("pd: get stores: %s", err)
+	} else {
+		c.addJSON("pd/stores.json", "pd", stores)
+	}
+	if cfg, err := pdClient.GetConfig(); err != nil {
+		c.fail("pd: get config: %s", err)
+	} else {
+		c.addJSON("pd/config.json", "pd", cfg)
+	}
+	if members, err := pdClient.GetMembers(); err != nil {
+		c.fail("pd: get members: %s", err)
+	} else {
+		c.addJSON("pd/members.json", "pd", members)
+	}
+}
+
+func diagInsightID(host string) string {
+	return "diag-insight-" + host
+}
+
+// readTailCommand returns the command to print the file, only the tail is
+// printed if the limit is set, one more byte is read to tell if the file
+// exceeds the limit
+func readTailCommand(file string, limit int64) string {
+	if limit <= 0 {
+		return "cat " + shellQuote(file)
+	}
+	return fmt.Sprintf("tail -c %d %s", limit+1, shellQuote(file))
+}
+
+func splitLines(data []byte) []string {
+	var lines []string
+	for line := range strings.SplitSeq(strings.TrimSpace(string(data)), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagCollector(t *testing.T) {
+	dir := t.TempDir()
+	c := &diagCollector{
+		dir:       dir,
+		fileLimit: 16,
+		sizeLimit: 40,
+	}
+
+	c.add("conf/tidb.toml", "h1:/conf/tidb.toml", []byte("password = \"secret\"\nlevel = \"info\"\n"), true)
+	data, err := os.ReadFile(filepath.Join(dir, "conf/tidb.toml"))
+	require.NoError(t, err)
+	// the content is truncated to the tail without the partial first line
+	require.Equal(t, "level = \"info\"\n", string(data))
+	require.True(t, c.meta.Files[0].Truncated)
+	require.True(t, c.meta.Files[0].Redacted)
+
+	// the key of the secret cut off is not leaked
+	c.add("conf/pd.toml", "h1:/conf/pd.toml", []byte("password = \"secret\"\n"), true)
+	data, err = os.ReadFile(filepath.Join(dir, "conf/pd.toml"))
+	require.NoError(t, err)
+	require.Empty(t, data)
+
+	c.add("a.log", "", []byte("0123456789"), false)
+	c.add("b.log", "", []byte("0123456789abcdef"), false)
+	require.Len(t, c.meta.Files, 3)
+	require.Len(t, c.meta.Errors, 1)
+	require.Contains(t, c.meta.Errors[0], "b.log")
+	require.NoFileExists(t, filepath.Join(dir, "b.log"))
+}
+
+func TestReadTailCommand(t *testing.T) {
+	require.Equal(t, "cat '/a b/c.log'", readTailCommand("/a b/c.log", 0))
+	require.Equal(t, "tail -c 101 '/c.log'", readTailCommand("/c.log", 100))
+}
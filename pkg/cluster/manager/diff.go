@@ -0,0 +1,274 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/pingcap/tiup/pkg/meta"
+	"github.com/pingcap/tiup/pkg/tui"
+	"gopkg.in/yaml.v3"
+)
+
+// TopologyDiff is an item differs between two clusters
+type TopologyDiff struct {
+	Item  string `json:"item"`
+	Left  string `json:"left"`
+	Right string `json:"right"`
+}
+
+// DiffCluster compares the versions, server configs, per-instance config
+// overrides, resource control and instance counts per role of two clusters.
+func (m *Manager) DiffCluster(left, right string) error {
+	leftTopo, leftVersion, err := m.diffMeta(left)
+	if err != nil {
+		return err
+	}
+	rightTopo, rightVersion, err := m.diffMeta(right)
+	if err != nil {
+		return err
+	}
+
+	diffs, err := diffClusters(leftVersion, leftTopo, rightVersion, rightTopo)
+	if err != nil {
+		return err
+	}
+
+	if m.logger.GetDisplayMode() == logprinter.DisplayModeJSON {
+		if diffs == nil {
+			diffs = []TopologyDiff{}
+		}
+		d, err := json.MarshalIndent(diffs, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(d))
+		return nil
+	}
+
+	if len(diffs) == 0 {
+		m.logger.Infof("No difference found between cluster `%s` and `%s`", left, right)
+		return nil
+	}
+	rows := [][]string{{"Item", left, right}}
+	for _, d := range diffs {
+		rows = append(rows, []string{color.CyanString(d.Item), d.Left, d.Right})
+	}
+	tui.PrintTable(rows, true)
+	return nil
+}
+
+// diffMeta loads the topology and version of the cluster to compare
+func (m *Manager) diffMeta(name string) (*spec.Specification, string, error) {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return nil, "", err
+	}
+	metadata, err := m.meta(name)
+	if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) &&
+		!errors.Is(perrs.Cause(err), spec.ErrNoTiSparkMaster) {
+		return nil, "", err
+	}
+	topo, ok := metadata.GetTopology().(*spec.Specification)
+	if !ok {
+		return nil, "", perrs.Errorf("diff is not supported for %s cluster", m.sysName)
+	}
+	return topo, metadata.GetBaseMeta().Version, nil
+}
+
+// diffClusters returns the items differ between two topologies, sorted by
+// the item names
+func diffClusters(leftVersion string, left *spec.Specification, rightVersion string, right *spec.Specification) ([]TopologyDiff, error) {
+	l, err := summarizeTopology(leftVersion, left)
+	if err != nil {
+		return nil, err
+	}
+	r, err := summarizeTopology(rightVersion, right)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]string, 0, len(l)+len(r))
+	for k := range l {
+		items = append(items, k)
+	}
+	for k := range r {
+		if _, ok := l[k]; !ok {
+			items = append(items, k)
+		}
+	}
+	sort.Strings(items)
+
+	var diffs []TopologyDiff
+	for _, item := range items {
+		lv, lok := l[item]
+		rv, rok := r[item]
+		if lok && rok && lv == rv {
+			continue
+		}
+		if !lok {
+			lv = "-"
+		}
+		if !rok {
+			rv = "-"
+		}
+		diffs = append(diffs, TopologyDiff{Item: item, Left: lv, Right: rv})
+	}
+	return diffs, nil
+}
+
+// summarizeTopology flattens the host independent parts of the topology into
+// items, so that topologies deployed on different hosts can be compared.
+// Per-instance config overrides are summarized by role, with the distinct
+// values and the number of instances having each of them.
+func summarizeTopology(version string, topo *spec.Specification) (map[string]string, error) {
+	items := map[string]string{"version": version}
+
+	for prefix, v := range map[string]any{
+		"component_versions":      topo.ComponentVersions,
+		"server_configs":          topo.ServerConfigs,
+		"global.resource_control": topo.GlobalOptions.ResourceControl,
+	} {
+		flat, err := flattenYAML(v)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range flat {
+			items[prefix+"."+k] = formatDiffValue(v)
+		}
+	}
+
+	err := forEachServerSlice(topo, func(role string, servers reflect.Value) error {
+		if servers.Len() == 0 {
+			return nil
+		}
+		items[role+".count"] = strconv.Itoa(servers.Len())
+
+		values := make(map[string]map[string]int)
+		for i := 0; i < servers.Len(); i++ {
+			data, err := yaml.Marshal(servers.Index(i).Interface())
+			if err != nil {
+				return perrs.Trace(err)
+			}
+			var ins map[string]any
+			if err := yaml.Unmarshal(data, &ins); err != nil {
+				return perrs.Trace(err)
+			}
+			for _, field := range []string{"config", "resource_control"} {
+				sub, ok := ins[field].(map[string]any)
+				if !ok {
+					continue
+				}
+				for k, v := range spec.FlattenMap(sub) {
+					item := role + "." + field + "." + k
+					if values[item] == nil {
+						values[item] = make(map[string]int)
+					}
+					values[item][formatDiffValue(v)]++
+				}
+			}
+		}
+		for item, counts := range values {
+			items[item] = summarizeValues(counts, servers.Len())
+		}
+		return nil
+	})
+	return items, err
+}
+
+// summarizeValues formats the distinct values of an item among total
+// instances, instances not setting the item are counted as "-"
+func summarizeValues(counts map[string]int, total int) string {
+	set := 0
+	for _, n := range counts {
+		set += n
+	}
+	if len(counts) == 1 && set == total {
+		for v := range counts {
+			return v
+		}
+	}
+	if set < total {
+		counts["-"] = total - set
+	}
+
+	values := make([]string, 0, len(counts))
+	for v := range counts {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	for i, v := range values {
+		values[i] = fmt.Sprintf("%s (%d)", v, counts[v])
+	}
+	return strings.Join(values, ", ")
+}
+
+// flattenYAML flattens the value by its yaml representation
+func flattenYAML(v any) (map[string]any, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, perrs.Trace(err)
+	}
+	var m map[string]any
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, perrs.Trace(err)
+	}
+	return spec.FlattenMap(m), nil
+}
+
+// formatDiffValue formats a value of config in a comparable way
+func formatDiffValue(v any) string {
+	switch v.(type) {
+	case []any, map[string]any:
+		data, err := json.Marshal(v)
+		if err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// forEachServerSlice calls fn with the yaml key and the value of each server
+// list of the topology
+func forEachServerSlice(topo *spec.Specification, fn func(key string, servers reflect.Value) error) error {
+	v := reflect.ValueOf(topo).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Slice || field.Type().Elem().Kind() != reflect.Pointer ||
+			field.Type().Elem().Elem().Kind() != reflect.Struct {
+			continue
+		}
+		if _, ok := field.Type().Elem().Elem().FieldByName("Host"); !ok {
+			continue
+		}
+		key := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		if err := fn(key, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDiffClusters(t *testing.T) {
+	left := &spec.Specification{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+server_configs:
+  tikv:
+    storage.reserve-space: 2GiB
+    raftstore:
+      apply-pool-size: 2
+pd_servers:
+  - host: 172.16.5.1
+tikv_servers:
+  - host: 172.16.5.1
+    config:
+      server.grpc-concurrency: 8
+  - host: 172.16.5.2
+    config:
+      server.grpc-concurrency: 8
+tidb_servers:
+  - host: 172.16.5.1
+`), left))
+
+	right := &spec.Specification{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+global:
+  resource_control:
+    memory_limit: 8G
+server_configs:
+  tikv:
+    storage.reserve-space: 2GiB
+    raftstore.apply-pool-size: 4
+pd_servers:
+  - host: 172.16.6.1
+tikv_servers:
+  - host: 172.16.6.1
+    config:
+      server.grpc-concurrency: 8
+  - host: 172.16.6.2
+    config:
+      server.grpc-concurrency: 16
+  - host: 172.16.6.3
+tidb_servers:
+  - host: 172.16.6.1
+`), right))
+
+	diffs, err := diffClusters("v8.5.0", left, "v8.5.0", right)
+	require.NoError(t, err)
+	assert.Equal(t, []TopologyDiff{
+		{Item: "global.resource_control.memory_limit", Left: "-", Right: "8G"},
+		{Item: "server_configs.tikv.raftstore.apply-pool-size", Left: "2", Right: "4"},
+		{Item: "tikv_servers.config.server.grpc-concurrency", Left: "8", Right: "- (1), 16 (1), 8 (1)"},
+		{Item: "tikv_servers.count", Left: "2", Right: "3"},
+	}, diffs)
+
+	diffs, err = diffClusters("v8.5.0", left, "v8.1.0", left)
+	require.NoError(t, err)
+	assert.Equal(t, []TopologyDiff{{Item: "version", Left: "v8.5.0", Right: "v8.1.0"}}, diffs)
+}
@@ -51,6 +51,11 @@ type DisplayOption struct {
 	ShowManageHost bool
 	ShowNuma       bool
 	ShowVersions   bool
+	ShowCapacity   bool
+
+	// CapacitySampleInterval is the interval to sample region size from PD
+	// for estimating the growth trend of stores
+	CapacitySampleInterval time.Duration
 }
 
 // InstInfo represents an instance info
@@ -111,6 +116,8 @@ type JSONOutput struct {
 	InstanceInfos   []InstInfo      `json:"instances,omitempty"`
 	LocationLabel   string          `json:"location_label,omitempty"`
 	LabelInfos      []api.LabelInfo `json:"labels,omitempty"`
+
+	Maintenance *spec.MaintenanceWindow `json:"maintenance,omitempty"`
 }
 
 // Display cluster meta and topology.
@@ -130,6 +137,11 @@ func (m *Manager) Display(dopt DisplayOption, opt operator.Options) error {
 	base := metadata.GetBaseMeta()
 	cyan := color.New(color.FgCyan, color.Bold)
 
+	var maintenance *spec.MaintenanceWindow
+	if clusterMeta, ok := metadata.(*spec.ClusterMeta); ok {
+		maintenance = clusterMeta.Maintenance
+	}
+
 	// check if managehost is set
 	if !dopt.ShowManageHost {
 		topo.IterInstance(func(inst spec.Instance) {
@@ -160,6 +172,7 @@ func (m *Manager) Display(dopt DisplayOption, opt operator.Options) error {
 				nil,
 			},
 			InstanceInfos: clusterInstInfos,
+			Maintenance:   maintenance,
 		}
 
 		if topo.BaseTopo().GlobalOptions.TLSEnabled {
@@ -173,6 +186,9 @@ func (m *Manager) Display(dopt DisplayOption, opt operator.Options) error {
 		fmt.Printf("Cluster version:    %s\n", cyan.Sprint(base.Version))
 		fmt.Printf("Deploy user:        %s\n", cyan.Sprint(topo.BaseTopo().GlobalOptions.User))
 		fmt.Printf("SSH type:           %s\n", cyan.Sprint(topo.BaseTopo().GlobalOptions.SSHType))
+		if maintenance != nil {
+			fmt.Printf("Maintenance:        %s\n", color.New(color.FgYellow, color.Bold).Sprint(formatMaintenance(name, maintenance)))
+		}
 
 		// display TLS info
 		if topo.BaseTopo().GlobalOptions.TLSEnabled {
@@ -445,6 +461,7 @@ func (m *Manager) DisplayTiKVLabels(dopt DisplayOption, opt operator.Options) er
 	var (
 		labelInfoArr  []api.LabelInfo
 		locationLabel []string
+		mismatches    []string
 	)
 
 	if _, ok := topo.(*spec.Specification); ok {
@@ -456,10 +473,14 @@ func (m *Manager) DisplayTiKVLabels(dopt DisplayOption, opt operator.Options) er
 			m.logger.Debugf("get location labels from pd failed: %v", err)
 		}
 
-		_, storeInfos, err := pdClient.GetTiKVLabels()
+		storeLabels, storeInfos, err := pdClient.GetTiKVLabels()
 		if err != nil {
 			m.logger.Debugf("get tikv state and labels from pd failed: %v", err)
 		}
+		expectedLabels, _, err := topo.(*spec.Specification).GetTiKVLabels()
+		if err != nil {
+			m.logger.Debugf("get tikv labels from topology failed: %v", err)
+		}
 
 		for storeIP := range tikvStoreIP {
 			row := []string{
@@ -477,6 +498,13 @@ func (m *Manager) DisplayTiKVLabels(dopt DisplayOption, opt operator.Options) er
 
 			for _, val := range storeInfos {
 				if store, ok := val[storeIP]; ok {
+					addr := utils.JoinHostPort(store.Machine, utils.MustAtoI(store.Port))
+					store.Mismatch = diffStoreLabels(locationLabel, expectedLabels[addr], storeLabels[addr])
+					labels := store.Labels
+					if len(store.Mismatch) > 0 {
+						labels = color.YellowString(labels)
+						mismatches = append(mismatches, fmt.Sprintf("%s (store %d):\n\t%s", addr, store.Store, strings.Join(store.Mismatch, "\n\t")))
+					}
 					row := []string{
 						"",
 						store.Port,
@@ -486,7 +514,7 @@ func (m *Manager) DisplayTiKVLabels(dopt DisplayOption, opt operator.Options) er
 						fmt.Sprintf("%v", store.Regions),
 						store.Capacity,
 						store.Available,
-						store.Labels,
+						labels,
 					}
 					clusterTable = append(clusterTable, row)
 
@@ -509,10 +537,37 @@ func (m *Manager) DisplayTiKVLabels(dopt DisplayOption, opt operator.Options) er
 	fmt.Printf("Location labels:    %s\n", cyan.Sprint(strings.Join(locationLabel, ",")))
 	tui.PrintTable(clusterTable, true)
 	fmt.Printf("Total nodes: %d\n", len(clusterTable)-1)
+	if len(mismatches) > 0 {
+		color.Yellow("\nWARN: labels of some stores disagree with the location labels of PD or the topology:\n%s", strings.Join(mismatches, "\n"))
+	}
 
 	return nil
 }
 
+// diffStoreLabels compares the labels a store reported to PD with the
+// location-labels of PD and the labels expected by the topology.
+func diffStoreLabels(locationLabels []string, expected, actual map[string]string) []string {
+	var result []string
+	lbs := set.NewStringSet(locationLabels...)
+	for _, name := range locationLabels {
+		if _, ok := actual[name]; !ok {
+			result = append(result, fmt.Sprintf("location label '%s' is missing", name))
+		}
+	}
+	for name := range actual {
+		if len(lbs) > 0 && !lbs.Exist(name) {
+			result = append(result, fmt.Sprintf("label '%s' is not specified in location-labels %v", name, locationLabels))
+		}
+	}
+	for name, value := range expected {
+		if v, ok := actual[name]; ok && v != value {
+			result = append(result, fmt.Sprintf("label '%s' is '%s' but '%s' in topology", name, v, value))
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
 // GetClusterTopology get the topology of the cluster.
 func (m *Manager) GetClusterTopology(dopt DisplayOption, opt operator.Options) ([]InstInfo, error) {
 	ctx := ctxt.New(
@@ -64,3 +64,21 @@ func TestGetGrafanaURLStr(t *testing.T) {
 	})
 	assert.Equal(t, exist, false)
 }
+
+func TestDiffStoreLabels(t *testing.T) {
+	assert.Empty(t, diffStoreLabels(
+		[]string{"zone", "host"},
+		map[string]string{"zone": "z1", "host": "h1"},
+		map[string]string{"zone": "z1", "host": "h1"},
+	))
+	assert.Equal(t, []string{
+		"label 'rack' is not specified in location-labels [zone host]",
+		"label 'zone' is 'z2' but 'z1' in topology",
+		"location label 'host' is missing",
+	}, diffStoreLabels(
+		[]string{"zone", "host"},
+		map[string]string{"zone": "z1", "host": "h1"},
+		map[string]string{"zone": "z2", "rack": "r1"},
+	))
+	assert.Empty(t, diffStoreLabels(nil, nil, map[string]string{"zone": "z1"}))
+}
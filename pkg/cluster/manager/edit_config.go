@@ -41,6 +41,12 @@ func (m *Manager) EditConfig(name string, opt EditConfigOptions, skipConfirm boo
 		return err
 	}
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) {
 		return err
@@ -63,6 +69,11 @@ func (m *Manager) EditConfig(name string, opt EditConfigOptions, skipConfirm boo
 	}
 
 	m.logger.Infof("Applying changes...")
+	if topo, ok := newTopo.(*spec.Specification); ok {
+		if err := topo.CopyTemplates(m.specManager.Path(name, spec.TemplatesDirName)); err != nil {
+			return err
+		}
+	}
 	metadata.SetTopology(newTopo)
 	err = m.specManager.SaveMeta(name, metadata)
 	if err != nil {
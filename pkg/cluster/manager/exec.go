@@ -15,6 +15,17 @@ package manager
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/joomcode/errorx"
@@ -23,15 +34,41 @@ import (
 	"github.com/pingcap/tiup/pkg/cluster/ctxt"
 	operator "github.com/pingcap/tiup/pkg/cluster/operation"
 	"github.com/pingcap/tiup/pkg/cluster/spec"
-	"github.com/pingcap/tiup/pkg/cluster/task"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
 	"github.com/pingcap/tiup/pkg/set"
 	"github.com/pingcap/tiup/pkg/utils"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/sync/errgroup"
 )
 
 // ExecOptions for exec shell commanm.
 type ExecOptions struct {
 	Command string
 	Sudo    bool
+	// Script is a local script uploaded to and run on the hosts instead of Command
+	Script string
+	// Timeout is the timeout of running the command on each host
+	Timeout time.Duration
+	// TTY opens an interactive session with a PTY on the only selected host
+	TTY bool
+}
+
+// ExecResult is the result of running a command on a host
+type ExecResult struct {
+	Host       string `json:"host"`
+	Command    string `json:"command"`
+	ExitCode   int    `json:"exit_code"`
+	Stdout     string `json:"stdout"`
+	Stderr     string `json:"stderr"`
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// execTarget is a command to be run on a host
+type execTarget struct {
+	host    string
+	sshPort int
+	cmd     string
 }
 
 // Exec shell command on host in the tidb cluster.
@@ -51,7 +88,6 @@ func (m *Manager) Exec(name string, opt ExecOptions, gOpt operator.Options) erro
 	filterRoles := set.NewStringSet(gOpt.Roles...)
 	filterNodes := set.NewStringSet(gOpt.Nodes...)
 
-	var shellTasks []task.Task
 	uniqueHosts := map[string]set.StringSet{} // host-sshPort -> {command}
 	topo.IterInstance(func(inst spec.Instance) {
 		key := utils.JoinHostPort(inst.GetManageHost(), inst.GetSSHPort())
@@ -64,71 +100,201 @@ func (m *Manager) Exec(name string, opt ExecOptions, gOpt operator.Options) erro
 				return
 			}
 
+			// the script is not rendered, it's run as it is
+			if opt.Script != "" {
+				uniqueHosts[key] = set.NewStringSet(opt.Script)
+				return
+			}
+
 			cmds, err := renderInstanceSpec(opt.Command, inst)
 			if err != nil {
 				m.logger.Debugf("error rendering command with spec: %s", err)
 				return // skip
 			}
-			cmdSet := set.NewStringSet(cmds...)
-			if _, ok := uniqueHosts[key]; ok {
-				uniqueHosts[key].Join(cmdSet)
-				return
-			}
-			uniqueHosts[key] = cmdSet
+			uniqueHosts[key] = set.NewStringSet(cmds...)
 		}
 	})
 
+	var targets []execTarget
 	for hostKey, i := range uniqueHosts {
-		host, _ := utils.ParseHostPort(hostKey)
+		host, port := utils.ParseHostPort(hostKey)
+		sshPort, _ := strconv.Atoi(port)
 		for _, cmd := range i.Slice() {
-			shellTasks = append(shellTasks,
-				task.NewBuilder(m.logger).
-					Shell(host, cmd, hostKey+cmd, opt.Sudo).
-					Build())
+			targets = append(targets, execTarget{host: host, sshPort: sshPort, cmd: cmd})
 		}
 	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].host != targets[j].host {
+			return targets[i].host < targets[j].host
+		}
+		return targets[i].cmd < targets[j].cmd
+	})
 
-	b, err := m.sshTaskBuilder(name, topo, base.User, gOpt)
+	if opt.TTY {
+		if len(targets) != 1 {
+			return perrs.Errorf("--tty requires exactly one host to be selected, but %d are selected, please choose one with -N", len(targets))
+		}
+		return m.execTTY(name, base.User, targets[0], opt)
+	}
+
+	ctx, err := m.clusterSSHContext(name, topo, base.User, gOpt)
 	if err != nil {
 		return err
 	}
 
-	t := b.
-		Parallel(false, shellTasks...).
-		Build()
+	var script []byte
+	if opt.Script != "" {
+		if script, err = os.ReadFile(opt.Script); err != nil {
+			return perrs.Annotatef(err, "read script %s", opt.Script)
+		}
+	}
 
-	execCtx := ctxt.New(
-		context.Background(),
-		gOpt.Concurrency,
-		m.logger,
-	)
-	if err := t.Execute(execCtx); err != nil {
-		if errorx.Cast(err) != nil {
-			// FIXME: Map possible task errors and give suggestions.
-			return err
+	results := make([]ExecResult, len(targets))
+	g := errgroup.Group{}
+	g.SetLimit(max(gOpt.Concurrency, 1))
+	for idx, target := range targets {
+		g.Go(func() error {
+			if script != nil {
+				results[idx] = m.execScript(ctx, target, script, opt)
+			} else {
+				results[idx] = m.execCommand(ctx, target, target.cmd, opt)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.ExitCode != 0 {
+			failed++
 		}
-		return perrs.Trace(err)
 	}
 
-	// print outputs
-	for hostKey, i := range uniqueHosts {
-		host, _ := utils.ParseHostPort(hostKey)
-		for _, cmd := range i.Slice() {
-			stdout, stderr, ok := ctxt.GetInner(execCtx).GetOutputs(hostKey + cmd)
-			if !ok {
-				continue
+	if m.logger.GetDisplayMode() == logprinter.DisplayModeJSON {
+		d, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(d))
+	} else {
+		// print outputs
+		for _, r := range results {
+			m.logger.Infof("Outputs of %s on %s (exit code %d, took %s):",
+				color.CyanString(r.Command),
+				color.CyanString(r.Host),
+				r.ExitCode,
+				time.Duration(r.DurationMS)*time.Millisecond)
+			if len(r.Stdout) > 0 {
+				m.logger.Infof("%s:\n%s", color.GreenString("stdout"), r.Stdout)
 			}
-			m.logger.Infof("Outputs of %s on %s:",
-				color.CyanString(cmd),
-				color.CyanString(host))
-			if len(stdout) > 0 {
-				m.logger.Infof("%s:\n%s", color.GreenString("stdout"), stdout)
+			if len(r.Stderr) > 0 {
+				m.logger.Infof("%s:\n%s", color.RedString("stderr"), r.Stderr)
 			}
-			if len(stderr) > 0 {
-				m.logger.Infof("%s:\n%s", color.RedString("stderr"), stderr)
+			if r.Error != "" {
+				m.logger.Infof("%s:\n%s", color.RedString("error"), r.Error)
 			}
 		}
 	}
 
+	if failed > 0 {
+		return perrs.Errorf("failed to run the command on %d of %d hosts", failed, len(results))
+	}
 	return nil
 }
+
+// execCommand runs cmd on the host of target and collects the result
+func (m *Manager) execCommand(ctx context.Context, target execTarget, cmd string, opt ExecOptions) ExecResult {
+	result := ExecResult{Host: target.host, Command: target.cmd}
+	e, found := ctxt.GetInner(ctx).GetExecutor(target.host)
+	if !found {
+		result.ExitCode = -1
+		result.Error = fmt.Sprintf("no executor found for host %s", target.host)
+		return result
+	}
+
+	var timeout []time.Duration
+	if opt.Timeout > 0 {
+		timeout = append(timeout, opt.Timeout)
+	}
+	start := time.Now()
+	stdout, stderr, err := e.Execute(ctx, cmd, opt.Sudo, timeout...)
+	result.DurationMS = time.Since(start).Milliseconds()
+	result.Stdout = string(stdout)
+	result.Stderr = string(stderr)
+	if err != nil {
+		result.ExitCode = exitCode(err)
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// execScript uploads the script to the host of target, runs it and removes
+// it at last
+func (m *Manager) execScript(ctx context.Context, target execTarget, script []byte, opt ExecOptions) ExecResult {
+	result := ExecResult{Host: target.host, Command: target.cmd, ExitCode: -1}
+	e, found := ctxt.GetInner(ctx).GetExecutor(target.host)
+	if !found {
+		result.Error = fmt.Sprintf("no executor found for host %s", target.host)
+		return result
+	}
+
+	sum := sha256.Sum256(script)
+	remote := fmt.Sprintf("/tmp/tiup-exec-%s-%s", hex.EncodeToString(sum[:8]), filepath.Base(opt.Script))
+	if err := e.Transfer(ctx, opt.Script, remote, false, 0, false); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer func() {
+		_, _, _ = e.Execute(ctx, fmt.Sprintf("rm -f %s", remote), opt.Sudo)
+	}()
+
+	return m.execCommand(ctx, target, fmt.Sprintf("bash %s", remote), opt)
+}
+
+// execTTY opens an interactive SSH session with a PTY to the host of target,
+// the command is run in the session if it's not empty
+func (m *Manager) execTTY(name, user string, target execTarget, opt ExecOptions) error {
+	args := []string{
+		"-i", m.specManager.Path(name, "ssh", "id_rsa"),
+		"-p", strconv.Itoa(target.sshPort),
+		"-o", "StrictHostKeyChecking=no",
+		"-t",
+		fmt.Sprintf("%s@%s", user, target.host),
+	}
+	if opt.Command != "" {
+		cmd := opt.Command
+		if opt.Sudo {
+			cmd = fmt.Sprintf("sudo -H %s", cmd)
+		}
+		args = append(args, cmd)
+	}
+
+	command := exec.Command("ssh", args...)
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+	return command.Run()
+}
+
+// exitCode returns the exit code of the remote command from the error of
+// executors, -1 is returned if the command is not run to the end, e.g. timed
+// out or failed to connect
+func exitCode(err error) int {
+	for err != nil {
+		var sshErr *ssh.ExitError
+		if errors.As(err, &sshErr) {
+			return sshErr.ExitStatus()
+		}
+		var execErr *exec.ExitError
+		if errors.As(err, &execErr) {
+			return execErr.ExitCode()
+		}
+		e := errorx.Cast(err)
+		if e == nil {
+			break
+		}
+		err = e.Cause()
+	}
+	return -1
+}
@@ -0,0 +1,34 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/cluster/executor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCode(t *testing.T) {
+	err := exec.Command("sh", "-c", "exit 3").Run()
+	assert.Equal(t, 3, exitCode(err))
+
+	wrapped := executor.ErrSSHExecuteFailed.Wrap(err, "Failed to execute command")
+	assert.Equal(t, 3, exitCode(wrapped))
+
+	assert.Equal(t, -1, exitCode(executor.ErrSSHExecuteTimedout.New("timed out")))
+	assert.Equal(t, -1, exitCode(errors.New("connection refused")))
+}
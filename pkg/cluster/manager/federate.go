@@ -0,0 +1,160 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/tui"
+)
+
+// MonitorFederate collects the metrics of the member clusters into the
+// Prometheus servers of the central cluster, the topologies of the clusters
+// are updated and the monitoring components of them are reloaded.
+func (m *Manager) MonitorFederate(central string, members []string, mode string, gOpt operator.Options, skipConfirm bool) error {
+	switch mode {
+	case spec.FederationModeRemoteWrite, spec.FederationModeFederate:
+	default:
+		return perrs.Errorf("invalid federation mode '%s', should be %s or %s", mode, spec.FederationModeRemoteWrite, spec.FederationModeFederate)
+	}
+
+	// the clusters are locked until their metadata are saved and the
+	// monitoring components are reloaded
+	for _, name := range append([]string{central}, members...) {
+		unlock, err := m.lockCluster(name)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	centralMeta, centralTopo, err := m.federationTopology(central)
+	if err != nil {
+		return err
+	}
+	memberMetas := make(map[string]*spec.ClusterMeta, len(members))
+	for _, member := range members {
+		if member == central {
+			return perrs.Errorf("cluster %s can not be both the central and a member", central)
+		}
+		if _, ok := memberMetas[member]; ok {
+			continue
+		}
+		metadata, topo, err := m.federationTopology(member)
+		if err != nil {
+			return err
+		}
+		memberMetas[member] = metadata
+
+		fm := spec.FederationMember{Cluster: member, Mode: mode}
+		if mode == spec.FederationModeFederate {
+			fm.Targets = spec.FederationTargets(topo)
+		}
+		for _, monitor := range centralTopo.Monitors {
+			monitor.SetFederationMember(fm)
+		}
+
+		for _, monitor := range topo.Monitors {
+			if monitor.ExternalLabels == nil {
+				monitor.ExternalLabels = make(map[string]string)
+			}
+			monitor.ExternalLabels[spec.FederationClusterLabel] = member
+			var addrs []string
+			if mode == spec.FederationModeRemoteWrite {
+				addrs = spec.FederationTargets(centralTopo)
+			}
+			monitor.SetFederationRemoteWrite(central, addrs)
+		}
+		if err := topo.Validate(); err != nil {
+			return perrs.Annotatef(err, "cluster %s", member)
+		}
+	}
+	// label the metrics of the central cluster itself as well, so that it
+	// can be selected in the dashboards together with the member clusters
+	for _, monitor := range centralTopo.Monitors {
+		if monitor.ExternalLabels == nil {
+			monitor.ExternalLabels = make(map[string]string)
+		}
+		monitor.ExternalLabels[spec.FederationClusterLabel] = central
+	}
+	if err := centralTopo.Validate(); err != nil {
+		return perrs.Annotatef(err, "cluster %s", central)
+	}
+
+	if !skipConfirm {
+		if err := tui.PromptForConfirmOrAbortError(
+			"%s", fmt.Sprintf("Will collect metrics of clusters %s into cluster %s with %s, the monitoring components of them will be reloaded.\nDo you want to continue? [y/N]:",
+				color.HiYellowString(strings.Join(members, ",")),
+				color.HiYellowString(central),
+				color.HiYellowString(mode),
+			),
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, member := range members {
+		if err := m.specManager.SaveMeta(member, memberMetas[member]); err != nil {
+			return perrs.Annotatef(err, "save meta of cluster %s", member)
+		}
+	}
+	if err := m.specManager.SaveMeta(central, centralMeta); err != nil {
+		return perrs.Annotatef(err, "save meta of cluster %s", central)
+	}
+
+	memberOpt := gOpt
+	memberOpt.Roles = []string{spec.ComponentPrometheus}
+	memberOpt.Nodes = nil
+	for _, member := range members {
+		if err := m.Reload(member, memberOpt, false, true); err != nil {
+			return perrs.Annotatef(err, "reload cluster %s", member)
+		}
+	}
+	centralOpt := gOpt
+	centralOpt.Roles = []string{spec.ComponentPrometheus, spec.ComponentGrafana}
+	centralOpt.Nodes = nil
+	if err := m.Reload(central, centralOpt, false, true); err != nil {
+		return perrs.Annotatef(err, "reload cluster %s", central)
+	}
+
+	m.logger.Infof("Metrics of clusters %s are collected into cluster %s", strings.Join(members, ","), central)
+	return nil
+}
+
+// federationTopology returns the meta and topology of a cluster joining the
+// federation, the cluster must have Prometheus servers
+func (m *Manager) federationTopology(name string) (*spec.ClusterMeta, *spec.Specification, error) {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return nil, nil, err
+	}
+	metadata, err := m.meta(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	clusterMeta, ok := metadata.(*spec.ClusterMeta)
+	if !ok {
+		return nil, nil, perrs.Errorf("cluster %s is not a TiDB cluster", name)
+	}
+	topo := clusterMeta.Topology
+	if len(topo.Monitors) == 0 {
+		return nil, nil, perrs.Errorf("no prometheus found in cluster %s", name)
+	}
+	return clusterMeta, topo, nil
+}
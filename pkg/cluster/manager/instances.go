@@ -0,0 +1,73 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/set"
+)
+
+// clusterSSHContext returns a context with SSH executors of all hosts in the cluster
+func (m *Manager) clusterSSHContext(name string, topo spec.Topology, user string, gOpt operator.Options) (context.Context, error) {
+	ctx := ctxt.New(
+		context.Background(),
+		gOpt.Concurrency,
+		m.logger,
+	)
+	if err := SetSSHKeySet(ctx, m.specManager.Path(name, "ssh", "id_rsa"), m.specManager.Path(name, "ssh", "id_rsa.pub")); err != nil {
+		return nil, err
+	}
+	if err := SetClusterSSH(ctx, topo, user, gOpt.SSHTimeout, gOpt.SSHType, topo.BaseTopo().GlobalOptions.SSHType); err != nil {
+		return nil, err
+	}
+	return ctx, nil
+}
+
+// filterInstances returns the instances matching the roles and nodes in options
+func filterInstances(topo spec.Topology, gOpt operator.Options) []spec.Instance {
+	filterRoles := set.NewStringSet(gOpt.Roles...)
+	filterNodes := set.NewStringSet(gOpt.Nodes...)
+	var instances []spec.Instance
+	topo.IterInstance(func(ins spec.Instance) {
+		if len(filterRoles) > 0 && !filterRoles.Exist(ins.Role()) {
+			return
+		}
+		if len(filterNodes) > 0 && !filterNodes.Exist(ins.ID()) {
+			return
+		}
+		instances = append(instances, ins)
+	})
+	return instances
+}
+
+// forEachInstance calls fn for every instance in parallel
+func forEachInstance(instances []spec.Instance, concurrency int, fn func(ins spec.Instance)) {
+	limit := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+	for _, ins := range instances {
+		wg.Add(1)
+		go func(ins spec.Instance) {
+			defer wg.Done()
+			limit <- struct{}{}
+			defer func() { <-limit }()
+			fn(ins)
+		}(ins)
+	}
+	wg.Wait()
+}
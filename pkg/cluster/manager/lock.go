@@ -0,0 +1,201 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/pingcap/tiup/pkg/tui"
+)
+
+// MetaLocker is the lock of the cluster metadata shared across machines,
+// e.g. the lock of a remote meta backend
+type MetaLocker interface {
+	// Holder returns who is holding the lock, nil if it is not locked
+	Holder(name string) (*spec.OperationLock, error)
+	// ForceUnlock releases the lock no matter who holds it
+	ForceUnlock(name string) error
+}
+
+// SetMetaLocker sets the lock of the cluster metadata shared across
+// machines, so that it can be inspected and released by lock commands
+func (m *Manager) SetMetaLocker(locker MetaLocker) {
+	m.metaLocker = locker
+}
+
+// lockCluster acquires the operation lock of the cluster, it must be called
+// by the methods changing the cluster or its metadata. The lock is
+// re-entrant in the process, so that the methods can call each other.
+func (m *Manager) lockCluster(name string) (func(), error) {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return nil, err
+	}
+
+	m.opLocksMu.Lock()
+	defer m.opLocksMu.Unlock()
+
+	if m.opLocks == nil {
+		m.opLocks = make(map[string]int)
+	}
+	if m.opLocks[name] == 0 {
+		if err := m.specManager.AcquireOperationLock(name, spec.NewOperationLock(tui.OsArgs())); err != nil {
+			return nil, err
+		}
+	}
+	m.opLocks[name]++
+
+	return func() {
+		m.opLocksMu.Lock()
+		defer m.opLocksMu.Unlock()
+
+		m.opLocks[name]--
+		if m.opLocks[name] > 0 {
+			return
+		}
+		delete(m.opLocks, name)
+		if err := m.specManager.ReleaseOperationLock(name); err != nil {
+			m.logger.Warnf("Failed to release the operation lock of cluster %s: %v", name, err)
+		}
+		// do not leave an empty directory if the cluster does not exist
+		_ = os.Remove(m.specManager.Path(name))
+	}, nil
+}
+
+// LockCluster acquires the operation lock of the cluster for the commands
+// running several steps as one operation, e.g. planning and applying the
+// changes, so that the cluster is not changed between the steps.
+func (m *Manager) LockCluster(name string) (func(), error) {
+	return m.lockCluster(name)
+}
+
+// LockState is the state of a lock of the cluster
+type LockState struct {
+	Lock   string              `json:"lock"`
+	Holder *spec.OperationLock `json:"holder,omitempty"`
+	Stale  bool                `json:"stale"`
+}
+
+// lockStates returns the states of the operation lock and the lock of meta
+// backend of the cluster
+func (m *Manager) lockStates(name string) ([]LockState, error) {
+	holder, err := m.specManager.OperationLock(name)
+	if err != nil {
+		return nil, err
+	}
+	states := []LockState{{Lock: "operation", Holder: holder}}
+	if m.metaLocker != nil {
+		holder, err := m.metaLocker.Holder(name)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, LockState{Lock: "meta backend", Holder: holder})
+	}
+	for i := range states {
+		if states[i].Holder != nil {
+			states[i].Stale = states[i].Holder.IsStale()
+		}
+	}
+	return states, nil
+}
+
+// LockStatus displays who is operating the cluster
+func (m *Manager) LockStatus(name string) error {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return err
+	}
+	states, err := m.lockStates(name)
+	if err != nil {
+		return err
+	}
+
+	if m.logger.GetDisplayMode() == logprinter.DisplayModeJSON {
+		d, err := json.MarshalIndent(states, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(d))
+		return nil
+	}
+
+	rows := [][]string{{"Lock", "Status", "User", "Host", "PID", "Command", "Since"}}
+	for _, s := range states {
+		if s.Holder == nil {
+			rows = append(rows, []string{s.Lock, color.GreenString("Free"), "-", "-", "-", "-", "-"})
+			continue
+		}
+		status := color.YellowString("Locked")
+		if s.Stale {
+			status = color.RedString("Stale")
+		}
+		rows = append(rows, []string{
+			s.Lock,
+			status,
+			s.Holder.User,
+			s.Holder.Host,
+			fmt.Sprint(s.Holder.PID),
+			s.Holder.Command,
+			s.Holder.Since.Format(time.RFC3339),
+		})
+	}
+	tui.PrintTable(rows, true)
+	return nil
+}
+
+// ReleaseLock releases the locks of the cluster left by the operations not
+// running anymore
+func (m *Manager) ReleaseLock(name string, skipConfirm bool) error {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return err
+	}
+	states, err := m.lockStates(name)
+	if err != nil {
+		return err
+	}
+
+	locked := false
+	for _, s := range states {
+		if s.Holder == nil {
+			continue
+		}
+		locked = true
+		if !skipConfirm && !s.Stale {
+			if err := tui.PromptForConfirmOrAbortError(
+				"The %s lock of cluster %s is held by %s.\nMake sure the operation is not running anymore, do you want to release it? [y/N]:",
+				s.Lock, color.HiYellowString(name), s.Holder,
+			); err != nil {
+				return err
+			}
+		}
+		if s.Lock == "operation" {
+			err = m.specManager.ReleaseOperationLock(name)
+		} else {
+			err = m.metaLocker.ForceUnlock(name)
+		}
+		if err != nil {
+			return err
+		}
+		m.logger.Infof("Released the %s lock of cluster %s held by %s", s.Lock, name, s.Holder)
+	}
+	if !locked {
+		m.logger.Infof("Cluster %s is not locked", name)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockCluster(t *testing.T) {
+	specManager := spec.NewSpec(t.TempDir(), func() spec.Metadata {
+		return &spec.ClusterMeta{}
+	})
+	m := NewManager("tidb", specManager, logprinter.NewLogger(""))
+
+	_, err := m.lockCluster("../test")
+	require.Error(t, err)
+
+	unlock, err := m.lockCluster("test")
+	require.NoError(t, err)
+	// nested calls share the lock
+	unlockNested, err := m.lockCluster("test")
+	require.NoError(t, err)
+	unlockNested()
+
+	holder, err := specManager.OperationLock("test")
+	require.NoError(t, err)
+	require.NotNil(t, holder)
+
+	unlock()
+	holder, err = specManager.OperationLock("test")
+	require.NoError(t, err)
+	require.Nil(t, holder)
+	// the directory created for the lock is removed
+	require.NoDirExists(t, specManager.Path("test"))
+}
@@ -0,0 +1,453 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	"github.com/pingcap/tiup/pkg/cluster/executor"
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// logFollowInterval is the interval to poll new log lines in follow mode
+const logFollowInterval = time.Second
+
+// logTimeRegexp matches the timestamp of the unified log format, e.g.:
+// [2024/01/02 15:04:05.000 +08:00] [INFO] [server.go:123] ["welcome"]
+var logTimeRegexp = regexp.MustCompile(`^\[(\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}\.\d{3} [+-]\d{2}:\d{2})\]`)
+
+const logTimeLayout = "2006/01/02 15:04:05.000 -07:00"
+
+// currentLogCond is the find condition of current log files, rotated files
+// have timestamps in their names (e.g. tikv.2024-01-02T15-04-05.000.log)
+const currentLogCond = `-regextype posix-extended -regex '.*/[A-Za-z_-]+\.log'`
+
+// LogsOptions represents the options of logs command
+type LogsOptions struct {
+	Since  time.Duration // only show logs newer than a relative duration
+	Grep   string        // only show lines matching the regular expression
+	Follow bool          // keep polling new lines of the log files
+	Bundle string        // pack raw log files into the tarball instead of printing
+}
+
+// LogBundleMeta is the metadata file of a log bundle
+type LogBundleMeta struct {
+	ClusterName    string              `json:"cluster_name"`
+	ClusterVersion string              `json:"cluster_version"`
+	CreatedAt      time.Time           `json:"created_at"`
+	Since          string              `json:"since,omitempty"`
+	Instances      []LogBundleInstance `json:"instances"`
+}
+
+// LogBundleInstance records the log files collected from an instance
+type LogBundleInstance struct {
+	ID     string   `json:"id"`
+	Role   string   `json:"role"`
+	Host   string   `json:"host"`
+	LogDir string   `json:"log_dir"`
+	Files  []string `json:"files"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// Logs searches log files of instances in the cluster.
+func (m *Manager) Logs(name string, opt LogsOptions, gOpt operator.Options) error {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return err
+	}
+
+	var pattern *regexp.Regexp
+	if opt.Grep != "" {
+		var err error
+		if pattern, err = regexp.Compile(opt.Grep); err != nil {
+			return perrs.Annotatef(err, "invalid pattern '%s'", opt.Grep)
+		}
+	}
+
+	metadata, err := m.meta(name)
+	if err != nil {
+		return err
+	}
+	topo := metadata.GetTopology()
+	base := metadata.GetBaseMeta()
+
+	ctx, err := m.clusterSSHContext(name, topo, base.User, gOpt)
+	if err != nil {
+		return err
+	}
+
+	instances := filterInstances(topo, gOpt)
+	if len(instances) == 0 {
+		return perrs.New("no instance matches the specified roles and nodes")
+	}
+
+	if opt.Bundle != "" {
+		return m.bundleLogs(ctx, name, base.Version, instances, opt, gOpt)
+	}
+
+	timeout := time.Duration(gOpt.OptTimeout) * time.Second
+	var since time.Time
+	if opt.Since > 0 {
+		since = time.Now().Add(-opt.Since)
+	}
+
+	var mu sync.Mutex
+	printLines := func(ins spec.Instance, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		prefix := color.CyanString("[%s %s]", ins.Role(), ins.ID())
+		mu.Lock()
+		defer mu.Unlock()
+		for _, line := range lines {
+			fmt.Printf("%s %s\n", prefix, line)
+		}
+	}
+
+	// literal patterns are pre-filtered by grep on the remote host to reduce
+	// the transferred data, the others are matched locally as RE2 syntax is
+	// not supported by grep
+	literal := ""
+	if pattern != nil {
+		if prefix, complete := pattern.LiteralPrefix(); complete {
+			literal = prefix
+		}
+	}
+
+	// offsets of log files in follow mode, instance ID -> file -> size
+	offsets := make(map[string]map[string]int64)
+	forEachInstance(instances, gOpt.Concurrency, func(ins spec.Instance) {
+		e, found := ctxt.GetInner(ctx).GetExecutor(ins.GetManageHost())
+		if !found {
+			return
+		}
+
+		if opt.Follow {
+			sizes, err := logFileSizes(ctx, e, ins.LogDir())
+			if err != nil {
+				m.logger.Warnf("Failed to list log files of %s: %s", ins.ID(), err)
+			}
+			mu.Lock()
+			offsets[ins.ID()] = sizes
+			mu.Unlock()
+			if opt.Since == 0 {
+				return
+			}
+		}
+
+		w := &logLineWriter{
+			filter: newLogLineFilter(since, pattern),
+			print:  func(lines []string) { printLines(ins, lines) },
+		}
+		stderr, err := executor.ExecuteStream(ctx, e, searchLogCommand(ins.LogDir(), opt.Since, literal), false, nil, w, timeout)
+		w.Flush()
+		if err != nil {
+			m.logger.Warnf("Failed to search logs of %s: %s, %s", ins.ID(), err, strings.TrimSpace(string(stderr)))
+		}
+	})
+
+	if !opt.Follow {
+		return nil
+	}
+
+	for {
+		time.Sleep(logFollowInterval)
+		forEachInstance(instances, gOpt.Concurrency, func(ins spec.Instance) {
+			e, found := ctxt.GetInner(ctx).GetExecutor(ins.GetManageHost())
+			if !found {
+				return
+			}
+			sizes, err := logFileSizes(ctx, e, ins.LogDir())
+			if err != nil {
+				m.logger.Debugf("Failed to list log files of %s: %s", ins.ID(), err)
+				return
+			}
+
+			mu.Lock()
+			prev := offsets[ins.ID()]
+			offsets[ins.ID()] = sizes
+			mu.Unlock()
+
+			files := make([]string, 0, len(sizes))
+			for f := range sizes {
+				files = append(files, f)
+			}
+			sort.Strings(files)
+			for _, f := range files {
+				offset := prev[f]
+				if sizes[f] < offset {
+					// the file is rotated or truncated, read it from the beginning
+					offset = 0
+				}
+				if sizes[f] == offset {
+					continue
+				}
+				cmd := fmt.Sprintf("tail -c +%d %s | head -c %d", offset+1, shellQuote(f), sizes[f]-offset)
+				stdout, _, err := e.Execute(ctx, cmd, false, timeout)
+				if err != nil {
+					m.logger.Debugf("Failed to read %s of %s: %s", f, ins.ID(), err)
+					continue
+				}
+				printLines(ins, filterLogLines(stdout, time.Time{}, pattern))
+			}
+		})
+	}
+}
+
+// bundleLogs downloads log files of instances and packs them into a tarball
+// with a metadata file describing where the files are from.
+func (m *Manager) bundleLogs(ctx context.Context, name, version string, instances []spec.Instance, opt LogsOptions, gOpt operator.Options) error {
+	tmpDir, err := os.MkdirTemp("", "tiup-cluster-logs-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	bundleMeta := LogBundleMeta{
+		ClusterName:    name,
+		ClusterVersion: version,
+		CreatedAt:      time.Now(),
+	}
+	if opt.Since > 0 {
+		bundleMeta.Since = opt.Since.String()
+	}
+
+	var mu sync.Mutex
+	timeout := time.Duration(gOpt.OptTimeout) * time.Second
+	forEachInstance(instances, gOpt.Concurrency, func(ins spec.Instance) {
+		record := LogBundleInstance{
+			ID:     ins.ID(),
+			Role:   ins.Role(),
+			Host:   ins.GetHost(),
+			LogDir: ins.LogDir(),
+		}
+		defer func() {
+			mu.Lock()
+			bundleMeta.Instances = append(bundleMeta.Instances, record)
+			mu.Unlock()
+		}()
+
+		e, found := ctxt.GetInner(ctx).GetExecutor(ins.GetManageHost())
+		if !found {
+			record.Error = "no executor found for host " + ins.GetManageHost()
+			return
+		}
+		stdout, stderr, err := e.Execute(ctx, listLogCommand(ins.LogDir(), opt.Since), false, timeout)
+		if err != nil {
+			record.Error = fmt.Sprintf("%s, %s", err, strings.TrimSpace(string(stderr)))
+			return
+		}
+
+		dir := instanceBundleDir(ins)
+		for f := range strings.SplitSeq(strings.TrimSpace(string(stdout)), "\n") {
+			if f == "" {
+				continue
+			}
+			dst := filepath.Join(tmpDir, dir, filepath.Base(f))
+			if err := e.Transfer(ctx, f, dst, true, 0, false); err != nil {
+				m.logger.Warnf("Failed to download %s from %s: %s", f, ins.ID(), err)
+				continue
+			}
+			record.Files = append(record.Files, filepath.Join(dir, filepath.Base(f)))
+		}
+		m.logger.Infof("Collected %d log files from %s", len(record.Files), ins.ID())
+	})
+
+	sort.Slice(bundleMeta.Instances, func(i, j int) bool {
+		return bundleMeta.Instances[i].ID < bundleMeta.Instances[j].ID
+	})
+	data, err := json.MarshalIndent(bundleMeta, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := utils.WriteFile(filepath.Join(tmpDir, "meta.json"), data, 0644); err != nil {
+		return err
+	}
+
+	if err := writeTarball(opt.Bundle, tmpDir); err != nil {
+		return err
+	}
+	m.logger.Infof("Logs of cluster %s are saved to %s", name, color.YellowString(opt.Bundle))
+	return nil
+}
+
+// writeTarball packs the directory into a gzip compressed tarball
+func writeTarball(file, dir string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return perrs.Annotatef(err, "create %s", file)
+	}
+	if err := utils.Tar(f, dir); err != nil {
+		f.Close()
+		return perrs.Annotatef(err, "write %s", file)
+	}
+	return f.Close()
+}
+
+func instanceBundleDir(ins spec.Instance) string {
+	r := strings.NewReplacer(":", "-", "[", "", "]", "")
+	return fmt.Sprintf("%s-%s", ins.Role(), r.Replace(ins.ID()))
+}
+
+// listLogCommand returns the command to list log files in the log dir, only
+// current log files are listed if since is not set, otherwise rotated files
+// modified in the duration are also listed. Compressed files are ignored.
+func listLogCommand(logDir string, since time.Duration) string {
+	cond := currentLogCond
+	if since > 0 {
+		minutes := int64(since.Minutes())
+		if time.Duration(minutes)*time.Minute < since {
+			minutes++
+		}
+		cond = fmt.Sprintf("-name '*.log*' ! -name '*.gz' -mmin -%d", minutes)
+	}
+	return fmt.Sprintf(
+		"find %s -maxdepth 1 -type f %s -printf '%%T@ %%p\\n' | sort -n | cut -d' ' -f2-",
+		shellQuote(logDir), cond,
+	)
+}
+
+// searchLogCommand returns the command to print lines of log files, the lines
+// are pre-filtered by grep if a literal string is given. The command fails if
+// the log dir or any of the files can not be read.
+func searchLogCommand(logDir string, since time.Duration, literal string) string {
+	// grep exits with 1 if no line is selected, which is not an error
+	reader := `cat -- "$f" || exit 2`
+	if literal != "" {
+		reader = fmt.Sprintf(`grep -hF -- %s "$f"; [ $? -le 1 ] || exit 2`, shellQuote(literal))
+	}
+	return fmt.Sprintf(
+		"test -d %[1]s || { echo 'log dir %[2]s does not exist' >&2; exit 1; }; %[3]s | while IFS= read -r f; do %[4]s; done",
+		shellQuote(logDir), strings.ReplaceAll(logDir, "'", ""), listLogCommand(logDir, since), reader,
+	)
+}
+
+// logFileSizes returns the size of current log files in the log dir
+func logFileSizes(ctx context.Context, e ctxt.Executor, logDir string) (map[string]int64, error) {
+	stdout, stderr, err := e.Execute(ctx, fmt.Sprintf("find %s -maxdepth 1 -type f %s -printf '%%s %%p\\n'", shellQuote(logDir), currentLogCond), false)
+	if err != nil {
+		return nil, perrs.Annotatef(err, "stderr: %s", string(stderr))
+	}
+	sizes := make(map[string]int64)
+	for line := range strings.SplitSeq(strings.TrimSpace(string(stdout)), "\n") {
+		size, file, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(size, 10, 64)
+		if err != nil {
+			continue
+		}
+		sizes[file] = n
+	}
+	return sizes, nil
+}
+
+// logLineFilter drops the log lines older than since or not matching the
+// pattern. Lines without timestamp (e.g. stack traces) follow the decision of
+// the last line with timestamp.
+type logLineFilter struct {
+	since   time.Time
+	pattern *regexp.Regexp
+	keep    bool
+}
+
+func newLogLineFilter(since time.Time, pattern *regexp.Regexp) *logLineFilter {
+	return &logLineFilter{since: since, pattern: pattern, keep: true}
+}
+
+// match returns whether the line should be kept, lines must be passed in order
+func (f *logLineFilter) match(line string) bool {
+	if !f.since.IsZero() {
+		if m := logTimeRegexp.FindStringSubmatch(line); m != nil {
+			if ts, err := time.Parse(logTimeLayout, m[1]); err == nil {
+				f.keep = !ts.Before(f.since)
+			}
+		}
+	}
+	return f.keep && (f.pattern == nil || f.pattern.MatchString(line))
+}
+
+// filterLogLines splits the output into lines and drops the ones not matching
+// the filter of since and pattern.
+func filterLogLines(data []byte, since time.Time, pattern *regexp.Regexp) []string {
+	var lines []string
+	filter := newLogLineFilter(since, pattern)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); filter.match(line) {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// logLineWriter prints the lines matching the filter as soon as they are
+// written, so that the output of a remote command is printed while streaming.
+type logLineWriter struct {
+	filter *logLineFilter
+	print  func(lines []string)
+	buf    []byte
+}
+
+// Write implements the io.Writer interface
+func (w *logLineWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	var lines []string
+	start := 0
+	for {
+		i := bytes.IndexByte(w.buf[start:], '\n')
+		if i < 0 {
+			break
+		}
+		if line := string(w.buf[start : start+i]); w.filter.match(line) {
+			lines = append(lines, line)
+		}
+		start += i + 1
+	}
+	w.buf = append(w.buf[:0], w.buf[start:]...)
+	w.print(lines)
+	return len(p), nil
+}
+
+// Flush prints the last line if it is not terminated by a newline
+func (w *logLineWriter) Flush() {
+	if len(w.buf) > 0 && w.filter.match(string(w.buf)) {
+		w.print([]string{string(w.buf)})
+	}
+	w.buf = nil
+}
+
+// shellQuote quotes the string to be used as a single argument in shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterLogLines(t *testing.T) {
+	data := []byte(`[2024/01/02 10:00:00.000 +08:00] [INFO] [server.go:1] ["old"]
+[2024/01/02 11:00:00.000 +08:00] [WARN] [server.go:2] ["slow query"]
+stack line of slow query
+[2024/01/02 11:30:00.000 +08:00] [ERROR] [server.go:3] ["panic"]
+`)
+	since, err := time.Parse(logTimeLayout, "2024/01/02 10:30:00.000 +08:00")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`[2024/01/02 11:00:00.000 +08:00] [WARN] [server.go:2] ["slow query"]`,
+		"stack line of slow query",
+		`[2024/01/02 11:30:00.000 +08:00] [ERROR] [server.go:3] ["panic"]`,
+	}, filterLogLines(data, since, nil))
+
+	assert.Equal(t, []string{
+		`[2024/01/02 11:00:00.000 +08:00] [WARN] [server.go:2] ["slow query"]`,
+		`[2024/01/02 11:30:00.000 +08:00] [ERROR] [server.go:3] ["panic"]`,
+	}, filterLogLines(data, time.Time{}, regexp.MustCompile(`panic|slow query\"`)))
+
+	assert.Len(t, filterLogLines(data, time.Time{}, nil), 4)
+}
+
+func TestLogLineWriter(t *testing.T) {
+	var printed []string
+	w := &logLineWriter{
+		filter: newLogLineFilter(time.Time{}, regexp.MustCompile(`^a`)),
+		print:  func(lines []string) { printed = append(printed, lines...) },
+	}
+	_, err := w.Write([]byte("a1\nb1\na"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1"}, printed)
+	_, err = w.Write([]byte("2\na3"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a1", "a2"}, printed)
+	w.Flush()
+	assert.Equal(t, []string{"a1", "a2", "a3"}, printed)
+}
+
+func TestSearchLogCommand(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+
+	// the log dir with spaces must be handled
+	dir := filepath.Join(t.TempDir(), "log dir")
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tikv.log"), []byte("a panic\nb ok\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tikv.2024-01-01.log"), []byte("c slow\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tikv.2023-01-01.log.gz"), []byte("d panic\n"), 0644))
+
+	run := func(cmd string) string {
+		out, err := exec.Command("bash", "-c", cmd).Output()
+		require.NoError(t, err)
+		return string(out)
+	}
+
+	assert.Equal(t, "a panic\nb ok\n", run(searchLogCommand(dir, 0, "")))
+	assert.Equal(t, "a panic\n", run(searchLogCommand(dir, 0, "panic")))
+	assert.Equal(t, "", run(searchLogCommand(dir, 0, "not found")))
+	assert.ElementsMatch(t, []string{"a panic", "b ok", "c slow"}, filterLogLines([]byte(run(searchLogCommand(dir, time.Hour, ""))), time.Time{}, nil))
+
+	// failures are not hidden
+	_, err := exec.Command("bash", "-c", searchLogCommand(filepath.Join(dir, "not-exist"), 0, "")).Output()
+	require.Error(t, err)
+	require.NoError(t, os.Chmod(filepath.Join(dir, "tikv.log"), 0))
+	if _, err := os.ReadFile(filepath.Join(dir, "tikv.log")); err != nil {
+		_, err = exec.Command("bash", "-c", searchLogCommand(dir, 0, "panic")).Output()
+		require.Error(t, err)
+	}
+	assert.Equal(t, `'it'\''s'`, shellQuote("it's"))
+}
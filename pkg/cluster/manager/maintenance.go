@@ -0,0 +1,316 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/api"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/set"
+)
+
+// balanceSchedulers are the PD schedulers paused in maintenance mode
+var balanceSchedulers = []string{
+	"balance-leader-scheduler",
+	"balance-region-scheduler",
+	"balance-hot-region-scheduler",
+}
+
+// MaintenanceOptions contains the options of entering maintenance mode
+type MaintenanceOptions struct {
+	// Duration is how long the maintenance lasts, the silences and paused
+	// schedulers expire after it even if the mode is not turned off
+	Duration time.Duration
+	// StoreLimit is the store limit of all stores during the maintenance,
+	// it's not changed if it's 0
+	StoreLimit int
+	Comment    string
+}
+
+// MaintenanceOn turns on the maintenance mode of the cluster, the alerts of
+// the nodes in options (or the whole cluster) are silenced, the balance
+// schedulers of PD are paused, and the window is recorded in the meta.
+func (m *Manager) MaintenanceOn(name string, opt MaintenanceOptions, gOpt operator.Options) error {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return err
+	}
+	if opt.Duration <= 0 {
+		return perrs.New("the duration of maintenance must be positive")
+	}
+
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	metadata, err := m.meta(name)
+	if err != nil {
+		return err
+	}
+	clusterMeta, ok := metadata.(*spec.ClusterMeta)
+	if !ok {
+		return perrs.Errorf("maintenance mode is not supported for %s cluster", m.sysName)
+	}
+	if w := clusterMeta.Maintenance; w != nil {
+		return perrs.Errorf("cluster `%s` is already in maintenance mode since %s, please turn it off first",
+			name, w.Start.Local().Format(time.DateTime))
+	}
+	topo := clusterMeta.Topology
+
+	var hosts []string
+	if len(gOpt.Nodes) > 0 {
+		instances := filterInstances(topo, gOpt)
+		if len(instances) == 0 {
+			return perrs.Errorf("no instance matches the nodes %v", gOpt.Nodes)
+		}
+		uniqueHosts := set.NewStringSet()
+		for _, ins := range instances {
+			uniqueHosts.Insert(ins.GetHost())
+		}
+		hosts = uniqueHosts.Slice()
+		sort.Strings(hosts)
+	}
+
+	comment := opt.Comment
+	if comment == "" {
+		comment = fmt.Sprintf("maintenance of cluster %s by tiup-cluster", name)
+	}
+	now := time.Now()
+	window := &spec.MaintenanceWindow{
+		Start:   now,
+		End:     now.Add(opt.Duration),
+		Nodes:   gOpt.Nodes,
+		Comment: comment,
+	}
+
+	// the window is saved even if it fails halfway, so that the changes
+	// made can be reverted by turning it off
+	clusterMeta.Maintenance = window
+	err = m.enterMaintenance(name, topo, window, hosts, opt, gOpt)
+	if serr := m.specManager.SaveMeta(name, clusterMeta); serr != nil && err == nil {
+		err = serr
+	}
+	if err != nil {
+		return err
+	}
+
+	m.logger.Infof("Cluster `%s` is in maintenance mode until %s", name, window.End.Local().Format(time.DateTime))
+	return nil
+}
+
+func (m *Manager) enterMaintenance(
+	name string,
+	topo *spec.Specification,
+	window *spec.MaintenanceWindow,
+	hosts []string,
+	opt MaintenanceOptions,
+	gOpt operator.Options,
+) error {
+	if len(topo.Alertmanagers) > 0 {
+		id, err := m.AlertSilence(name, AlertSilenceOptions{
+			Matchers: maintenanceSilenceMatchers(hosts),
+			Duration: opt.Duration,
+			Comment:  window.Comment,
+		}, gOpt)
+		if err != nil {
+			return err
+		}
+		window.Silences = append(window.Silences, id)
+		m.logger.Infof("Silenced alerts in Alertmanager, silence ID: %s", id)
+	} else {
+		m.logger.Warnf("No alertmanager found in cluster `%s`, alerts are not silenced", name)
+	}
+
+	if len(topo.PDServers) == 0 {
+		return nil
+	}
+	pdClient, err := m.maintenancePDClient(name, topo, gOpt)
+	if err != nil {
+		return err
+	}
+	for _, scheduler := range balanceSchedulers {
+		if err := pdClient.PauseScheduler(scheduler, opt.Duration); err != nil {
+			// the scheduler may be removed by user
+			m.logger.Warnf("Failed to pause scheduler %s: %v", scheduler, err)
+			continue
+		}
+		window.PausedSchedulers = append(window.PausedSchedulers, scheduler)
+	}
+	m.logger.Infof("Paused PD schedulers: %s", strings.Join(window.PausedSchedulers, ", "))
+
+	if opt.StoreLimit > 0 {
+		limits, err := pdClient.GetStoreLimits()
+		if err != nil {
+			return perrs.Annotate(err, "get store limits")
+		}
+		window.OriginStoreLimits = make(map[string]spec.MaintenanceStoreLimit, len(limits))
+		for id, limit := range limits {
+			window.OriginStoreLimits[id] = spec.MaintenanceStoreLimit{
+				AddPeer:    limit.AddPeer,
+				RemovePeer: limit.RemovePeer,
+			}
+		}
+		window.StoreLimit = opt.StoreLimit
+		if err := pdClient.SetAllStoreLimits(opt.StoreLimit); err != nil {
+			return perrs.Annotate(err, "set store limits")
+		}
+		m.logger.Infof("Set store limit of all stores to %d", opt.StoreLimit)
+	}
+	return nil
+}
+
+// MaintenanceOff turns off the maintenance mode of the cluster and reverts
+// the changes made when turning it on.
+func (m *Manager) MaintenanceOff(name string, gOpt operator.Options) error {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return err
+	}
+
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	metadata, err := m.meta(name)
+	if err != nil {
+		return err
+	}
+	clusterMeta, ok := metadata.(*spec.ClusterMeta)
+	if !ok {
+		return perrs.Errorf("maintenance mode is not supported for %s cluster", m.sysName)
+	}
+	window := clusterMeta.Maintenance
+	if window == nil {
+		m.logger.Infof("Cluster `%s` is not in maintenance mode", name)
+		return nil
+	}
+	topo := clusterMeta.Topology
+
+	// the silences and the paused schedulers expire by themselves, so
+	// failing to revert them is not fatal
+	if len(window.Silences) > 0 {
+		client, err := m.alertmanagerClient(name, topo, gOpt)
+		if err != nil {
+			m.logger.Warnf("Failed to expire silences %v: %v", window.Silences, err)
+		} else {
+			for _, id := range window.Silences {
+				if err := client.ExpireSilence(id); err != nil {
+					m.logger.Warnf("Failed to expire silence %s: %v", id, err)
+				}
+			}
+		}
+	}
+
+	if len(window.PausedSchedulers) > 0 || window.StoreLimit > 0 {
+		pdClient, err := m.maintenancePDClient(name, topo, gOpt)
+		if err != nil {
+			return err
+		}
+		for _, scheduler := range window.PausedSchedulers {
+			if err := pdClient.PauseScheduler(scheduler, 0); err != nil {
+				m.logger.Warnf("Failed to resume scheduler %s: %v", scheduler, err)
+			}
+		}
+		if window.StoreLimit > 0 && len(window.OriginStoreLimits) > 0 {
+			if err := m.restoreStoreLimits(pdClient, window.OriginStoreLimits); err != nil {
+				return perrs.Annotate(err, "restore store limits")
+			}
+			m.logger.Infof("Restored store limits of %d stores", len(window.OriginStoreLimits))
+		}
+	}
+
+	clusterMeta.Maintenance = nil
+	if err := m.specManager.SaveMeta(name, clusterMeta); err != nil {
+		return err
+	}
+	m.logger.Infof("Cluster `%s` is out of maintenance mode", name)
+	return nil
+}
+
+// restoreStoreLimits sets the limits of the stores back to the origin ones,
+// the stores removed during the maintenance are skipped
+func (m *Manager) restoreStoreLimits(pdClient *api.PDClient, origin map[string]spec.MaintenanceStoreLimit) error {
+	current, err := pdClient.GetStoreLimits()
+	if err != nil {
+		return err
+	}
+	ids := make([]string, 0, len(origin))
+	for id := range origin {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if _, ok := current[id]; !ok {
+			m.logger.Warnf("Store %s is removed, its store limit is not restored", id)
+			continue
+		}
+		limit := origin[id]
+		if err := pdClient.SetStoreLimit(id, "add-peer", limit.AddPeer); err != nil {
+			return err
+		}
+		if err := pdClient.SetStoreLimit(id, "remove-peer", limit.RemovePeer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Manager) maintenancePDClient(name string, topo *spec.Specification, gOpt operator.Options) (*api.PDClient, error) {
+	tlsCfg, err := topo.TLSConfig(m.specManager.Path(name, spec.TLSCertKeyDir))
+	if err != nil {
+		return nil, err
+	}
+	ctx := ctxt.New(context.Background(), gOpt.Concurrency, m.logger)
+	timeout := time.Duration(gOpt.APITimeout) * time.Second
+	return api.NewPDClient(ctx, topo.GetPDListWithManageHost(), timeout, tlsCfg), nil
+}
+
+// maintenanceSilenceMatchers returns the matchers of the alerts of instances
+// on the hosts, the whole cluster is matched if hosts is empty
+func maintenanceSilenceMatchers(hosts []string) []string {
+	if len(hosts) == 0 {
+		return nil
+	}
+	quoted := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		quoted = append(quoted, regexp.QuoteMeta(host))
+	}
+	return []string{fmt.Sprintf("instance=~(%s)(:[0-9]+)?", strings.Join(quoted, "|"))}
+}
+
+// formatMaintenance describes the maintenance window
+func formatMaintenance(name string, w *spec.MaintenanceWindow) string {
+	scope := "all nodes"
+	if len(w.Nodes) > 0 {
+		scope = "nodes " + strings.Join(w.Nodes, ",")
+	}
+	if time.Now().After(w.End) {
+		return fmt.Sprintf("expired at %s, run `tiup cluster maintenance %s off` to clean up",
+			w.End.Local().Format(time.DateTime), name)
+	}
+	return fmt.Sprintf("on for %s since %s until %s",
+		scope, w.Start.Local().Format(time.DateTime), w.End.Local().Format(time.DateTime))
+}
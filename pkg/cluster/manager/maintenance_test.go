@@ -0,0 +1,97 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiup/pkg/cluster/api"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaintenanceSilenceMatchers(t *testing.T) {
+	assert.Nil(t, maintenanceSilenceMatchers(nil))
+
+	exprs := maintenanceSilenceMatchers([]string{"10.0.1.1", "10.0.1.2"})
+	assert.Equal(t, []string{`instance=~(10\.0\.1\.1|10\.0\.1\.2)(:[0-9]+)?`}, exprs)
+
+	matchers, err := parseSilenceMatchers(exprs)
+	require.NoError(t, err)
+	require.Len(t, matchers, 1)
+	assert.Equal(t, "instance", matchers[0].Name)
+	assert.Equal(t, `(10\.0\.1\.1|10\.0\.1\.2)(:[0-9]+)?`, matchers[0].Value)
+	assert.True(t, matchers[0].IsRegex)
+	assert.True(t, matchers[0].IsEqual)
+}
+
+func TestFormatMaintenance(t *testing.T) {
+	now := time.Now()
+	w := &spec.MaintenanceWindow{Start: now, End: now.Add(time.Hour)}
+	assert.True(t, strings.HasPrefix(formatMaintenance("test", w), "on for all nodes since"))
+
+	w.Nodes = []string{"10.0.1.1:20160"}
+	assert.True(t, strings.HasPrefix(formatMaintenance("test", w), "on for nodes 10.0.1.1:20160 since"))
+
+	w.End = now.Add(-time.Minute)
+	assert.Contains(t, formatMaintenance("test", w), "tiup cluster maintenance test off")
+}
+
+func TestRestoreStoreLimits(t *testing.T) {
+	var mu sync.Mutex
+	var posts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/pd/api/v1/stores/limit":
+			fmt.Fprint(w, `{"1": {"add-peer": 100, "remove-peer": 100}, "4": {"add-peer": 100, "remove-peer": 100}}`)
+		case r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, "/pd/api/v1/store/"):
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			posts = append(posts, r.URL.Path+" "+string(body))
+			mu.Unlock()
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	m := NewManager("tidb", nil, logprinter.NewLogger(""))
+	ctx := ctxt.New(context.Background(), 1, m.logger)
+	pdClient := api.NewPDClient(ctx, []string{strings.TrimPrefix(server.URL, "http://")}, time.Second, nil)
+
+	err := m.restoreStoreLimits(pdClient, map[string]spec.MaintenanceStoreLimit{
+		"1": {AddPeer: 15, RemovePeer: 20},
+		// removed during the maintenance
+		"2": {AddPeer: 30, RemovePeer: 30},
+		"4": {AddPeer: 8.5, RemovePeer: 10},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`/pd/api/v1/store/1/limit {"rate":15,"type":"add-peer"}`,
+		`/pd/api/v1/store/1/limit {"rate":20,"type":"remove-peer"}`,
+		`/pd/api/v1/store/4/limit {"rate":8.5,"type":"add-peer"}`,
+		`/pd/api/v1/store/4/limit {"rate":10,"type":"remove-peer"}`,
+	}, posts)
+}
@@ -17,6 +17,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/fatih/color"
 	"github.com/joomcode/errorx"
@@ -46,6 +47,11 @@ type Manager struct {
 	sysName     string
 	specManager *spec.SpecManager
 	logger      *logprinter.Logger
+	metaLocker  MetaLocker
+
+	// the operation locks held by this process and their reference counts
+	opLocksMu sync.Mutex
+	opLocks   map[string]int
 }
 
 // NewManager create a Manager.
@@ -49,6 +49,12 @@ func (m *Manager) Patch(name string, packagePath string, opt operator.Options, o
 		}
 	}
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil {
 		return err
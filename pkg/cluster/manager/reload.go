@@ -44,6 +44,12 @@ func (m *Manager) Reload(name string, gOpt operator.Options, skipRestart, skipCo
 	sshTimeout := gOpt.SSHTimeout
 	exeTimeout := gOpt.OptTimeout
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil {
 		return err
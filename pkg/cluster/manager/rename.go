@@ -56,6 +56,12 @@ func (m *Manager) Rename(name string, opt operator.Options, newName string, skip
 		}
 	}
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil { // refuse renaming if current cluster topology is not valid
 		return err
@@ -64,6 +70,8 @@ func (m *Manager) Rename(name string, opt operator.Options, newName string, skip
 	if err := os.Rename(m.specManager.Path(name), m.specManager.Path(newName)); err != nil {
 		return err
 	}
+	// the operation lock is moved together with the metadata
+	defer m.specManager.ReleaseOperationLock(newName)
 
 	m.logger.Infof("Rename cluster `%s` -> `%s` successfully", name, newName)
 
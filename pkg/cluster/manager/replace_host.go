@@ -0,0 +1,394 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/cluster/task"
+	"github.com/pingcap/tiup/pkg/set"
+	"github.com/pingcap/tiup/pkg/tui"
+	"github.com/pingcap/tiup/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	replaceHostStateFile = "replace-host.json"
+	replaceHostTopoFile  = "replace-host.yaml"
+)
+
+// the steps of replacing a host, in the order of execution
+const (
+	replaceStepScaleOut = "scale-out"
+	replaceStepScaleIn  = "scale-in"
+	replaceStepWait     = "wait"
+	replaceStepMonitor  = "monitor"
+)
+
+// ReplaceHostOptions contains the options for replacing a host
+type ReplaceHostOptions struct {
+	// Deploy is used to scale out the instances on the new host
+	Deploy DeployOptions
+	// Interval is the interval to poll the progress of offlining
+	Interval time.Duration
+	// Force scales in the old instances without waiting for them to be
+	// offline, it is used when the old host is permanently down
+	Force bool
+}
+
+// replaceHostState records the progress of replacing a host, so that an
+// interrupted replacement can be resumed
+type replaceHostState struct {
+	OldHost  string   `json:"old_host"`
+	NewHost  string   `json:"new_host"`
+	OldNodes []string `json:"old_nodes"`
+	NewNodes []string `json:"new_nodes"`
+	Step     string   `json:"step"`
+}
+
+func loadReplaceHostState(path string) (*replaceHostState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, perrs.Trace(err)
+	}
+	state := &replaceHostState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, perrs.Annotatef(err, "invalid replace-host state %s", path)
+	}
+	return state, nil
+}
+
+func (s *replaceHostState) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return perrs.Trace(err)
+	}
+	return utils.WriteFile(path, data, 0644)
+}
+
+// ReplaceHost replaces all the instances on oldHost with the same instances
+// on newHost, it scales out the cloned instances, scales in the old ones,
+// waits for the data to be migrated and then refreshes the monitoring.
+// The progress is saved in the cluster directory, running it again with the
+// same hosts resumes an interrupted replacement.
+func (m *Manager) ReplaceHost(
+	name, oldHost, newHost string,
+	opt ReplaceHostOptions,
+	afterDeploy func(b *task.Builder, newPart spec.Topology, gOpt operator.Options),
+	final func(b *task.Builder, name string, meta spec.Metadata, gOpt operator.Options),
+	scaleIn func(name string, gOpt operator.Options) func(b *task.Builder, metadata spec.Metadata, tlsCfg *tls.Config),
+	skipConfirm bool,
+	gOpt operator.Options,
+) error {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return err
+	}
+	if oldHost == newHost {
+		return perrs.Errorf("the new host is the same as the old host %s", oldHost)
+	}
+
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	statePath := m.specManager.Path(name, replaceHostStateFile)
+	topoPath := m.specManager.Path(name, replaceHostTopoFile)
+	state, err := loadReplaceHostState(statePath)
+	if err != nil {
+		return err
+	}
+
+	if state != nil {
+		if state.OldHost != oldHost || state.NewHost != newHost {
+			return perrs.Errorf("replacing %s with %s of cluster `%s` is in progress, please finish it first or remove %s to abandon it",
+				state.OldHost, state.NewHost, name, statePath)
+		}
+		m.logger.Infof("Resume replacing %s with %s from step %s", oldHost, newHost, state.Step)
+	} else {
+		metadata, err := m.meta(name)
+		if err != nil && !errors.Is(perrs.Cause(err), spec.ErrNoTiSparkMaster) {
+			return err
+		}
+		topo, ok := metadata.GetTopology().(*spec.Specification)
+		if !ok {
+			return perrs.Errorf("replace-host is not supported for %s cluster", m.sysName)
+		}
+
+		part, oldNodes, err := cloneHostTopology(topo, oldHost, newHost)
+		if err != nil {
+			return err
+		}
+		if len(oldNodes) == 0 {
+			return perrs.Errorf("no instance of cluster `%s` is found on host %s", name, oldHost)
+		}
+		var newNodes []string
+		part.IterInstance(func(ins spec.Instance) {
+			newNodes = append(newNodes, ins.ID())
+		})
+
+		if !skipConfirm {
+			if opt.Force {
+				m.logger.Warnf("%s", color.HiRedString(tui.ASCIIArtWarning))
+				if err := tui.PromptForAnswerOrAbortError(
+					"Yes, I know my data might be lost.",
+					"%s", color.HiRedString("Forcing scale in is unsafe and may result in data loss for stateful components.\n"+
+						"The process is irreversible and could NOT be cancelled.\n")+
+						"Only use `--force` when the old host is already permanently offline.\n"+
+						"Are you sure to continue?",
+				); err != nil {
+					return err
+				}
+			}
+			if err := tui.PromptForConfirmOrAbortError(
+				"%s", fmt.Sprintf("%s\n%s\nDo you confirm this action? [y/N]:",
+					color.HiYellowString("Will replace these instances on %s: %v", oldHost, oldNodes),
+					color.HiYellowString("with these instances on %s: %v", newHost, newNodes)),
+			); err != nil {
+				return err
+			}
+		}
+
+		data, err := marshalServers(part)
+		if err != nil {
+			return err
+		}
+		if err := utils.WriteFile(topoPath, data, 0644); err != nil {
+			return perrs.Trace(err)
+		}
+		state = &replaceHostState{
+			OldHost:  oldHost,
+			NewHost:  newHost,
+			OldNodes: oldNodes,
+			NewNodes: newNodes,
+			Step:     replaceStepScaleOut,
+		}
+		if err := state.save(statePath); err != nil {
+			return err
+		}
+	}
+
+	next := func(step string) error {
+		state.Step = step
+		return state.save(statePath)
+	}
+
+	for {
+		switch state.Step {
+		case replaceStepScaleOut:
+			existing, err := m.existingNodes(name)
+			if err != nil {
+				return err
+			}
+			if len(set.NewStringSet(state.NewNodes...).Difference(existing)) == 0 {
+				m.logger.Infof("Instances on %s are already scaled out, skip scale-out", newHost)
+			} else if err := m.ScaleOut(name, topoPath, afterDeploy, final, opt.Deploy, true, gOpt); err != nil {
+				return err
+			}
+			if err := next(replaceStepScaleIn); err != nil {
+				return err
+			}
+		case replaceStepScaleIn:
+			metadata, err := m.meta(name)
+			if err != nil && !errors.Is(perrs.Cause(err), spec.ErrNoTiSparkMaster) {
+				return err
+			}
+			topo := metadata.GetTopology().(*spec.Specification)
+
+			// the instances already removed or pending offline are scaled
+			// in by a previous run
+			offline := set.NewStringSet()
+			for _, node := range offlineNodes(topo) {
+				offline.Insert(node.ID)
+			}
+			var nodes []string
+			topo.IterInstance(func(ins spec.Instance) {
+				if slices.Contains(state.OldNodes, ins.ID()) && !offline.Exist(ins.ID()) {
+					nodes = append(nodes, ins.ID())
+				}
+			})
+			if len(nodes) > 0 {
+				scaleInOpt := gOpt
+				scaleInOpt.Nodes = nodes
+				scaleInOpt.Roles = nil
+				scaleInOpt.Force = opt.Force
+				if err := m.ScaleIn(name, true, scaleInOpt, scaleIn(name, scaleInOpt)); err != nil {
+					return err
+				}
+			}
+			if err := next(replaceStepWait); err != nil {
+				return err
+			}
+		case replaceStepWait:
+			statusOpt := ScaleInStatusOptions{Wait: true, Interval: opt.Interval}
+			if err := m.ScaleInStatus(name, statusOpt, gOpt, true); err != nil {
+				return err
+			}
+			if err := next(replaceStepMonitor); err != nil {
+				return err
+			}
+		case replaceStepMonitor:
+			metadata, err := m.meta(name)
+			if err != nil && !errors.Is(perrs.Cause(err), spec.ErrNoTiSparkMaster) {
+				return err
+			}
+			topo := metadata.GetTopology().(*spec.Specification)
+			if len(topo.Monitors) > 0 || len(topo.Grafanas) > 0 {
+				reloadOpt := gOpt
+				reloadOpt.Nodes = nil
+				reloadOpt.Roles = []string{spec.ComponentPrometheus, spec.ComponentGrafana}
+				if err := m.Reload(name, reloadOpt, false, true); err != nil {
+					return err
+				}
+			}
+
+			_ = os.Remove(topoPath)
+			if err := os.Remove(statePath); err != nil {
+				return perrs.Trace(err)
+			}
+			m.logger.Infof("Replaced host %s with %s of cluster `%s` successfully", oldHost, newHost, name)
+			return nil
+		default:
+			return perrs.Errorf("unknown step %s in %s", state.Step, statePath)
+		}
+	}
+}
+
+// existingNodes returns the IDs of all instances in the cluster
+func (m *Manager) existingNodes(name string) (set.StringSet, error) {
+	metadata, err := m.meta(name)
+	if err != nil && !errors.Is(perrs.Cause(err), spec.ErrNoTiSparkMaster) {
+		return nil, err
+	}
+	nodes := set.NewStringSet()
+	metadata.GetTopology().IterInstance(func(ins spec.Instance) {
+		nodes.Insert(ins.ID())
+	})
+	return nodes, nil
+}
+
+// cloneHostTopology returns a topology containing copies of the instances on
+// oldHost moved to newHost, with the same ports, directories, labels and
+// other settings, and the IDs of the instances on oldHost.
+func cloneHostTopology(topo *spec.Specification, oldHost, newHost string) (*spec.Specification, []string, error) {
+	var oldNodes []string
+	topo.IterInstance(func(ins spec.Instance) {
+		if ins.GetHost() == oldHost {
+			oldNodes = append(oldNodes, ins.ID())
+		}
+	})
+
+	clonedServers := make(map[string]reflect.Value)
+	err := forEachServerSlice(topo, func(key string, servers reflect.Value) error {
+		// deep copy the specs so that the topology is not affected
+		data, err := yaml.Marshal(servers.Interface())
+		if err != nil {
+			return perrs.Trace(err)
+		}
+		copied := reflect.New(servers.Type())
+		if err := yaml.Unmarshal(data, copied.Interface()); err != nil {
+			return perrs.Trace(err)
+		}
+
+		cloned := reflect.MakeSlice(servers.Type(), 0, 0)
+		for i := 0; i < copied.Elem().Len(); i++ {
+			s := copied.Elem().Index(i).Elem()
+			if s.FieldByName("Host").String() != oldHost {
+				continue
+			}
+			moveSpecHost(s, oldHost, newHost)
+			cloned = reflect.Append(cloned, s.Addr())
+		}
+		clonedServers[key] = cloned
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	part := topo.NewPart().(*spec.Specification)
+	err = forEachServerSlice(part, func(key string, servers reflect.Value) error {
+		servers.Set(clonedServers[key])
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return part, oldNodes, nil
+}
+
+// moveSpecHost moves the instance spec from oldHost to newHost, addresses
+// refering to oldHost are changed to newHost, and the fields bound to the
+// old machine are reset.
+func moveSpecHost(s reflect.Value, oldHost, newHost string) {
+	for i := 0; i < s.NumField(); i++ {
+		field := s.Field(i)
+		if field.Kind() != reflect.String || !field.CanSet() {
+			continue
+		}
+		switch v := field.String(); {
+		case v == oldHost:
+			field.SetString(newHost)
+		case strings.HasPrefix(v, oldHost+":"):
+			field.SetString(newHost + strings.TrimPrefix(v, oldHost))
+		}
+	}
+	for _, name := range []string{"ManageHost", "Arch", "OS"} {
+		if field := s.FieldByName(name); field.IsValid() && field.Kind() == reflect.String {
+			field.SetString("")
+		}
+	}
+	for _, name := range []string{"Offline", "Patched"} {
+		if field := s.FieldByName(name); field.IsValid() && field.Kind() == reflect.Bool {
+			field.SetBool(false)
+		}
+	}
+}
+
+// marshalServers marshals the servers of the topology only, so that the
+// global configs of the cluster are inherited when scaling out
+func marshalServers(topo *spec.Specification) ([]byte, error) {
+	servers := make(map[string]any)
+	v := reflect.ValueOf(topo).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() != reflect.Slice || field.Len() == 0 {
+			continue
+		}
+		tag := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if tag == "" || tag == "-" {
+			continue
+		}
+		servers[tag] = field.Interface()
+	}
+	data, err := yaml.Marshal(servers)
+	return data, perrs.Trace(err)
+}
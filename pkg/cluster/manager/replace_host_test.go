@@ -0,0 +1,82 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestCloneHostTopology(t *testing.T) {
+	topo := &spec.Specification{
+		PDServers: []*spec.PDSpec{
+			{Host: "172.16.5.1", ClientPort: 2379, PeerPort: 2380},
+		},
+		TiKVServers: []*spec.TiKVSpec{
+			{Host: "172.16.5.1", Port: 20160, StatusPort: 20180, DeployDir: "/data/tikv-20160"},
+			{
+				Host:          "172.16.5.2",
+				ManageHost:    "10.0.0.2",
+				Port:          20160,
+				StatusPort:    20180,
+				AdvertiseAddr: "172.16.5.2:20160",
+				DeployDir:     "/data/tikv-20160",
+				NumaNode:      "0",
+				Arch:          "amd64",
+				OS:            "linux",
+				Patched:       true,
+				Config:        map[string]any{"server.labels": map[string]any{"host": "h2"}},
+			},
+		},
+		Monitors: []*spec.PrometheusSpec{
+			{Host: "172.16.5.2", Port: 9090},
+		},
+	}
+
+	part, oldNodes, err := cloneHostTopology(topo, "172.16.5.2", "172.16.5.3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"172.16.5.2:20160", "172.16.5.2:9090"}, oldNodes)
+	assert.Empty(t, part.PDServers)
+	require.Len(t, part.TiKVServers, 1)
+	require.Len(t, part.Monitors, 1)
+
+	kv := part.TiKVServers[0]
+	assert.Equal(t, "172.16.5.3", kv.Host)
+	assert.Equal(t, "172.16.5.3:20160", kv.AdvertiseAddr)
+	assert.Empty(t, kv.ManageHost)
+	assert.Empty(t, kv.Arch)
+	assert.Empty(t, kv.OS)
+	assert.False(t, kv.Patched)
+	assert.Equal(t, 20160, kv.Port)
+	assert.Equal(t, "/data/tikv-20160", kv.DeployDir)
+	assert.Equal(t, "0", kv.NumaNode)
+	assert.Equal(t, map[string]any{"host": "h2"}, kv.Config["server.labels"])
+	assert.Equal(t, "172.16.5.3", part.Monitors[0].Host)
+
+	// the original topology is not changed
+	assert.Equal(t, "172.16.5.2", topo.TiKVServers[1].Host)
+	assert.True(t, topo.TiKVServers[1].Patched)
+
+	data, err := marshalServers(part)
+	require.NoError(t, err)
+	servers := make(map[string]any)
+	require.NoError(t, yaml.Unmarshal(data, servers))
+	assert.Len(t, servers, 2)
+	assert.Contains(t, servers, "tikv_servers")
+	assert.Contains(t, servers, "monitoring_servers")
+}
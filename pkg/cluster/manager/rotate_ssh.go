@@ -31,6 +31,12 @@ import (
 
 // RotateSSH rotate public keys of target nodes
 func (m *Manager) RotateSSH(name string, gOpt operator.Options, skipConfirm bool) error {
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) &&
 		!errors.Is(perrs.Cause(err), spec.ErrNoTiSparkMaster) {
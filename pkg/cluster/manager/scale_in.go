@@ -54,6 +54,12 @@ func (m *Manager) ScaleIn(
 		nodes = gOpt.Nodes
 	)
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil &&
 		!errors.Is(perrs.Cause(err), meta.ErrValidate) &&
@@ -0,0 +1,313 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/fatih/color"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/api"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/meta"
+	"github.com/pingcap/tiup/pkg/tui"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// ScaleInStatusOptions controls how the progress of scale-in is tracked
+type ScaleInStatusOptions struct {
+	// Wait keeps polling until all the offline instances are pruned
+	Wait bool
+	// Interval is the interval between two polls
+	Interval time.Duration
+}
+
+// offlineNode is an instance being offlined asynchronously
+type offlineNode struct {
+	ID        string
+	Role      string
+	Host      string
+	StoreAddr string // the address registered in PD, empty for binlog nodes
+}
+
+// regionSample is the region count of a store observed at a moment
+type regionSample struct {
+	regions int
+	at      time.Time
+}
+
+// offlineProgress tracks the regions migrated out of an offline store
+type offlineProgress struct {
+	first *regionSample
+	last  *regionSample
+}
+
+// observe records the region count of the store at the moment
+func (p *offlineProgress) observe(regions int, at time.Time) {
+	sample := &regionSample{regions: regions, at: at}
+	if p.first == nil {
+		p.first = sample
+	}
+	p.last = sample
+}
+
+// rate returns the average regions migrated per minute since the store is
+// first observed, 0 is returned if there is not enough samples
+func (p *offlineProgress) rate() float64 {
+	if p.first == nil || p.last == nil {
+		return 0
+	}
+	elapsed := p.last.at.Sub(p.first.at)
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(p.first.regions-p.last.regions) / elapsed.Minutes()
+}
+
+// eta estimates how long it takes to migrate the remaining regions with the
+// current rate, false is returned if it can not be estimated
+func (p *offlineProgress) eta() (time.Duration, bool) {
+	rate := p.rate()
+	if p.last == nil || rate <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(p.last.regions) / rate * float64(time.Minute)), true
+}
+
+// offlineNodes returns the instances which are pending offline
+func offlineNodes(topo *spec.Specification) []offlineNode {
+	var nodes []offlineNode
+	for _, s := range topo.TiKVServers {
+		if s.Offline {
+			addr := utils.JoinHostPort(s.Host, s.Port)
+			nodes = append(nodes, offlineNode{ID: addr, Role: spec.ComponentTiKV, Host: s.Host, StoreAddr: addr})
+		}
+	}
+	for _, s := range topo.TiFlashServers {
+		if s.Offline {
+			nodes = append(nodes, offlineNode{
+				ID:        utils.JoinHostPort(s.Host, s.GetMainPort()),
+				Role:      spec.ComponentTiFlash,
+				Host:      s.Host,
+				StoreAddr: utils.JoinHostPort(s.Host, s.FlashServicePort),
+			})
+		}
+	}
+	for _, s := range topo.PumpServers {
+		if s.Offline {
+			nodes = append(nodes, offlineNode{ID: utils.JoinHostPort(s.Host, s.Port), Role: spec.ComponentPump, Host: s.Host})
+		}
+	}
+	for _, s := range topo.Drainers {
+		if s.Offline {
+			nodes = append(nodes, offlineNode{ID: utils.JoinHostPort(s.Host, s.Port), Role: spec.ComponentDrainer, Host: s.Host})
+		}
+	}
+	return nodes
+}
+
+// ScaleInStatus displays the progress of the instances being offlined
+// asynchronously, i.e. the remaining regions of TiKV and TiFlash stores, the
+// migration rate and ETA, and prunes the instances once they become Tombstone.
+func (m *Manager) ScaleInStatus(name string, opt ScaleInStatusOptions, gOpt operator.Options, skipConfirm bool) error {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return err
+	}
+	if opt.Interval <= 0 {
+		opt.Interval = 10 * time.Second
+	}
+
+	ctx := ctxt.New(context.Background(), gOpt.Concurrency, m.logger)
+	progress := make(map[string]*offlineProgress)
+	for round := 0; ; round++ {
+		metadata, err := m.meta(name)
+		if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) &&
+			!errors.Is(perrs.Cause(err), spec.ErrNoTiSparkMaster) {
+			return err
+		}
+		topo, ok := metadata.GetTopology().(*spec.Specification)
+		if !ok {
+			return perrs.Errorf("scale-in status is not supported for %s cluster", m.sysName)
+		}
+
+		nodes := offlineNodes(topo)
+		if len(nodes) == 0 {
+			m.logger.Infof("No instance of cluster `%s` is pending offline", name)
+			return nil
+		}
+
+		tlsCfg, err := topo.TLSConfig(m.specManager.Path(name, spec.TLSCertKeyDir))
+		if err != nil {
+			return err
+		}
+		states, err := m.offlineStates(ctx, topo, nodes, tlsCfg, gOpt, progress)
+		if err != nil {
+			return err
+		}
+
+		// the first round only sets the baseline to calculate the rate
+		// if we are not going to wait
+		if opt.Wait || round > 0 {
+			printOfflineProgress(nodes, states, progress)
+		}
+
+		// the stores removed from PD already are destroyed as tombstones
+		tombstone := false
+		for _, state := range states {
+			if state.StateName == "Tombstone" || state.StateName == storeStateRemoved {
+				tombstone = true
+			}
+		}
+		if tombstone {
+			pruneOpt := gOpt
+			pruneOpt.Nodes = nil
+			pruneOpt.Roles = nil
+			if err := m.DestroyTombstone(name, pruneOpt, skipConfirm); err != nil {
+				return err
+			}
+		}
+
+		if !opt.Wait && round > 0 {
+			return nil
+		}
+		time.Sleep(opt.Interval)
+	}
+}
+
+// storeStateRemoved is the state of an offline store which does not exist
+// in PD anymore, e.g. it is removed and purged already
+const storeStateRemoved = "Removed"
+
+// offlineState is the state of an offline node
+type offlineState struct {
+	StateName string
+	Store     uint64
+	Regions   int
+	Leaders   int
+}
+
+// offlineStates fetches the states of the offline nodes and records the
+// region counts into progress
+func (m *Manager) offlineStates(
+	ctx context.Context,
+	topo *spec.Specification,
+	nodes []offlineNode,
+	tlsCfg *tls.Config,
+	gOpt operator.Options,
+	progress map[string]*offlineProgress,
+) (map[string]offlineState, error) {
+	timeout := time.Duration(gOpt.APITimeout) * time.Second
+	pdEndpoints := topo.GetPDListWithManageHost()
+	states := make(map[string]offlineState)
+
+	stores, err := api.NewPDClient(ctx, pdEndpoints, timeout, tlsCfg).GetStores()
+	if err != nil {
+		return nil, perrs.Annotate(err, "get stores from pd")
+	}
+	now := time.Now()
+
+	var binlogClient *api.BinlogClient
+	for _, node := range nodes {
+		state := offlineState{StateName: "N/A"}
+		switch {
+		case node.StoreAddr != "":
+			store := findStore(stores, node.StoreAddr)
+			if store == nil {
+				state.StateName = storeStateRemoved
+				break
+			}
+			state.StateName = store.Store.StateName
+			state.Store = store.Store.GetId()
+			state.Regions = store.Status.RegionCount
+			state.Leaders = store.Status.LeaderCount
+			p, ok := progress[node.ID]
+			if !ok {
+				p = &offlineProgress{}
+				progress[node.ID] = p
+			}
+			p.observe(state.Regions, now)
+		default:
+			if binlogClient == nil {
+				if binlogClient, err = api.NewBinlogClient(pdEndpoints, timeout, tlsCfg); err != nil {
+					return nil, err
+				}
+			}
+			var tombstone bool
+			if node.Role == spec.ComponentPump {
+				tombstone, err = binlogClient.IsPumpTombstone(ctx, node.ID)
+			} else {
+				tombstone, err = binlogClient.IsDrainerTombstone(ctx, node.ID)
+			}
+			if err != nil {
+				m.logger.Debugf("get state of %s %s failed: %v", node.Role, node.ID, err)
+				break
+			}
+			state.StateName = "Offline"
+			if tombstone {
+				state.StateName = "Tombstone"
+			}
+		}
+		states[node.ID] = state
+	}
+	return states, nil
+}
+
+// printOfflineProgress prints the progress of the offline nodes
+func printOfflineProgress(nodes []offlineNode, states map[string]offlineState, progress map[string]*offlineProgress) {
+	rows := [][]string{{"ID", "Role", "Host", "Store", "Status", "Regions", "Leaders", "Rate/min", "ETA"}}
+	for _, node := range nodes {
+		state := states[node.ID]
+		store, regions, leaders, rate, eta := "-", "-", "-", "-", "-"
+		if state.StateName == storeStateRemoved {
+			eta = "done"
+		}
+		if node.StoreAddr != "" && state.Store != 0 {
+			store = strconv.FormatUint(state.Store, 10)
+			regions = strconv.Itoa(state.Regions)
+			leaders = strconv.Itoa(state.Leaders)
+			if p, ok := progress[node.ID]; ok {
+				if p.first != p.last {
+					rate = strconv.FormatFloat(p.rate(), 'f', 1, 64)
+				}
+				if d, ok := p.eta(); ok {
+					eta = formatInstanceSince(d.Truncate(time.Second))
+				}
+			}
+			if state.StateName == "Tombstone" {
+				eta = "done"
+			}
+		}
+		rows = append(rows, []string{
+			color.CyanString(node.ID),
+			node.Role,
+			node.Host,
+			store,
+			formatInstanceStatus(state.StateName),
+			regions,
+			leaders,
+			rate,
+			eta,
+		})
+	}
+	fmt.Printf("\n%s\n", time.Now().Format(time.DateTime))
+	tui.PrintTable(rows, true)
+}
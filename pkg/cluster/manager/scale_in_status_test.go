@@ -0,0 +1,68 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfflineProgress(t *testing.T) {
+	p := &offlineProgress{}
+	_, ok := p.eta()
+	assert.False(t, ok)
+
+	now := time.Now()
+	p.observe(1000, now)
+	assert.Equal(t, float64(0), p.rate())
+	_, ok = p.eta()
+	assert.False(t, ok)
+
+	p.observe(900, now.Add(time.Minute))
+	p.observe(800, now.Add(2*time.Minute))
+	assert.Equal(t, float64(100), p.rate())
+	eta, ok := p.eta()
+	assert.True(t, ok)
+	assert.Equal(t, 8*time.Minute, eta)
+
+	// regions may be moved in when the store is busy
+	p.observe(1200, now.Add(3*time.Minute))
+	assert.Less(t, p.rate(), float64(0))
+	_, ok = p.eta()
+	assert.False(t, ok)
+}
+
+func TestOfflineNodes(t *testing.T) {
+	topo := &spec.Specification{
+		TiKVServers: []*spec.TiKVSpec{
+			{Host: "172.16.5.1", Port: 20160},
+			{Host: "172.16.5.2", Port: 20160, Offline: true},
+		},
+		TiFlashServers: []*spec.TiFlashSpec{
+			{Host: "172.16.5.3", TCPPort: 9000, FlashServicePort: 3930, Offline: true},
+		},
+		PumpServers: []*spec.PumpSpec{
+			{Host: "172.16.5.4", Port: 8250, Offline: true},
+		},
+	}
+	nodes := offlineNodes(topo)
+	assert.Equal(t, []offlineNode{
+		{ID: "172.16.5.2:20160", Role: spec.ComponentTiKV, Host: "172.16.5.2", StoreAddr: "172.16.5.2:20160"},
+		{ID: "172.16.5.3:9000", Role: spec.ComponentTiFlash, Host: "172.16.5.3", StoreAddr: "172.16.5.3:3930"},
+		{ID: "172.16.5.4:8250", Role: spec.ComponentPump, Host: "172.16.5.4"},
+	}, nodes)
+}
@@ -56,6 +56,12 @@ func (m *Manager) ScaleOut(
 		return err
 	}
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	// allow specific validation errors so that user can recover a broken
 	// cluster if it is somehow in a bad state.
@@ -98,6 +104,12 @@ func (m *Manager) ScaleOut(
 
 		if newPartTopo, ok := newPart.(*spec.Specification); ok {
 			newPartTopo.AdjustByVersion(base.Version)
+			if err := newPartTopo.ApplyPlacementLabels(); err != nil {
+				return err
+			}
+			if err := newPartTopo.CheckTemplates(); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -194,6 +206,12 @@ func (m *Manager) ScaleOut(
 		}
 	}
 
+	if newPartTopo, ok := newPart.(*spec.Specification); ok {
+		if err := newPartTopo.CopyTemplates(m.specManager.Path(name, spec.TemplatesDirName)); err != nil {
+			return err
+		}
+	}
+
 	// Build the scale out tasks
 	t, err := buildScaleOutTask(
 		m, name, metadata, mergedTopo, opt, sshConnProps, sshProxyProps, newPart,
@@ -208,7 +226,9 @@ func (m *Manager) ScaleOut(
 		m.logger,
 	)
 	ctx = context.WithValue(ctx, ctxt.CtxBaseTopo, topo)
+	ctx, journal := task.WithJournal(ctx)
 	if err := t.Execute(ctx); err != nil {
+		m.rollback(ctx, journal, opt.NoRollback)
 		if errorx.Cast(err) != nil {
 			// FIXME: Map possible task errors and give suggestions.
 			return err
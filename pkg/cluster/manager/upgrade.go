@@ -66,6 +66,12 @@ func (m *Manager) Upgrade(name string, clusterVersion string, componentVersions
 		return err
 	}
 
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	metadata, err := m.meta(name)
 	if err != nil {
 		return err
@@ -0,0 +1,502 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/checkpoint"
+	"github.com/pingcap/tiup/pkg/cluster/clusterutil"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/meta"
+	"github.com/pingcap/tiup/pkg/set"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// the types of events reported by watch
+const (
+	WatchEventRestarted = "restarted"
+	WatchEventFlapping  = "flapping"
+	WatchEventDown      = "down"
+	WatchEventRecovered = "recovered"
+	WatchEventRecover   = "recover"
+)
+
+const watchEventsFile = "watch-events.log"
+
+// defaultWatchBackoff is the backoff policy of the roles not specified
+var defaultWatchBackoff = BackoffPolicy{Initial: 30 * time.Second, Max: 10 * time.Minute}
+
+// BackoffPolicy is the policy to retry recovering a down instance, the delay
+// starts from Initial and doubles after every attempt until reaching Max.
+type BackoffPolicy struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// delay returns the delay before the next attempt after attempts failed ones
+func (p BackoffPolicy) delay(attempts int) time.Duration {
+	d := p.Initial
+	for i := 0; i < attempts && d < p.Max; i++ {
+		d *= 2
+	}
+	return min(d, p.Max)
+}
+
+// ParseBackoffPolicies parses the per-role backoff policies in format of
+// role=initial[:max], e.g. tikv=1m:30m
+func ParseBackoffPolicies(specs []string) (map[string]BackoffPolicy, error) {
+	policies := make(map[string]BackoffPolicy)
+	for _, s := range specs {
+		role, value, ok := strings.Cut(s, "=")
+		if !ok || role == "" || value == "" {
+			return nil, perrs.Errorf("invalid backoff policy %s, the format is role=initial[:max]", s)
+		}
+		initial, maximum, hasMax := strings.Cut(value, ":")
+		policy := BackoffPolicy{}
+		var err error
+		if policy.Initial, err = time.ParseDuration(initial); err != nil {
+			return nil, perrs.Annotatef(err, "invalid backoff policy %s", s)
+		}
+		policy.Max = defaultWatchBackoff.Max
+		if hasMax {
+			if policy.Max, err = time.ParseDuration(maximum); err != nil {
+				return nil, perrs.Annotatef(err, "invalid backoff policy %s", s)
+			}
+		}
+		if policy.Initial <= 0 || policy.Max < policy.Initial {
+			return nil, perrs.Errorf("invalid backoff policy %s, the max delay must not be less than the initial one", s)
+		}
+		policies[role] = policy
+	}
+	return policies, nil
+}
+
+// WatchOptions controls how the instances are watched
+type WatchOptions struct {
+	// Interval is the interval between two polls
+	Interval time.Duration
+	// FlapCount restarts within FlapWindow are reported as flapping
+	FlapCount  int
+	FlapWindow time.Duration
+	// DownThreshold is how long an instance stays down before it's reported
+	DownThreshold time.Duration
+	// AutoRecover starts the instances which are down past the threshold
+	AutoRecover bool
+	// Backoff is the per-role backoff policies of recovering
+	Backoff map[string]BackoffPolicy
+	// JournalLines is the number of journal lines attached to the events
+	JournalLines int
+	// Webhook is the URL the events are posted to
+	Webhook string
+	// Hook is the local script run with the event
+	Hook string
+}
+
+// backoff returns the backoff policy of the role
+func (opt WatchOptions) backoff(role string) BackoffPolicy {
+	if p, ok := opt.Backoff[role]; ok {
+		return p
+	}
+	return defaultWatchBackoff
+}
+
+// WatchEvent is an event of an instance reported by watch
+type WatchEvent struct {
+	Time     time.Time `json:"time"`
+	Cluster  string    `json:"cluster"`
+	Instance string    `json:"instance"`
+	Role     string    `json:"role"`
+	Host     string    `json:"host"`
+	Type     string    `json:"type"`
+	Status   string    `json:"status"`
+	Restarts int       `json:"restarts"`
+	Message  string    `json:"message,omitempty"`
+	Journal  string    `json:"journal,omitempty"`
+}
+
+// watchObservation is the state of an instance observed by one poll
+type watchObservation struct {
+	// Active is true if the systemd service is active
+	Active bool
+	// Healthy is true if the instance is up according to its status API
+	Healthy bool
+	// Uptime is how long the service has been active
+	Uptime time.Duration
+}
+
+// watchState tracks the observations of an instance between polls
+type watchState struct {
+	last         *watchObservation
+	restarts     []time.Time
+	flapping     bool
+	downSince    time.Time
+	downReported bool
+	attempts     int
+	nextAttempt  time.Time
+}
+
+// observe updates the state with the observation, returns the events
+// happened and whether the instance should be recovered now
+func (s *watchState) observe(obs watchObservation, now time.Time, opt WatchOptions, backoff BackoffPolicy) (events []string, recover bool) {
+	if s.last != nil && obs.Active && (!s.last.Active || obs.Uptime < s.last.Uptime) {
+		s.restarts = append(s.restarts, now)
+		events = append(events, WatchEventRestarted)
+	}
+	s.last = &obs
+
+	for len(s.restarts) > 0 && now.Sub(s.restarts[0]) > opt.FlapWindow {
+		s.restarts = s.restarts[1:]
+	}
+	switch {
+	case opt.FlapCount > 0 && len(s.restarts) >= opt.FlapCount:
+		if !s.flapping {
+			s.flapping = true
+			events = append(events, WatchEventFlapping)
+		}
+	default:
+		s.flapping = false
+	}
+
+	if obs.Healthy {
+		if s.downReported {
+			events = append(events, WatchEventRecovered)
+		}
+		s.downSince = time.Time{}
+		s.downReported = false
+		s.attempts = 0
+		s.nextAttempt = time.Time{}
+		return events, false
+	}
+
+	if s.downSince.IsZero() {
+		s.downSince = now
+	}
+	if now.Sub(s.downSince) < opt.DownThreshold {
+		return events, false
+	}
+	if !s.downReported {
+		s.downReported = true
+		events = append(events, WatchEventDown)
+	}
+	if opt.AutoRecover && !now.Before(s.nextAttempt) {
+		s.nextAttempt = now.Add(backoff.delay(s.attempts))
+		s.attempts++
+		recover = true
+	}
+	return events, recover
+}
+
+// watchTarget is the instances to watch loaded from the meta of a round
+type watchTarget struct {
+	ctx         context.Context
+	tlsCfg      *tls.Config
+	instances   []spec.Instance
+	systemdMode string
+	masterList  []string
+}
+
+// loadWatchTarget loads the latest meta of the cluster and returns the
+// instances selected, the ones in the maintenance window are excluded
+func (m *Manager) loadWatchTarget(name string, gOpt operator.Options) (*watchTarget, error) {
+	metadata, err := m.meta(name)
+	if err != nil && !errors.Is(perrs.Cause(err), meta.ErrValidate) &&
+		!errors.Is(perrs.Cause(err), spec.ErrNoTiSparkMaster) {
+		return nil, err
+	}
+	topo := metadata.GetTopology()
+	base := metadata.GetBaseMeta()
+
+	ctx, err := m.clusterSSHContext(name, topo, base.User, gOpt)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg, err := topo.TLSConfig(m.specManager.Path(name, spec.TLSCertKeyDir))
+	if err != nil {
+		return nil, err
+	}
+
+	var maintenance *spec.MaintenanceWindow
+	if clusterMeta, ok := metadata.(*spec.ClusterMeta); ok {
+		maintenance = clusterMeta.Maintenance
+	}
+	return &watchTarget{
+		ctx:         ctx,
+		tlsCfg:      tlsCfg,
+		instances:   excludeMaintenance(filterInstances(topo, gOpt), maintenance, time.Now()),
+		systemdMode: string(topo.BaseTopo().GlobalOptions.SystemdMode),
+		masterList:  topo.BaseTopo().MasterList,
+	}, nil
+}
+
+// excludeMaintenance removes the instances in the maintenance window, the
+// window covers all instances if no node is specified
+func excludeMaintenance(instances []spec.Instance, w *spec.MaintenanceWindow, now time.Time) []spec.Instance {
+	if w == nil || now.After(w.End) {
+		return instances
+	}
+	if len(w.Nodes) == 0 {
+		return nil
+	}
+	nodes := set.NewStringSet(w.Nodes...)
+	return slices.DeleteFunc(instances, func(ins spec.Instance) bool {
+		return nodes.Exist(ins.ID())
+	})
+}
+
+// isRemovedStatus returns true if the instance is being or has been scaled
+// in according to its status, such instances are expected to be down
+func isRemovedStatus(status string) bool {
+	lower := strings.ToLower(status)
+	return strings.Contains(lower, "offline") || strings.HasPrefix(lower, "tombstone")
+}
+
+// Watch keeps polling the states of the instances, reports the instances
+// restarting in a loop or staying down, and recovers them if required.
+// The meta is reloaded every round, so the instances scaled in, scaled out
+// or put in the maintenance window are followed.
+func (m *Manager) Watch(name string, opt WatchOptions, gOpt operator.Options) error {
+	if err := clusterutil.ValidateClusterNameOrError(name); err != nil {
+		return err
+	}
+	if opt.Interval <= 0 {
+		opt.Interval = 30 * time.Second
+	}
+
+	target, err := m.loadWatchTarget(name, gOpt)
+	if err != nil {
+		return err
+	}
+	if len(target.instances) == 0 {
+		return perrs.Errorf("no instance of cluster `%s` is selected", name)
+	}
+	statusTimeout := time.Duration(gOpt.APITimeout) * time.Second
+
+	m.logger.Infof("Watching %d instances of cluster `%s` every %s, events are recorded in %s",
+		len(target.instances), name, opt.Interval, m.specManager.Path(name, watchEventsFile))
+
+	var mu sync.Mutex
+	states := make(map[string]*watchState)
+	for {
+		now := time.Now()
+		ctx := target.ctx
+		watched := set.NewStringSet()
+		for _, ins := range target.instances {
+			watched.Insert(ins.ID())
+		}
+		// forget the instances no longer watched, so that they start over
+		// if they come back
+		for id := range states {
+			if !watched.Exist(id) {
+				delete(states, id)
+			}
+		}
+
+		forEachInstance(target.instances, gOpt.Concurrency, func(ins spec.Instance) {
+			status := ins.Status(ctx, statusTimeout, target.tlsCfg, target.masterList...)
+			if isRemovedStatus(status) {
+				mu.Lock()
+				delete(states, ins.ID())
+				mu.Unlock()
+				return
+			}
+			obs := watchObservation{}
+			if e, found := ctxt.GetInner(ctx).GetExecutor(ins.GetManageHost()); found {
+				active, _, since, _ := operator.GetServiceStatus(checkpoint.NewContext(ctx), e, ins.ServiceName(), target.systemdMode, target.systemdMode)
+				obs.Active = active == "active"
+				obs.Uptime = since
+			}
+			lower := strings.ToLower(status)
+			if status == "-" {
+				// no status API, rely on the systemd service
+				obs.Healthy = obs.Active
+			} else {
+				obs.Healthy = strings.HasPrefix(lower, "up") || strings.HasPrefix(lower, "healthy")
+			}
+
+			mu.Lock()
+			state, ok := states[ins.ID()]
+			if !ok {
+				state = &watchState{}
+				states[ins.ID()] = state
+			}
+			events, recover := state.observe(obs, now, opt, opt.backoff(ins.Role()))
+			restarts := len(state.restarts)
+			attempts := state.attempts
+			mu.Unlock()
+
+			for _, typ := range events {
+				event := WatchEvent{
+					Time:     now,
+					Cluster:  name,
+					Instance: ins.ID(),
+					Role:     ins.Role(),
+					Host:     ins.GetHost(),
+					Type:     typ,
+					Status:   status,
+					Restarts: restarts,
+				}
+				switch typ {
+				case WatchEventFlapping:
+					event.Message = fmt.Sprintf("restarted %d times in %s", restarts, opt.FlapWindow)
+					event.Journal = m.watchJournal(ctx, ins, opt.JournalLines, target.systemdMode)
+				case WatchEventDown:
+					event.Message = fmt.Sprintf("down for more than %s", opt.DownThreshold)
+					event.Journal = m.watchJournal(ctx, ins, opt.JournalLines, target.systemdMode)
+				}
+				m.reportWatchEvent(ctx, event, opt)
+			}
+
+			if recover {
+				event := WatchEvent{
+					Time:     time.Now(),
+					Cluster:  name,
+					Instance: ins.ID(),
+					Role:     ins.Role(),
+					Host:     ins.GetHost(),
+					Type:     WatchEventRecover,
+					Status:   status,
+					Restarts: restarts,
+					Message:  fmt.Sprintf("attempt %d to start the instance succeeded", attempts),
+				}
+				if err := m.watchRecover(name, ins, gOpt); err != nil {
+					event.Message = fmt.Sprintf("attempt %d to start the instance failed: %s", attempts, err)
+				}
+				m.reportWatchEvent(ctx, event, opt)
+			}
+		})
+
+		time.Sleep(opt.Interval)
+
+		next, err := m.loadWatchTarget(name, gOpt)
+		if err != nil {
+			// keep watching the instances known, the meta may be being
+			// written by another operation
+			m.logger.Warnf("Failed to reload the meta of cluster `%s`: %v", name, err)
+			continue
+		}
+		target = next
+	}
+}
+
+// watchRecover starts the instance holding the operation lock of the
+// cluster, the instance is skipped if it's no longer watched by then
+func (m *Manager) watchRecover(name string, ins spec.Instance, gOpt operator.Options) error {
+	unlock, err := m.lockCluster(name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// the meta may be changed while waiting for the lock
+	target, err := m.loadWatchTarget(name, gOpt)
+	if err != nil {
+		return err
+	}
+	idx := slices.IndexFunc(target.instances, func(i spec.Instance) bool {
+		return i.ID() == ins.ID()
+	})
+	if idx < 0 {
+		return perrs.Errorf("instance %s is removed or in maintenance", ins.ID())
+	}
+	return operator.StartComponent(checkpoint.NewContext(target.ctx), []spec.Instance{target.instances[idx]},
+		set.NewStringSet(), gOpt, target.tlsCfg, target.systemdMode)
+}
+
+// watchJournal returns the latest lines of journal of the instance
+func (m *Manager) watchJournal(ctx context.Context, ins spec.Instance, lines int, systemdMode string) string {
+	if lines <= 0 {
+		return ""
+	}
+	e, found := ctxt.GetInner(ctx).GetExecutor(ins.GetManageHost())
+	if !found {
+		return ""
+	}
+	cmd := fmt.Sprintf("journalctl -u %s -n %d --no-pager", ins.ServiceName(), lines)
+	sudo := true
+	if systemdMode == string(spec.UserMode) {
+		cmd = fmt.Sprintf("journalctl --user -u %s -n %d --no-pager", ins.ServiceName(), lines)
+		sudo = false
+	}
+	stdout, _, err := e.Execute(ctx, cmd, sudo)
+	if err != nil {
+		m.logger.Debugf("get journal of %s failed: %v", ins.ID(), err)
+		return ""
+	}
+	return string(stdout)
+}
+
+// reportWatchEvent records the event and runs the hooks
+func (m *Manager) reportWatchEvent(ctx context.Context, event WatchEvent, opt WatchOptions) {
+	switch event.Type {
+	case WatchEventRestarted, WatchEventRecovered:
+		m.logger.Infof("[%s] %s %s %s", event.Time.Format(time.DateTime), event.Instance, event.Type, event.Message)
+	default:
+		m.logger.Warnf("[%s] %s %s: %s", event.Time.Format(time.DateTime), event.Instance, event.Type, event.Message)
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		m.logger.Warnf("Failed to marshal the event: %v", err)
+		return
+	}
+
+	path := m.specManager.Path(event.Cluster, watchEventsFile)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err == nil {
+		_, err = f.Write(append(data, '\n'))
+		f.Close()
+	}
+	if err != nil {
+		m.logger.Warnf("Failed to record the event to %s: %v", path, err)
+	}
+
+	// the hooks are only run for the events need attention
+	if event.Type != WatchEventFlapping && event.Type != WatchEventDown {
+		return
+	}
+	if opt.Webhook != "" {
+		if _, err := utils.NewHTTPClient(10*time.Second, nil).Post(ctx, opt.Webhook, bytes.NewReader(data)); err != nil {
+			m.logger.Warnf("Failed to post the event to %s: %v", opt.Webhook, err)
+		}
+	}
+	if opt.Hook != "" {
+		cmd := exec.CommandContext(ctx, opt.Hook)
+		cmd.Stdin = bytes.NewReader(data)
+		cmd.Env = append(os.Environ(),
+			"TIUP_WATCH_CLUSTER="+event.Cluster,
+			"TIUP_WATCH_INSTANCE="+event.Instance,
+			"TIUP_WATCH_ROLE="+event.Role,
+			"TIUP_WATCH_HOST="+event.Host,
+			"TIUP_WATCH_EVENT="+event.Type,
+		)
+		if output, err := cmd.CombinedOutput(); err != nil {
+			m.logger.Warnf("Failed to run hook %s: %v, output: %s", opt.Hook, err, output)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"testing"
+	"time"
+
+	operator "github.com/pingcap/tiup/pkg/cluster/operation"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackoffPolicies(t *testing.T) {
+	policies, err := ParseBackoffPolicies([]string{"tikv=1m:30m", "tidb=10s"})
+	require.NoError(t, err)
+	assert.Equal(t, BackoffPolicy{Initial: time.Minute, Max: 30 * time.Minute}, policies["tikv"])
+	assert.Equal(t, BackoffPolicy{Initial: 10 * time.Second, Max: defaultWatchBackoff.Max}, policies["tidb"])
+
+	for _, s := range []string{"tikv", "tikv=", "=1m", "tikv=abc", "tikv=1m:abc", "tikv=10m:1m"} {
+		_, err := ParseBackoffPolicies([]string{s})
+		assert.Error(t, err, s)
+	}
+
+	p := BackoffPolicy{Initial: 10 * time.Second, Max: time.Minute}
+	assert.Equal(t, 10*time.Second, p.delay(0))
+	assert.Equal(t, 20*time.Second, p.delay(1))
+	assert.Equal(t, 40*time.Second, p.delay(2))
+	assert.Equal(t, time.Minute, p.delay(3))
+	assert.Equal(t, time.Minute, p.delay(100))
+}
+
+func TestWatchStateFlapping(t *testing.T) {
+	opt := WatchOptions{FlapCount: 2, FlapWindow: 5 * time.Minute, DownThreshold: time.Hour}
+	s := &watchState{}
+	now := time.Now()
+
+	events, _ := s.observe(watchObservation{Active: true, Healthy: true, Uptime: time.Hour}, now, opt, defaultWatchBackoff)
+	assert.Empty(t, events)
+
+	// the uptime is reset
+	now = now.Add(time.Minute)
+	events, _ = s.observe(watchObservation{Active: true, Healthy: true, Uptime: 10 * time.Second}, now, opt, defaultWatchBackoff)
+	assert.Equal(t, []string{WatchEventRestarted}, events)
+
+	// the service is restarting
+	now = now.Add(time.Minute)
+	events, _ = s.observe(watchObservation{}, now, opt, defaultWatchBackoff)
+	assert.Empty(t, events)
+	now = now.Add(time.Minute)
+	events, _ = s.observe(watchObservation{Active: true, Healthy: true, Uptime: 10 * time.Second}, now, opt, defaultWatchBackoff)
+	assert.Equal(t, []string{WatchEventRestarted, WatchEventFlapping}, events)
+
+	// flapping is reported only once
+	now = now.Add(time.Minute)
+	events, _ = s.observe(watchObservation{Active: true, Healthy: true, Uptime: 5 * time.Second}, now, opt, defaultWatchBackoff)
+	assert.Equal(t, []string{WatchEventRestarted}, events)
+
+	// the restarts are out of the window
+	now = now.Add(time.Hour)
+	events, _ = s.observe(watchObservation{Active: true, Healthy: true, Uptime: time.Hour}, now, opt, defaultWatchBackoff)
+	assert.Empty(t, events)
+	assert.False(t, s.flapping)
+}
+
+func TestWatchStateDown(t *testing.T) {
+	opt := WatchOptions{DownThreshold: 2 * time.Minute, AutoRecover: true}
+	backoff := BackoffPolicy{Initial: time.Minute, Max: 4 * time.Minute}
+	s := &watchState{}
+	now := time.Now()
+
+	events, recover := s.observe(watchObservation{}, now, opt, backoff)
+	assert.Empty(t, events)
+	assert.False(t, recover)
+
+	now = now.Add(2 * time.Minute)
+	events, recover = s.observe(watchObservation{}, now, opt, backoff)
+	assert.Equal(t, []string{WatchEventDown}, events)
+	assert.True(t, recover)
+
+	// wait for the backoff
+	now = now.Add(30 * time.Second)
+	events, recover = s.observe(watchObservation{}, now, opt, backoff)
+	assert.Empty(t, events)
+	assert.False(t, recover)
+	now = now.Add(30 * time.Second)
+	_, recover = s.observe(watchObservation{}, now, opt, backoff)
+	assert.True(t, recover)
+	now = now.Add(time.Minute)
+	_, recover = s.observe(watchObservation{}, now, opt, backoff)
+	assert.False(t, recover)
+	now = now.Add(time.Minute)
+	_, recover = s.observe(watchObservation{}, now, opt, backoff)
+	assert.True(t, recover)
+
+	events, recover = s.observe(watchObservation{Active: true, Healthy: true}, now.Add(time.Minute), opt, backoff)
+	assert.Equal(t, []string{WatchEventRestarted, WatchEventRecovered}, events)
+	assert.False(t, recover)
+	assert.Equal(t, 0, s.attempts)
+}
+
+func TestExcludeMaintenance(t *testing.T) {
+	topo := &spec.Specification{
+		TiKVServers: []*spec.TiKVSpec{
+			{Host: "172.16.5.1", Port: 20160},
+			{Host: "172.16.5.2", Port: 20160},
+		},
+	}
+	ids := func(instances []spec.Instance) []string {
+		var ids []string
+		for _, ins := range instances {
+			ids = append(ids, ins.ID())
+		}
+		return ids
+	}
+	now := time.Now()
+	all := []string{"172.16.5.1:20160", "172.16.5.2:20160"}
+
+	assert.Equal(t, all, ids(excludeMaintenance(filterInstances(topo, operator.Options{}), nil, now)))
+	w := &spec.MaintenanceWindow{Start: now.Add(-time.Hour), End: now.Add(time.Hour), Nodes: []string{"172.16.5.2:20160"}}
+	assert.Equal(t, []string{"172.16.5.1:20160"}, ids(excludeMaintenance(filterInstances(topo, operator.Options{}), w, now)))
+	w.Nodes = nil
+	assert.Empty(t, excludeMaintenance(filterInstances(topo, operator.Options{}), w, now))
+	// the expired window is ignored
+	assert.Equal(t, all, ids(excludeMaintenance(filterInstances(topo, operator.Options{}), w, now.Add(2*time.Hour))))
+}
+
+func TestIsRemovedStatus(t *testing.T) {
+	for _, s := range []string{"Pending Offline", "Offline", "Tombstone"} {
+		assert.True(t, isRemovedStatus(s), s)
+	}
+	for _, s := range []string{"Up", "Down", "Disconnected", "-", "Healthy"} {
+		assert.False(t, isRemovedStatus(s), s)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metastore
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// excludedDirs are the directories of a cluster not stored in the backend,
+// the patches are too large for the size limit of values in etcd and kept
+// in the local directory, the backups of the meta are dropped
+var excludedDirs = []string{spec.PatchDirName, spec.BackupDirName}
+
+// archive writes the working copy of a cluster as a gzipped tarball, only
+// regular files and directories are supported
+func archive(w io.Writer, dir string) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	err := filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil || name == "." {
+			return err
+		}
+		if slices.Contains(excludedDirs, name) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return perrs.Errorf("%s is not a regular file or directory", path)
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return perrs.AddStack(err)
+	}
+	if err := tw.Close(); err != nil {
+		return perrs.AddStack(err)
+	}
+	return perrs.AddStack(gw.Close())
+}
+
+// extract extracts the tarball written by archive into dir, the entries
+// other than regular files and directories, or out of dir are rejected
+func extract(r io.Reader, dir string) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return perrs.AddStack(err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return perrs.AddStack(err)
+		}
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) {
+			return perrs.Errorf("invalid entry %s in archive: out of the target directory", hdr.Name)
+		}
+		target := filepath.Join(dir, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := utils.MkdirAll(target, hdr.FileInfo().Mode().Perm()); err != nil {
+				return perrs.AddStack(err)
+			}
+		case tar.TypeReg:
+			if err := utils.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return perrs.AddStack(err)
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_EXCL, hdr.FileInfo().Mode().Perm())
+			if err != nil {
+				return perrs.AddStack(err)
+			}
+			_, err = io.Copy(f, tr)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return perrs.AddStack(err)
+			}
+		default:
+			return perrs.Errorf("invalid entry %s in archive: not a regular file or directory", hdr.Name)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metastore
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "ssh"), 0700))
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "backup"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(src, metaFileName), []byte("user: tidb\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "ssh", "id_rsa"), []byte("private key"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "backup", "meta-1.yaml"), []byte("user: tidb\n"), 0644))
+	require.NoError(t, os.Symlink(t.TempDir(), filepath.Join(src, "patch")))
+
+	// the backups and patches are excluded
+	var buf bytes.Buffer
+	require.NoError(t, archive(&buf, src))
+	dst := filepath.Join(t.TempDir(), "test")
+	require.NoError(t, extract(&buf, dst))
+	require.FileExists(t, filepath.Join(dst, metaFileName))
+	fi, err := os.Stat(filepath.Join(dst, "ssh", "id_rsa"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	require.NoDirExists(t, filepath.Join(dst, "backup"))
+	require.NoFileExists(t, filepath.Join(dst, "patch"))
+
+	// other links are not supported
+	require.NoError(t, os.Symlink("meta.yaml", filepath.Join(src, "link")))
+	require.Error(t, archive(&buf, src))
+}
+
+func TestExtractInvalid(t *testing.T) {
+	tarball := func(hdr *tar.Header) *bytes.Buffer {
+		var buf bytes.Buffer
+		gw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gw)
+		require.NoError(t, tw.WriteHeader(hdr))
+		require.NoError(t, tw.Close())
+		require.NoError(t, gw.Close())
+		return &buf
+	}
+
+	dir := filepath.Join(t.TempDir(), "test")
+	for _, hdr := range []*tar.Header{
+		{Name: "../escaped", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "/abs", Typeflag: tar.TypeReg, Mode: 0644},
+		{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "meta.yaml"},
+		{Name: "hard", Typeflag: tar.TypeLink, Linkname: "meta.yaml"},
+	} {
+		require.Error(t, extract(tarball(hdr), dir), hdr.Name)
+	}
+	require.NoFileExists(t, filepath.Join(filepath.Dir(dir), "escaped"))
+	require.NoFileExists(t, filepath.Join(dir, "link"))
+}
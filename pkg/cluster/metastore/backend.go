@@ -0,0 +1,114 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metastore stores the metadata of clusters in a pluggable backend,
+// optionally encrypted, so that the metadata is not bound to a single
+// control machine. The metadata of a cluster is checked out from the backend
+// into a working directory owned by the process before running a command,
+// and committed back after the command finishes.
+package metastore
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/joomcode/errorx"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+)
+
+var (
+	errNS = errorx.NewNamespace("metastore")
+	// ErrLocked is the error when the metadata of a cluster is locked by others
+	ErrLocked = errNS.NewType("locked")
+	// ErrNotExist is the error when the metadata of a cluster is not found in the backend
+	ErrNotExist = errors.New("metadata does not exist")
+)
+
+// LockInfo describes who is holding the lock of a cluster
+type LockInfo = spec.OperationLock
+
+// lockedError returns the error that the cluster is locked by holder
+func lockedError(name string, holder LockInfo) error {
+	return ErrLocked.New("Metadata of cluster '%s' is locked by %s", name, holder)
+}
+
+// Backend is the storage of the metadata archives of clusters
+type Backend interface {
+	// Get returns the archive of the cluster, ErrNotExist is returned if
+	// it does not exist
+	Get(ctx context.Context, name string) ([]byte, error)
+	// Put saves the archive of the cluster
+	Put(ctx context.Context, name string, data []byte) error
+	// Delete removes the archive of the cluster, it is not an error if the
+	// archive does not exist
+	Delete(ctx context.Context, name string) error
+	// List returns the names of the clusters in the backend
+	List(ctx context.Context) ([]string, error)
+	// Lock acquires the exclusive lock of the cluster, ErrLocked is returned
+	// if it is held by others
+	Lock(ctx context.Context, name string, info LockInfo) error
+	// Unlock releases the lock of the cluster acquired by Lock
+	Unlock(ctx context.Context, name string) error
+	// Holder returns who is holding the lock of the cluster, nil is returned
+	// if it is not locked
+	Holder(ctx context.Context, name string) (*LockInfo, error)
+	// ForceUnlock releases the lock of the cluster no matter who holds it
+	ForceUnlock(ctx context.Context, name string) error
+	// Close releases the resources of the backend
+	Close() error
+}
+
+// ParseBackend creates the backend from its URL, the supported schemes are:
+//
+//	file:///path/to/dir
+//	s3://bucket/prefix?endpoint=host:port&region=us-east-1&insecure=true
+//	etcd://host1:2379,host2:2379/prefix?cacert=ca.crt&cert=client.crt&key=client.pem&user=root
+//
+// The credentials of S3 are read from the access-key and secret-access-key
+// parameters, or the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment
+// variables. The S3 storage must support conditional writes (If-None-Match
+// and If-Match of PutObject) to lock the clusters.
+//
+// The connection to etcd is secured by TLS if the cacert or cert parameter
+// is set, the password of the user is read from the password parameter or
+// the ETCDCTL_PASSWORD environment variable.
+func ParseBackend(rawURL string) (Backend, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, perrs.Annotatef(err, "parse meta backend %s", rawURL)
+	}
+	prefix := strings.Trim(u.Path, "/")
+
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" {
+			return nil, perrs.Errorf("path of the file meta backend is not specified")
+		}
+		return newFileBackend(u.Path)
+	case "s3":
+		if u.Host == "" {
+			return nil, perrs.Errorf("bucket of the s3 meta backend is not specified")
+		}
+		return newS3Backend(u.Host, prefix, u.Query())
+	case "etcd":
+		if u.Host == "" {
+			return nil, perrs.Errorf("endpoints of the etcd meta backend are not specified")
+		}
+		return newEtcdBackend(strings.Split(u.Host, ","), prefix, u.Query())
+	default:
+		return nil, perrs.Errorf("unsupported meta backend %s", rawURL)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metastore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+
+	perrs "github.com/pingcap/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// sealMagic is the header of encrypted archives
+	sealMagic = "TIUPMETA\x01"
+	saltSize  = 16
+)
+
+// isSealed checks if the archive is encrypted
+func isSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(sealMagic))
+}
+
+// deriveKey derives the AES-256 key from the secret, which is either a
+// passphrase or the content of a keyfile
+func deriveKey(secret, salt []byte) ([]byte, error) {
+	key, err := scrypt.Key(secret, salt, 1<<15, 8, 1, 32)
+	return key, perrs.AddStack(err)
+}
+
+// seal encrypts the archive with AES-GCM, the output is laid out as
+// magic | salt | nonce | ciphertext
+func seal(secret, plain []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, perrs.AddStack(err)
+	}
+	gcm, err := newGCM(secret, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, perrs.AddStack(err)
+	}
+
+	out := make([]byte, 0, len(sealMagic)+len(salt)+len(nonce)+len(plain)+gcm.Overhead())
+	out = append(out, sealMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	// authenticate the header as well
+	return gcm.Seal(out, nonce, plain, out), nil
+}
+
+// unseal decrypts the archive encrypted by seal
+func unseal(secret, data []byte) ([]byte, error) {
+	if !isSealed(data) || len(data) < len(sealMagic)+saltSize {
+		return nil, perrs.Errorf("the metadata is not encrypted")
+	}
+	salt := data[len(sealMagic) : len(sealMagic)+saltSize]
+	gcm, err := newGCM(secret, salt)
+	if err != nil {
+		return nil, err
+	}
+	headerSize := len(sealMagic) + saltSize + gcm.NonceSize()
+	if len(data) < headerSize {
+		return nil, perrs.Errorf("the encrypted metadata is truncated")
+	}
+	header := data[:headerSize]
+	plain, err := gcm.Open(nil, header[len(sealMagic)+saltSize:], data[headerSize:], header)
+	if err != nil {
+		return nil, perrs.Errorf("decrypt metadata failed, the passphrase or keyfile may be wrong")
+	}
+	return plain, nil
+}
+
+func newGCM(secret, salt []byte) (cipher.AEAD, error) {
+	key, err := deriveKey(secret, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, perrs.AddStack(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	return gcm, perrs.AddStack(err)
+}
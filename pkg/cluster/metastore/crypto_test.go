@@ -0,0 +1,44 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metastore
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealUnseal(t *testing.T) {
+	plain := []byte("topology and keys")
+
+	sealed, err := seal([]byte("passphrase"), plain)
+	require.NoError(t, err)
+	require.True(t, isSealed(sealed))
+	require.NotContains(t, string(sealed), string(plain))
+
+	data, err := unseal([]byte("passphrase"), sealed)
+	require.NoError(t, err)
+	require.Equal(t, plain, data)
+
+	_, err = unseal([]byte("wrong"), sealed)
+	require.Error(t, err)
+
+	// the header is authenticated
+	sealed[len(sealMagic)] ^= 1
+	_, err = unseal([]byte("passphrase"), sealed)
+	require.Error(t, err)
+
+	_, err = unseal([]byte("passphrase"), plain)
+	require.Error(t, err)
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metastore
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	perrs "github.com/pingcap/errors"
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcdLockTTL is the TTL in seconds of the lease of locks, the lease is
+// kept alive while the process is running, so the lock is released
+// automatically if the process exits unexpectedly
+const etcdLockTTL = 30
+
+// etcdMaxValueSize is the default limit of the request size of etcd, the
+// archives larger than it are rejected by the server
+const etcdMaxValueSize = 1536 * 1024
+
+// etcdBackend stores the archives in etcd
+type etcdBackend struct {
+	client *clientv3.Client
+	prefix string
+	// the leases of the locks acquired by this process
+	leases map[string]clientv3.LeaseID
+}
+
+func newEtcdBackend(endpoints []string, prefix string, params url.Values) (*etcdBackend, error) {
+	cfg := clientv3.Config{
+		Endpoints:   endpoints,
+		DialTimeout: 5 * time.Second,
+		Username:    params.Get("user"),
+		Password:    params.Get("password"),
+	}
+	if cfg.Password == "" {
+		cfg.Password = os.Getenv("ETCDCTL_PASSWORD")
+	}
+	if params.Get("cacert") != "" || params.Get("cert") != "" {
+		tlsInfo := transport.TLSInfo{
+			TrustedCAFile: params.Get("cacert"),
+			CertFile:      params.Get("cert"),
+			KeyFile:       params.Get("key"),
+		}
+		tlsCfg, err := tlsInfo.ClientConfig()
+		if err != nil {
+			return nil, perrs.Annotate(err, "load tls config of etcd")
+		}
+		cfg.TLS = tlsCfg
+	}
+
+	client, err := clientv3.New(cfg)
+	if err != nil {
+		return nil, perrs.Annotatef(err, "connect to etcd %s", strings.Join(endpoints, ","))
+	}
+	return &etcdBackend{
+		client: client,
+		prefix: "/" + path.Join(prefix, "clusters"),
+		leases: make(map[string]clientv3.LeaseID),
+	}, nil
+}
+
+func (b *etcdBackend) key(name, suffix string) string {
+	return path.Join(b.prefix, name+suffix)
+}
+
+// Get implements the Backend interface
+func (b *etcdBackend) Get(ctx context.Context, name string) ([]byte, error) {
+	resp, err := b.client.Get(ctx, b.key(name, archiveSuffix))
+	if err != nil {
+		return nil, perrs.AddStack(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, ErrNotExist
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+// Put implements the Backend interface
+func (b *etcdBackend) Put(ctx context.Context, name string, data []byte) error {
+	if len(data) > etcdMaxValueSize {
+		return perrs.Errorf("the metadata archive is %d bytes, larger than the limit %d bytes of etcd", len(data), etcdMaxValueSize)
+	}
+	_, err := b.client.Put(ctx, b.key(name, archiveSuffix), string(data))
+	return perrs.AddStack(err)
+}
+
+// Delete implements the Backend interface
+func (b *etcdBackend) Delete(ctx context.Context, name string) error {
+	_, err := b.client.Delete(ctx, b.key(name, archiveSuffix))
+	return perrs.AddStack(err)
+}
+
+// List implements the Backend interface
+func (b *etcdBackend) List(ctx context.Context) ([]string, error) {
+	resp, err := b.client.Get(ctx, b.prefix+"/", clientv3.WithPrefix(), clientv3.WithKeysOnly())
+	if err != nil {
+		return nil, perrs.AddStack(err)
+	}
+	var names []string
+	for _, kv := range resp.Kvs {
+		name := strings.TrimPrefix(string(kv.Key), b.prefix+"/")
+		if strings.HasSuffix(name, archiveSuffix) {
+			names = append(names, strings.TrimSuffix(name, archiveSuffix))
+		}
+	}
+	return names, nil
+}
+
+// Lock implements the Backend interface
+func (b *etcdBackend) Lock(ctx context.Context, name string, info LockInfo) error {
+	data, err := json.Marshal(info)
+	if err != nil {
+		return perrs.AddStack(err)
+	}
+	lease, err := b.client.Grant(ctx, etcdLockTTL)
+	if err != nil {
+		return perrs.AddStack(err)
+	}
+
+	key := b.key(name, lockSuffix)
+	resp, err := b.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(data), clientv3.WithLease(lease.ID))).
+		Else(clientv3.OpGet(key)).
+		Commit()
+	if err != nil {
+		_, _ = b.client.Revoke(context.Background(), lease.ID)
+		return perrs.AddStack(err)
+	}
+	if !resp.Succeeded {
+		_, _ = b.client.Revoke(context.Background(), lease.ID)
+		var holder LockInfo
+		if kvs := resp.Responses[0].GetResponseRange().Kvs; len(kvs) > 0 {
+			_ = json.Unmarshal(kvs[0].Value, &holder)
+		}
+		return lockedError(name, holder)
+	}
+
+	// keep the lease alive until the lock is released or the client closed
+	ch, err := b.client.KeepAlive(context.Background(), lease.ID)
+	if err != nil {
+		return perrs.AddStack(err)
+	}
+	go func() {
+		for range ch {
+		}
+	}()
+	b.leases[name] = lease.ID
+	return nil
+}
+
+// Unlock implements the Backend interface
+func (b *etcdBackend) Unlock(ctx context.Context, name string) error {
+	lease, ok := b.leases[name]
+	if !ok {
+		return nil
+	}
+	delete(b.leases, name)
+	_, err := b.client.Revoke(ctx, lease)
+	return perrs.AddStack(err)
+}
+
+// Holder implements the Backend interface
+func (b *etcdBackend) Holder(ctx context.Context, name string) (*LockInfo, error) {
+	resp, err := b.client.Get(ctx, b.key(name, lockSuffix))
+	if err != nil {
+		return nil, perrs.AddStack(err)
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, nil
+	}
+	holder := &LockInfo{}
+	return holder, perrs.AddStack(json.Unmarshal(resp.Kvs[0].Value, holder))
+}
+
+// ForceUnlock implements the Backend interface
+func (b *etcdBackend) ForceUnlock(ctx context.Context, name string) error {
+	if lease, ok := b.leases[name]; ok {
+		delete(b.leases, name)
+		_, _ = b.client.Revoke(ctx, lease)
+	}
+	_, err := b.client.Delete(ctx, b.key(name, lockSuffix))
+	return perrs.AddStack(err)
+}
+
+// Close implements the Backend interface
+func (b *etcdBackend) Close() error {
+	return b.client.Close()
+}
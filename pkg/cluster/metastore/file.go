@@ -0,0 +1,126 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metastore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+const (
+	archiveSuffix = ".meta"
+	lockSuffix    = ".lock"
+	// lockGuardName is the file locked while acquiring the locks
+	lockGuardName = ".lock.guard"
+)
+
+// fileBackend stores the archives in a local directory, it is used to keep
+// the metadata encrypted at rest on the control machine
+type fileBackend struct {
+	dir string
+}
+
+func newFileBackend(dir string) (*fileBackend, error) {
+	if err := utils.MkdirAll(dir, 0700); err != nil {
+		return nil, perrs.Annotatef(err, "create meta backend directory %s", dir)
+	}
+	return &fileBackend{dir: dir}, nil
+}
+
+// Get implements the Backend interface
+func (b *fileBackend) Get(_ context.Context, name string) ([]byte, error) {
+	data, err := os.ReadFile(filepath.Join(b.dir, name+archiveSuffix))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotExist
+	}
+	return data, perrs.AddStack(err)
+}
+
+// Put implements the Backend interface
+func (b *fileBackend) Put(_ context.Context, name string, data []byte) error {
+	// write to a temporary file and rename it, so that the archive is never
+	// left half written
+	path := filepath.Join(b.dir, name+archiveSuffix)
+	if err := utils.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	return perrs.AddStack(os.Rename(path+".tmp", path))
+}
+
+// Delete implements the Backend interface
+func (b *fileBackend) Delete(_ context.Context, name string) error {
+	err := os.Remove(filepath.Join(b.dir, name+archiveSuffix))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return perrs.AddStack(err)
+}
+
+// List implements the Backend interface
+func (b *fileBackend) List(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, perrs.AddStack(err)
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), archiveSuffix) {
+			names = append(names, strings.TrimSuffix(e.Name(), archiveSuffix))
+		}
+	}
+	return names, nil
+}
+
+// Lock implements the Backend interface
+func (b *fileBackend) Lock(_ context.Context, name string, info LockInfo) error {
+	holder, err := spec.AcquireLockFile(filepath.Join(b.dir, name+lockSuffix), filepath.Join(b.dir, lockGuardName), info, 0600)
+	if err != nil {
+		return err
+	}
+	if holder != nil {
+		return lockedError(name, *holder)
+	}
+	return nil
+}
+
+// Unlock implements the Backend interface
+func (b *fileBackend) Unlock(ctx context.Context, name string) error {
+	return b.ForceUnlock(ctx, name)
+}
+
+// Holder implements the Backend interface
+func (b *fileBackend) Holder(_ context.Context, name string) (*LockInfo, error) {
+	return spec.ReadLockFile(filepath.Join(b.dir, name+lockSuffix))
+}
+
+// ForceUnlock implements the Backend interface
+func (b *fileBackend) ForceUnlock(_ context.Context, name string) error {
+	err := os.Remove(filepath.Join(b.dir, name+lockSuffix))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return perrs.AddStack(err)
+}
+
+// Close implements the Backend interface
+func (b *fileBackend) Close() error {
+	return nil
+}
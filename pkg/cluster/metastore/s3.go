@@ -0,0 +1,305 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metastore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/s3utils"
+	"github.com/minio/minio-go/v7/pkg/signer"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// s3Backend stores the archives in an S3-compatible object storage
+type s3Backend struct {
+	client *minio.Client
+	creds  *credentials.Credentials
+	bucket string
+	prefix string
+	region string
+	// the tokens of the locks acquired by this process
+	tokens map[string]string
+}
+
+func newS3Backend(bucket, prefix string, params url.Values) (*s3Backend, error) {
+	endpoint := params.Get("endpoint")
+	if endpoint == "" {
+		endpoint = "s3.amazonaws.com"
+	}
+	accessKey := params.Get("access-key")
+	if accessKey == "" {
+		accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	secretKey := params.Get("secret-access-key")
+	if secretKey == "" {
+		secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+
+	creds := credentials.NewStaticV4(accessKey, secretKey, "")
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  creds,
+		Secure: params.Get("insecure") != "true",
+		Region: params.Get("region"),
+	})
+	if err != nil {
+		return nil, perrs.Annotatef(err, "connect to s3 endpoint %s", endpoint)
+	}
+	return &s3Backend{
+		client: client,
+		creds:  creds,
+		bucket: bucket,
+		prefix: prefix,
+		region: params.Get("region"),
+		tokens: make(map[string]string),
+	}, nil
+}
+
+func (b *s3Backend) key(name, suffix string) string {
+	return path.Join(b.prefix, name+suffix)
+}
+
+func (b *s3Backend) get(ctx context.Context, key string) ([]byte, error) {
+	obj, err := b.client.GetObject(ctx, b.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, perrs.AddStack(err)
+	}
+	defer obj.Close()
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, ErrNotExist
+		}
+		return nil, perrs.AddStack(err)
+	}
+	return data, nil
+}
+
+func (b *s3Backend) put(ctx context.Context, key string, data []byte) error {
+	_, err := b.client.PutObject(ctx, b.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{})
+	return perrs.AddStack(err)
+}
+
+func (b *s3Backend) remove(ctx context.Context, key string) error {
+	err := b.client.RemoveObject(ctx, b.bucket, key, minio.RemoveObjectOptions{})
+	if err != nil && minio.ToErrorResponse(err).Code != "NoSuchKey" {
+		return perrs.AddStack(err)
+	}
+	return nil
+}
+
+// Get implements the Backend interface
+func (b *s3Backend) Get(ctx context.Context, name string) ([]byte, error) {
+	return b.get(ctx, b.key(name, archiveSuffix))
+}
+
+// Put implements the Backend interface
+func (b *s3Backend) Put(ctx context.Context, name string, data []byte) error {
+	return b.put(ctx, b.key(name, archiveSuffix), data)
+}
+
+// Delete implements the Backend interface
+func (b *s3Backend) Delete(ctx context.Context, name string) error {
+	return b.remove(ctx, b.key(name, archiveSuffix))
+}
+
+// List implements the Backend interface
+func (b *s3Backend) List(ctx context.Context) ([]string, error) {
+	prefix := ""
+	if b.prefix != "" {
+		prefix = b.prefix + "/"
+	}
+	var names []string
+	for obj := range b.client.ListObjects(ctx, b.bucket, minio.ListObjectsOptions{Prefix: prefix}) {
+		if obj.Err != nil {
+			return nil, perrs.AddStack(obj.Err)
+		}
+		name := strings.TrimPrefix(obj.Key, prefix)
+		if strings.HasSuffix(name, archiveSuffix) && !strings.Contains(name, "/") {
+			names = append(names, strings.TrimSuffix(name, archiveSuffix))
+		}
+	}
+	return names, nil
+}
+
+// s3Lock is the content of the lock object
+type s3Lock struct {
+	LockInfo
+	Token string `json:"token"`
+}
+
+// Lock implements the Backend interface. The lock object is created with a
+// conditional put, so only one of the operators acquiring the lock at the
+// same time succeeds; a stale lock is taken over only if it is not changed
+// since it was read.
+func (b *s3Backend) Lock(ctx context.Context, name string, info LockInfo) error {
+	key := b.key(name, lockSuffix)
+	condition, value := "If-None-Match", "*"
+	holder, etag, err := b.readLock(ctx, key)
+	switch {
+	case err == nil && !holder.IsStale():
+		return lockedError(name, holder.LockInfo)
+	case err == nil:
+		condition, value = "If-Match", etag
+	case err != ErrNotExist:
+		return err
+	}
+
+	lock := s3Lock{LockInfo: info, Token: utils.Base62Tag()}
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return perrs.AddStack(err)
+	}
+	if err := b.putIf(ctx, key, data, condition, value); err != nil {
+		if err != errPreconditionFailed {
+			return err
+		}
+		// acquired by others after it was read
+		if holder, _, err := b.readLock(ctx, key); err == nil {
+			return lockedError(name, holder.LockInfo)
+		}
+		return ErrLocked.New("Metadata of cluster '%s' is locked by others", name)
+	}
+	b.tokens[name] = lock.Token
+	return nil
+}
+
+// errPreconditionFailed is returned by putIf if the condition is not met
+var errPreconditionFailed = errors.New("precondition failed")
+
+// putIf puts the object if the condition header is met, minio-go quotes the
+// value of If-None-Match so the request is signed and sent here.
+func (b *s3Backend) putIf(ctx context.Context, key string, data []byte, condition, value string) error {
+	region := b.region
+	if region == "" {
+		var err error
+		if region, err = b.client.GetBucketLocation(ctx, b.bucket); err != nil {
+			return perrs.AddStack(err)
+		}
+	}
+	u := *b.client.EndpointURL()
+	u.Path = "/" + b.bucket + "/" + key
+	if s3utils.IsAmazonEndpoint(u) && s3utils.IsVirtualHostSupported(u, b.bucket) {
+		// path-style requests to the global endpoint are redirected to the
+		// region of the bucket
+		u.Host = fmt.Sprintf("%s.s3.%s.amazonaws.com", b.bucket, region)
+		u.Path = "/" + key
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return perrs.AddStack(err)
+	}
+	sum := sha256.Sum256(data)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+	req.Header.Set(condition, value)
+	creds, err := b.creds.Get()
+	if err != nil {
+		return perrs.AddStack(err)
+	}
+	if creds.AccessKeyID != "" {
+		req = signer.SignV4(*req, creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken, region)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return perrs.AddStack(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusPreconditionFailed, http.StatusConflict:
+		return errPreconditionFailed
+	default:
+		return perrs.Errorf("put s3 object %s: %s, %s", key, resp.Status, body)
+	}
+}
+
+// readLock returns the lock object and its ETag
+func (b *s3Backend) readLock(ctx context.Context, key string) (*s3Lock, string, error) {
+	obj, err := b.client.GetObject(ctx, b.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, "", perrs.AddStack(err)
+	}
+	defer obj.Close()
+	stat, err := obj.Stat()
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, "", ErrNotExist
+		}
+		return nil, "", perrs.AddStack(err)
+	}
+	data, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, "", perrs.AddStack(err)
+	}
+	lock := &s3Lock{}
+	return lock, stat.ETag, perrs.AddStack(json.Unmarshal(data, lock))
+}
+
+// Unlock implements the Backend interface
+func (b *s3Backend) Unlock(ctx context.Context, name string) error {
+	key := b.key(name, lockSuffix)
+	holder, _, err := b.readLock(ctx, key)
+	if err == ErrNotExist {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	// do not release the lock acquired by others
+	if holder.Token != b.tokens[name] {
+		return nil
+	}
+	delete(b.tokens, name)
+	return b.remove(ctx, key)
+}
+
+// Holder implements the Backend interface
+func (b *s3Backend) Holder(ctx context.Context, name string) (*LockInfo, error) {
+	holder, _, err := b.readLock(ctx, b.key(name, lockSuffix))
+	if err == ErrNotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &holder.LockInfo, nil
+}
+
+// ForceUnlock implements the Backend interface
+func (b *s3Backend) ForceUnlock(ctx context.Context, name string) error {
+	delete(b.tokens, name)
+	return b.remove(ctx, b.key(name, lockSuffix))
+}
+
+// Close implements the Backend interface
+func (b *s3Backend) Close() error {
+	return nil
+}
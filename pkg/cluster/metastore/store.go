@@ -0,0 +1,317 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metastore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/pingcap/tiup/pkg/localdata"
+	"github.com/pingcap/tiup/pkg/tui"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// metaFileName is the file indicates the cluster exists in the working copy
+const metaFileName = "meta.yaml"
+
+// Store synchronizes the local working copies of cluster metadata with the
+// backend. The working copies are checked out into a directory owned by the
+// process, so that the commands running concurrently do not conflict.
+type Store struct {
+	backend Backend
+	secret  []byte
+	// local is the directory of the clusters stored as plain files locally,
+	// they are uploaded to the backend when they are committed first time
+	local   string
+	workDir string
+	entries []*entry
+	// kept is set if a working copy fails to be uploaded and is kept in
+	// the working directory
+	kept bool
+}
+
+// entry is a cluster checked out from the backend
+type entry struct {
+	name     string
+	locked   bool
+	imported bool
+}
+
+// NewStore creates a Store, local is the directory of the clusters stored
+// as plain files locally, the working directory of the process is created
+// beside it. The archives are encrypted with secret if it is not empty.
+func NewStore(backend Backend, secret []byte, local string) (*Store, error) {
+	if err := utils.MkdirAll(filepath.Dir(local), 0755); err != nil {
+		return nil, perrs.AddStack(err)
+	}
+	workDir, err := os.MkdirTemp(filepath.Dir(local), fmt.Sprintf(".%s-%d-", filepath.Base(local), os.Getpid()))
+	if err != nil {
+		return nil, perrs.Annotate(err, "create working directory of cluster metadata")
+	}
+	return &Store{
+		backend: backend,
+		secret:  secret,
+		local:   local,
+		workDir: workDir,
+	}, nil
+}
+
+// FromEnv creates the Store configured by the environment variables, nil is
+// returned if no backend is configured and the metadata is stored as plain
+// files in the local directory.
+func FromEnv(local string) (*Store, error) {
+	rawURL := os.Getenv(localdata.EnvNameClusterMetaBackend)
+	if rawURL == "" {
+		return nil, nil
+	}
+	backend, err := ParseBackend(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var secret []byte
+	if keyFile := os.Getenv(localdata.EnvNameClusterMetaKeyFile); keyFile != "" {
+		if secret, err = os.ReadFile(keyFile); err != nil {
+			return nil, perrs.Annotatef(err, "read meta keyfile %s", keyFile)
+		}
+	} else if passphrase := os.Getenv(localdata.EnvNameClusterMetaPassphrase); passphrase != "" {
+		secret = []byte(passphrase)
+	}
+
+	// keeping the metadata on local disk is meaningful only if it is encrypted
+	if _, ok := backend.(*fileBackend); ok && len(secret) == 0 {
+		if secret = []byte(tui.PromptForPassword("Passphrase of the cluster metadata: ")); len(secret) == 0 {
+			return nil, perrs.Errorf("the file meta backend requires %s or %s to be set",
+				localdata.EnvNameClusterMetaKeyFile, localdata.EnvNameClusterMetaPassphrase)
+		}
+	}
+	return NewStore(backend, secret, local)
+}
+
+// WorkDir returns the directory the clusters are checked out into, it is
+// used as the base directory of the cluster metadata
+func (s *Store) WorkDir() string {
+	return s.workDir
+}
+
+// Checkout fetches the metadata of the cluster from the backend into the
+// working directory. If lock is true, the cluster is locked until Commit is
+// called, otherwise the working copy is read only and dropped by Commit.
+//
+// If the cluster is not in the backend, it is copied from the local
+// directory if it exists there, so that the metadata of existing clusters
+// is uploaded at the first Commit.
+func (s *Store) Checkout(ctx context.Context, name string, lock bool, info LockInfo) error {
+	if lock {
+		if err := s.backend.Lock(ctx, name, info); err != nil {
+			return err
+		}
+	}
+	e := &entry{name: name, locked: lock}
+	err := s.checkout(ctx, e)
+	if err != nil {
+		_ = os.RemoveAll(filepath.Join(s.workDir, name))
+		if lock {
+			_ = s.backend.Unlock(ctx, name)
+		}
+		return err
+	}
+	s.entries = append(s.entries, e)
+	return nil
+}
+
+func (s *Store) checkout(ctx context.Context, e *entry) error {
+	dir := filepath.Join(s.workDir, e.name)
+	data, err := s.backend.Get(ctx, e.name)
+	switch {
+	case err == ErrNotExist:
+		if err := s.importLocal(e); err != nil {
+			return err
+		}
+		return s.linkPatches(e)
+	case err != nil:
+		return perrs.Annotatef(err, "fetch metadata of cluster %s", e.name)
+	}
+
+	switch {
+	case isSealed(data) && len(s.secret) == 0:
+		return perrs.Errorf("metadata of cluster %s is encrypted, please set %s or %s",
+			e.name, localdata.EnvNameClusterMetaKeyFile, localdata.EnvNameClusterMetaPassphrase)
+	case isSealed(data):
+		if data, err = unseal(s.secret, data); err != nil {
+			return err
+		}
+	case len(s.secret) > 0:
+		// it may be replaced by someone with access to the backend
+		return perrs.Errorf("metadata of cluster %s in meta backend is not encrypted", e.name)
+	}
+	if err := extract(bytes.NewReader(data), dir); err != nil {
+		return perrs.Annotatef(err, "extract metadata of cluster %s", e.name)
+	}
+	return s.linkPatches(e)
+}
+
+// linkPatches links the patch directory of the working copy to the one in
+// the local directory, as the patches are not stored in the backend
+func (s *Store) linkPatches(e *entry) error {
+	if utils.IsNotExist(filepath.Join(s.workDir, e.name, metaFileName)) {
+		return nil
+	}
+	patchDir := filepath.Join(s.local, e.name, spec.PatchDirName)
+	if err := utils.MkdirAll(patchDir, 0755); err != nil {
+		return perrs.AddStack(err)
+	}
+	return perrs.AddStack(os.Symlink(patchDir, filepath.Join(s.workDir, e.name, spec.PatchDirName)))
+}
+
+// importLocal copies the cluster in the local directory into the working
+// directory, if it exists
+func (s *Store) importLocal(e *entry) error {
+	src := filepath.Join(s.local, e.name)
+	if utils.IsNotExist(filepath.Join(src, metaFileName)) {
+		return nil
+	}
+	var buf bytes.Buffer
+	if err := archive(&buf, src); err != nil {
+		return perrs.Annotatef(err, "read local metadata of cluster %s", e.name)
+	}
+	if err := extract(&buf, filepath.Join(s.workDir, e.name)); err != nil {
+		return perrs.Annotatef(err, "copy local metadata of cluster %s", e.name)
+	}
+	e.imported = true
+	return nil
+}
+
+// CheckoutAll fetches the metadata of all the clusters in the backend and
+// the local directory as read only working copies
+func (s *Store) CheckoutAll(ctx context.Context) error {
+	names, err := s.backend.List(ctx)
+	if err != nil {
+		return perrs.Annotate(err, "list clusters in meta backend")
+	}
+	if dirs, err := os.ReadDir(s.local); err == nil {
+		for _, d := range dirs {
+			if d.IsDir() && !slices.Contains(names, d.Name()) {
+				names = append(names, d.Name())
+			}
+		}
+	}
+	for _, name := range names {
+		if err := s.Checkout(ctx, name, false, LockInfo{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit uploads the working copies of the locked clusters to the backend,
+// or removes them from the backend if they are destroyed, then the working
+// copies are removed and the locks are released. A working copy is kept if
+// it fails to be uploaded.
+func (s *Store) Commit(ctx context.Context) error {
+	var firstErr error
+	for _, e := range s.entries {
+		if err := s.commit(ctx, e); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	s.entries = nil
+	return firstErr
+}
+
+func (s *Store) commit(ctx context.Context, e *entry) error {
+	dir := filepath.Join(s.workDir, e.name)
+	if !e.locked {
+		return perrs.AddStack(os.RemoveAll(dir))
+	}
+	defer s.backend.Unlock(ctx, e.name)
+
+	if utils.IsNotExist(filepath.Join(dir, metaFileName)) {
+		if err := s.backend.Delete(ctx, e.name); err != nil {
+			return perrs.Annotatef(err, "remove metadata of cluster %s from meta backend", e.name)
+		}
+		if err := os.RemoveAll(filepath.Join(s.local, e.name)); err != nil {
+			return perrs.AddStack(err)
+		}
+		return perrs.AddStack(os.RemoveAll(dir))
+	}
+
+	var buf bytes.Buffer
+	if err := archive(&buf, dir); err != nil {
+		return perrs.Annotatef(err, "archive metadata of cluster %s", e.name)
+	}
+	data := buf.Bytes()
+	if len(s.secret) > 0 {
+		var err error
+		if data, err = seal(s.secret, data); err != nil {
+			return err
+		}
+	}
+	if err := s.backend.Put(ctx, e.name, data); err != nil {
+		s.kept = true
+		return perrs.Annotatef(err, "save metadata of cluster %s to meta backend, it is kept in %s", e.name, dir)
+	}
+	return s.removeLocal(e)
+}
+
+// removeLocal removes the working copy of the cluster, and the copy in the
+// local directory except the patches if it is imported from there
+func (s *Store) removeLocal(e *entry) error {
+	if e.imported {
+		dir := filepath.Join(s.local, e.name)
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			return perrs.AddStack(err)
+		}
+		for _, f := range files {
+			if f.Name() == spec.PatchDirName {
+				continue
+			}
+			if err := os.RemoveAll(filepath.Join(dir, f.Name())); err != nil {
+				return perrs.AddStack(err)
+			}
+		}
+	}
+	return perrs.AddStack(os.RemoveAll(filepath.Join(s.workDir, e.name)))
+}
+
+// Holder returns who is holding the lock of the cluster in the backend, nil
+// is returned if it is not locked
+func (s *Store) Holder(name string) (*LockInfo, error) {
+	return s.backend.Holder(context.Background(), name)
+}
+
+// ForceUnlock releases the lock of the cluster in the backend no matter who
+// holds it
+func (s *Store) ForceUnlock(name string) error {
+	return s.backend.ForceUnlock(context.Background(), name)
+}
+
+// Close removes the working directory unless a working copy is kept in it,
+// and releases the resources of the backend
+func (s *Store) Close() error {
+	if !s.kept {
+		if err := os.RemoveAll(s.workDir); err != nil {
+			_ = s.backend.Close()
+			return perrs.AddStack(err)
+		}
+	}
+	return s.backend.Close()
+}
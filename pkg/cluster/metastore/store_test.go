@@ -0,0 +1,236 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metastore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackend(t *testing.T) {
+	dir := t.TempDir()
+	b, err := ParseBackend("file://" + dir)
+	require.NoError(t, err)
+	require.IsType(t, &fileBackend{}, b)
+
+	for _, u := range []string{"file://", "s3:///prefix", "etcd:///prefix", "ftp://host/dir",
+		"etcd://127.0.0.1:2379/prefix?cacert=" + filepath.Join(dir, "ca.crt")} {
+		_, err := ParseBackend(u)
+		require.Error(t, err, u)
+	}
+}
+
+func TestStoreCheckoutCommit(t *testing.T) {
+	ctx := context.Background()
+	backend, err := newFileBackend(t.TempDir())
+	require.NoError(t, err)
+	local := filepath.Join(t.TempDir(), "clusters")
+	localPath := func(cluster string, subpath ...string) string {
+		return filepath.Join(append([]string{local, cluster}, subpath...)...)
+	}
+	workPath := func(s *Store, cluster string, subpath ...string) string {
+		return filepath.Join(append([]string{s.WorkDir(), cluster}, subpath...)...)
+	}
+	store, err := NewStore(backend, []byte("secret"), local)
+	require.NoError(t, err)
+	info := spec.NewOperationLock("tiup-cluster deploy test")
+
+	// an existing local cluster is uploaded at the first commit
+	require.NoError(t, os.MkdirAll(localPath("test", "ssh"), 0700))
+	require.NoError(t, os.WriteFile(localPath("test", metaFileName), []byte("user: tidb\n"), 0644))
+	require.NoError(t, os.WriteFile(localPath("test", "ssh", "id_rsa"), []byte("private key"), 0600))
+	require.NoError(t, store.Checkout(ctx, "test", true, info))
+	require.FileExists(t, workPath(store, "test", metaFileName))
+
+	// the cluster is locked by others
+	other, err := NewStore(backend, []byte("secret"), local)
+	require.NoError(t, err)
+	require.NotEqual(t, store.WorkDir(), other.WorkDir())
+	err = other.Checkout(ctx, "test", true, spec.NewOperationLock("tiup-cluster upgrade test"))
+	require.True(t, errorx.IsOfType(err, ErrLocked))
+	require.Contains(t, err.Error(), "tiup-cluster deploy test")
+
+	require.NoError(t, store.Commit(ctx))
+	require.NoDirExists(t, workPath(store, "test"))
+	// the patches are kept in the local directory
+	require.NoFileExists(t, localPath("test", metaFileName))
+	require.DirExists(t, localPath("test", "patch"))
+	names, err := backend.List(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"test"}, names)
+	data, err := backend.Get(ctx, "test")
+	require.NoError(t, err)
+	require.True(t, isSealed(data))
+
+	// checkout the cluster with the right secret, read only working copies
+	// of other processes do not conflict
+	require.NoError(t, store.Checkout(ctx, "test", false, LockInfo{}))
+	require.NoError(t, other.Checkout(ctx, "test", true, info))
+	key, err := os.ReadFile(workPath(other, "test", "ssh", "id_rsa"))
+	require.NoError(t, err)
+	require.Equal(t, "private key", string(key))
+	fi, err := os.Stat(workPath(other, "test", "ssh", "id_rsa"))
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+	link, err := os.Readlink(workPath(other, "test", "patch"))
+	require.NoError(t, err)
+	require.Equal(t, localPath("test", "patch"), link)
+	require.NoError(t, store.Commit(ctx))
+
+	// the cluster is destroyed
+	require.NoError(t, os.RemoveAll(workPath(other, "test")))
+	require.NoError(t, other.Commit(ctx))
+	_, err = backend.Get(ctx, "test")
+	require.Equal(t, ErrNotExist, err)
+	require.NoDirExists(t, localPath("test"))
+
+	// read only checkouts are dropped without uploading
+	require.NoError(t, backend.Put(ctx, "ro", data))
+	require.NoError(t, store.CheckoutAll(ctx))
+	require.FileExists(t, workPath(store, "ro", metaFileName))
+	require.NoError(t, os.WriteFile(workPath(store, "ro", metaFileName), []byte("changed"), 0644))
+	require.NoError(t, store.Commit(ctx))
+	require.NoDirExists(t, workPath(store, "ro"))
+	stored, err := backend.Get(ctx, "ro")
+	require.NoError(t, err)
+	require.Equal(t, data, stored)
+
+	// wrong secret
+	wrong, err := NewStore(backend, []byte("wrong"), local)
+	require.NoError(t, err)
+	require.Error(t, wrong.Checkout(ctx, "ro", true, info))
+	require.NoDirExists(t, workPath(wrong, "ro"))
+	require.NoError(t, store.Checkout(ctx, "ro", true, info), "the lock is released on failure")
+
+	// plain archives are rejected if the secret is set
+	var plain bytes.Buffer
+	require.NoError(t, archive(&plain, workPath(store, "ro")))
+	require.NoError(t, backend.Put(ctx, "plain", plain.Bytes()))
+	err = wrong.Checkout(ctx, "plain", false, LockInfo{})
+	require.ErrorContains(t, err, "not encrypted")
+	require.NoDirExists(t, workPath(wrong, "plain"))
+
+	// the working directories are removed when closed
+	for _, s := range []*Store{store, other, wrong} {
+		require.NoError(t, s.Close())
+		require.NoDirExists(t, s.WorkDir())
+	}
+}
+
+// fakeS3 is an in-memory S3 server supporting the conditional puts
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	etag    int
+	etags   map[string]string
+}
+
+func (s *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := r.URL.Path
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		data, ok := s.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			return
+		}
+		w.Header().Set("ETag", `"`+s.etags[key]+`"`)
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		_, _ = w.Write(data)
+	case http.MethodPut:
+		_, exist := s.objects[key]
+		if (r.Header.Get("If-None-Match") == "*" && exist) ||
+			(r.Header.Get("If-Match") != "" && strings.Trim(r.Header.Get("If-Match"), `"`) != s.etags[key]) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		s.objects[key], _ = io.ReadAll(r.Body)
+		s.etag++
+		s.etags[key] = strconv.Itoa(s.etag)
+		w.Header().Set("ETag", `"`+s.etags[key]+`"`)
+	case http.MethodDelete:
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Lock(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeS3{objects: make(map[string][]byte), etags: make(map[string]string)}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	newBackend := func() *s3Backend {
+		b, err := newS3Backend("bucket", "prefix", url.Values{
+			"endpoint":          {strings.TrimPrefix(server.URL, "http://")},
+			"region":            {"us-east-1"},
+			"insecure":          {"true"},
+			"access-key":        {"access"},
+			"secret-access-key": {"secret"},
+		})
+		require.NoError(t, err)
+		return b
+	}
+	b1, b2 := newBackend(), newBackend()
+
+	info := spec.NewOperationLock("tiup-cluster deploy test")
+	require.NoError(t, b1.Lock(ctx, "test", info))
+	err := b2.Lock(ctx, "test", spec.NewOperationLock("tiup-cluster upgrade test"))
+	require.True(t, errorx.IsOfType(err, ErrLocked))
+	require.Contains(t, err.Error(), "tiup-cluster deploy test")
+
+	// the lock is not released by others
+	require.NoError(t, b2.Unlock(ctx, "test"))
+	holder, err := b1.Holder(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, info.Command, holder.Command)
+	require.NoError(t, b1.Unlock(ctx, "test"))
+	holder, err = b1.Holder(ctx, "test")
+	require.NoError(t, err)
+	require.Nil(t, holder)
+
+	// the stale lock is taken over
+	stale := spec.NewOperationLock("tiup-cluster deploy test")
+	stale.PID = 1 << 30
+	require.NoError(t, b1.Lock(ctx, "test", stale))
+	require.NoError(t, b2.Lock(ctx, "test", info))
+	holder, err = b1.Holder(ctx, "test")
+	require.NoError(t, err)
+	require.Equal(t, os.Getpid(), holder.PID)
+
+	// the condition is checked by the server
+	err = b1.putIf(ctx, b1.key("test", lockSuffix), []byte("{}"), "If-None-Match", "*")
+	require.Equal(t, errPreconditionFailed, err)
+}
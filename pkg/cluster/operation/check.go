@@ -27,7 +27,9 @@ import (
 	"github.com/pingcap/tiup/pkg/cluster/ctxt"
 	"github.com/pingcap/tiup/pkg/cluster/module"
 	"github.com/pingcap/tiup/pkg/cluster/spec"
+	system "github.com/pingcap/tiup/pkg/cluster/template/systemd"
 	"github.com/pingcap/tiup/pkg/insight"
+	"github.com/pingcap/tiup/pkg/meta"
 	"go.uber.org/zap"
 )
 
@@ -67,6 +69,7 @@ var (
 	CheckNameDirPermission = "permission"
 	CheckNameDirExist      = "exist"
 	CheckNameTimeZone      = "timezone"
+	CheckNameSystemd       = "systemd"
 )
 
 // CheckResult is the result of a check
@@ -1012,6 +1015,10 @@ func CheckTimeZone(ctx context.Context, topo *spec.Specification, host string, r
 	}
 
 	if len(topo.PDServers) < 1 {
+		// nothing to compare with for a monitoring only topology
+		if topo.IsMonitoringOnly() {
+			return nil
+		}
 		return append(results, &CheckResult{
 			Name: CheckNameTimeZone,
 			Err:  fmt.Errorf("no pd found"),
@@ -1072,3 +1079,71 @@ func compareVersion(v1, v2 string) int {
 	}
 	return 0
 }
+
+// CheckSystemdDirectives checks if the systemd on the host supports all the
+// directives rendered from the resource_control of instances on it
+func CheckSystemdDirectives(ctx context.Context, e ctxt.Executor, host string, topo *spec.Specification) []*CheckResult {
+	stdout, stderr, err := e.Execute(ctx, "systemctl --version", false)
+	if err != nil {
+		return []*CheckResult{{
+			Name: CheckNameSystemd,
+			Err:  fmt.Errorf("failed to get systemd version: %s", strings.TrimSpace(string(stderr))),
+		}}
+	}
+	version, err := system.ParseVersion(string(stdout))
+	if err != nil {
+		return []*CheckResult{{Name: CheckNameSystemd, Err: err}}
+	}
+	return checkSystemdDirectives(version, host, topo)
+}
+
+func checkSystemdDirectives(version int, host string, topo *spec.Specification) []*CheckResult {
+	// the directive and the instances using it
+	unsupported := make(map[string][]string)
+	required := make(map[string]system.UnsupportedDirective)
+	check := func(id string, rc meta.ResourceControl) {
+		cfg := system.NewConfig("", "", "").WithResourceControl(rc)
+		for d, v := range cfg.UnsupportedDirectives(version) {
+			unsupported[d] = append(unsupported[d], id)
+			required[d] = v
+		}
+	}
+
+	monitored := false
+	topo.IterInstance(func(inst spec.Instance) {
+		if inst.GetManageHost() != host {
+			return
+		}
+		check(inst.ID(), spec.MergeResourceControl(topo.GlobalOptions.ResourceControl, inst.ResourceControl()))
+		monitored = monitored || !inst.IgnoreMonitorAgent()
+	})
+	if monitored {
+		check("monitored", spec.MergeResourceControl(topo.GlobalOptions.ResourceControl, topo.MonitoredOptions.ResourceControl))
+	}
+
+	if len(unsupported) == 0 {
+		return []*CheckResult{{
+			Name: CheckNameSystemd,
+			Msg:  fmt.Sprintf("systemd %d supports all the directives of resource_control", version),
+		}}
+	}
+
+	directives := make([]string, 0, len(unsupported))
+	for d := range unsupported {
+		directives = append(directives, d)
+	}
+	sort.Strings(directives)
+
+	var results []*CheckResult
+	for _, d := range directives {
+		// the legacy directives are ignored by systemd, which does not
+		// break the existing clusters
+		results = append(results, &CheckResult{
+			Name: CheckNameSystemd,
+			Err: fmt.Errorf("%s used by %s requires systemd %d or later, but the version is %d",
+				d, strings.Join(unsupported[d], ","), required[d].Version, version),
+			Warn: required[d].Legacy,
+		})
+	}
+	return results
+}
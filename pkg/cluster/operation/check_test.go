@@ -17,7 +17,10 @@ import (
 	"testing"
 
 	"github.com/AstroProfundis/sysinfo"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 // TestCompareVersion verifies the custom version comparison logic.
@@ -211,3 +214,49 @@ func TestCheckOSInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckSystemdDirectives(t *testing.T) {
+	topo := &spec.Specification{}
+	err := yaml.Unmarshal([]byte(`
+global:
+  resource_control:
+    memory_high: 30G
+tidb_servers:
+  - host: 172.16.5.1
+    resource_control:
+      allowed_cpus: 0-7
+  - host: 172.16.5.2
+tikv_servers:
+  - host: 172.16.5.1
+    resource_control:
+      allowed_cpus: 8-15
+`), topo)
+	require.NoError(t, err)
+
+	results := checkSystemdDirectives(252, "172.16.5.1", topo)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+
+	results = checkSystemdDirectives(239, "172.16.5.2", topo)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+
+	results = checkSystemdDirectives(239, "172.16.5.1", topo)
+	require.Len(t, results, 1)
+	require.ErrorContains(t, results[0].Err, "AllowedCPUs used by")
+	require.ErrorContains(t, results[0].Err, "requires systemd 244 or later, but the version is 239")
+
+	results = checkSystemdDirectives(219, "172.16.5.2", topo)
+	require.Len(t, results, 1)
+	require.ErrorContains(t, results[0].Err, "MemoryHigh used by 172.16.5.2:4000,monitored requires systemd 231")
+	require.False(t, results[0].Warn)
+
+	// the directives supported before the versions are checked only warn
+	topo.TiKVServers[0].ResourceControl.IOReadBandwidthMax = "/dev/sda 100M"
+	results = checkSystemdDirectives(229, "172.16.5.1", topo)
+	require.Len(t, results, 3)
+	require.ErrorContains(t, results[1].Err, "IOReadBandwidthMax used by 172.16.5.1:20160 requires systemd 230")
+	require.True(t, results[1].Warn)
+	require.ErrorContains(t, results[2].Err, "MemoryHigh")
+	require.False(t, results[2].Warn)
+}
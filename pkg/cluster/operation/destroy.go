@@ -17,6 +17,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -503,6 +504,17 @@ func DestroyClusterTombstone(
 		return nil, err
 	}
 
+	// a store which is offline in the topology but does not exist in PD
+	// anymore has been removed and purged already, destroy it as a tombstone
+	isTombstone := func(id string) (bool, error) {
+		tombstone, err := pdClient.IsTombStone(id)
+		if errors.Is(err, api.ErrNoStore) {
+			logger.Warnf("Store %s does not exist in PD anymore, destroy it as tombstone", id)
+			return true, nil
+		}
+		return tombstone, err
+	}
+
 	filterID := func(instance []spec.Instance, id string) (res []spec.Instance) {
 		for _, ins := range instance {
 			if ins.ID() == id {
@@ -538,7 +550,7 @@ func DestroyClusterTombstone(
 
 		id := utils.JoinHostPort(s.Host, s.Port)
 
-		tombstone, err := pdClient.IsTombStone(id)
+		tombstone, err := isTombstone(id)
 		if err != nil {
 			return nil, err
 		}
@@ -568,7 +580,7 @@ func DestroyClusterTombstone(
 
 		id := utils.JoinHostPort(s.Host, s.FlashServicePort)
 
-		tombstone, err := pdClient.IsTombStone(id)
+		tombstone, err := isTombstone(id)
 		if err != nil {
 			return nil, err
 		}
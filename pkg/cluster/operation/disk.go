@@ -0,0 +1,86 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+)
+
+// DiskUsage is the usage of the filesystem a directory is located on
+type DiskUsage struct {
+	Filesystem string `json:"filesystem"`
+	MountPoint string `json:"mount_point"`
+	Size       uint64 `json:"size"`
+	Used       uint64 `json:"used"`
+	Available  uint64 `json:"available"`
+}
+
+// UsedRatio returns the used percentage of the filesystem in range [0, 1]
+func (d DiskUsage) UsedRatio() float64 {
+	if d.Size == 0 {
+		return 0
+	}
+	return float64(d.Size-d.Available) / float64(d.Size)
+}
+
+// GetDiskUsage returns the usage of the filesystem that path is located on
+/*
+[tidb@ip-172-16-5-70 deploy]$ df -Pk /data1/deploy/data
+Filesystem     1024-blocks     Used Available Capacity Mounted on
+/dev/nvme1n1    1967841040 98373096 1769440492       6% /data1
+*/
+func GetDiskUsage(ctx context.Context, e ctxt.Executor, path string) (*DiskUsage, error) {
+	stdout, stderr, err := e.Execute(ctx, fmt.Sprintf("df -Pk %s", path), false)
+	if err != nil {
+		return nil, errors.Annotatef(err, "stderr: %s", string(stderr))
+	}
+	return parseDfOutput(string(stdout))
+}
+
+func parseDfOutput(out string) (*DiskUsage, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 2 {
+		return nil, errors.Errorf("unexpected output of df: %s", out)
+	}
+
+	// the mount point may contain spaces, so only the first 5 fields are
+	// split and the rest of the line is treated as the mount point
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 6 {
+		return nil, errors.Errorf("unexpected output of df: %s", out)
+	}
+
+	var blocks [3]uint64
+	for i := range blocks {
+		v, err := strconv.ParseUint(fields[i+1], 10, 64)
+		if err != nil {
+			return nil, errors.Annotatef(err, "unexpected output of df: %s", out)
+		}
+		blocks[i] = v * 1024
+	}
+
+	return &DiskUsage{
+		Filesystem: fields[0],
+		MountPoint: strings.Join(fields[5:], " "),
+		Size:       blocks[0],
+		Used:       blocks[1],
+		Available:  blocks[2],
+	}, nil
+}
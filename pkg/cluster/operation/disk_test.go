@@ -0,0 +1,45 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDfOutput(t *testing.T) {
+	out := `Filesystem     1024-blocks     Used Available Capacity Mounted on
+/dev/nvme1n1        1000000   600000    400000      60% /data1
+`
+	du, err := parseDfOutput(out)
+	require.NoError(t, err)
+	require.Equal(t, "/dev/nvme1n1", du.Filesystem)
+	require.Equal(t, "/data1", du.MountPoint)
+	require.Equal(t, uint64(1000000*1024), du.Size)
+	require.Equal(t, uint64(600000*1024), du.Used)
+	require.Equal(t, uint64(400000*1024), du.Available)
+	require.InDelta(t, 0.6, du.UsedRatio(), 0.0001)
+
+	du, err = parseDfOutput(`Filesystem 1024-blocks Used Available Capacity Mounted on
+tmpfs 100 10 90 10% /mnt/my data`)
+	require.NoError(t, err)
+	require.Equal(t, "/mnt/my data", du.MountPoint)
+
+	_, err = parseDfOutput("df: /not/exist: No such file or directory")
+	require.Error(t, err)
+	_, err = parseDfOutput(`Filesystem 1024-blocks Used Available Capacity Mounted on
+tmpfs x 10 90 10% /mnt`)
+	require.Error(t, err)
+}
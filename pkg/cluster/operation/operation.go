@@ -57,6 +57,12 @@ type Options struct {
 	RetainDataRoles []string
 	RetainDataNodes []string
 
+	// Archive data and log directories to the target before destroying
+	ArchiveTo string
+	// The SSH port and key to connect the host of the archive target
+	ArchiveSSHPort int
+	ArchiveSSHKey  string
+
 	DisplayMode string // the output format
 	Operation   Operation
 }
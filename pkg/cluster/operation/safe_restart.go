@@ -0,0 +1,194 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/api"
+	"github.com/pingcap/tiup/pkg/cluster/spec"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/pingcap/tiup/pkg/set"
+)
+
+// the states of unhealthy regions checked by PD
+const (
+	RegionStateMissPeer    = "miss-peer"
+	RegionStateDownPeer    = "down-peer"
+	RegionStatePendingPeer = "pending-peer"
+)
+
+// RegionHealthGate is the condition of regions to be satisfied before and
+// after restarting a store in a safe restart, so that the replica loss of two
+// consecutive restarts never stack up.
+type RegionHealthGate struct {
+	MaxMissPeer    int           // max number of regions missing peers
+	MaxDownPeer    int           // max number of regions with down peers
+	MaxPendingPeer int           // max number of regions with pending peers
+	MaxWait        time.Duration // max time to wait for the regions to be healthy
+	Interval       time.Duration // interval to check the regions
+}
+
+// violations returns the descriptions of the region states exceeding the
+// thresholds
+func (g RegionHealthGate) violations(counts map[string]int) []string {
+	var result []string
+	for _, limit := range []struct {
+		state string
+		max   int
+	}{
+		{RegionStateMissPeer, g.MaxMissPeer},
+		{RegionStateDownPeer, g.MaxDownPeer},
+		{RegionStatePendingPeer, g.MaxPendingPeer},
+	} {
+		if counts[limit.state] > limit.max {
+			result = append(result, fmt.Sprintf("%d %s regions (max %d)", counts[limit.state], limit.state, limit.max))
+		}
+	}
+	return result
+}
+
+// WaitRegionsHealthy waits until the unhealthy regions reported by PD are
+// within the thresholds of the gate
+func WaitRegionsHealthy(ctx context.Context, pdClient *api.PDClient, gate RegionHealthGate) error {
+	logger := ctx.Value(logprinter.ContextKeyLogger).(*logprinter.Logger)
+	interval := gate.Interval
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(gate.MaxWait)
+
+	for {
+		counts := make(map[string]int)
+		var err error
+		for _, state := range []string{RegionStateMissPeer, RegionStateDownPeer, RegionStatePendingPeer} {
+			var info *api.RegionsInfo
+			if info, err = pdClient.CheckRegion(state); err != nil {
+				break
+			}
+			counts[state] = info.Count
+		}
+
+		var reason string
+		if err != nil {
+			reason = fmt.Sprintf("failed to check regions: %v", err)
+		} else {
+			violations := gate.violations(counts)
+			if len(violations) == 0 {
+				return nil
+			}
+			reason = strings.Join(violations, ", ")
+		}
+
+		if time.Now().After(deadline) {
+			return perrs.Errorf("regions are not healthy after waiting for %s: %s", gate.MaxWait, reason)
+		}
+		logger.Infof("\t  Waiting for regions to be healthy: %s", reason)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// SafeRestart restarts the instances one by one in the order of upgrading,
+// the leaders of TiKV stores are evicted before restarting and recovered
+// after it, and it waits for the regions to be healthy before restarting
+// each store and after the last one.
+func SafeRestart(
+	ctx context.Context,
+	topo *spec.Specification,
+	options Options,
+	tlsCfg *tls.Config,
+	currentVersion string,
+	gate RegionHealthGate,
+) error {
+	logger := ctx.Value(logprinter.ContextKeyLogger).(*logprinter.Logger)
+	roleFilter := set.NewStringSet(options.Roles...)
+	nodeFilter := set.NewStringSet(options.Nodes...)
+	components := FilterComponent(topo.ComponentsByUpdateOrder(currentVersion), roleFilter)
+	systemdMode := string(topo.BaseTopo().GlobalOptions.SystemdMode)
+
+	var pdEndpoints []string
+	if forcePDEndpoints := os.Getenv(EnvNamePDEndpointOverwrite); forcePDEndpoints != "" {
+		pdEndpoints = strings.Split(forcePDEndpoints, ",")
+		logger.Warnf("%s is set, using %s as PD endpoints", EnvNamePDEndpointOverwrite, pdEndpoints)
+	} else {
+		pdEndpoints = topo.GetPDListWithManageHost()
+	}
+	pdClient := api.NewPDClient(ctx, pdEndpoints, 10*time.Second, tlsCfg)
+
+	waitRegions := func() error {
+		err := WaitRegionsHealthy(ctx, pdClient, gate)
+		if err != nil && options.Force {
+			logger.Warnf("Ignore waiting for regions to be healthy: %v", err)
+			return nil
+		}
+		return err
+	}
+
+	for _, component := range components {
+		instances := FilterInstance(component.Instances(), nodeFilter)
+		if len(instances) == 0 {
+			continue
+		}
+		logger.Infof("Restarting component %s one by one", component.Name())
+
+		isStore := component.Name() == spec.ComponentTiKV || component.Name() == spec.ComponentTiFlash
+		for _, ins := range instances {
+			if isStore {
+				if err := waitRegions(); err != nil {
+					return perrs.Annotatef(err, "refuse to restart %s", ins.ID())
+				}
+			}
+			if err := restartRollingInstance(ctx, topo, ins, options, tlsCfg, systemdMode); err != nil {
+				return err
+			}
+		}
+		if isStore {
+			if err := waitRegions(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// restartRollingInstance restarts the instance with the hooks of rolling
+// update, e.g. evicting and recovering leaders
+func restartRollingInstance(ctx context.Context, topo spec.Topology, ins spec.Instance, options Options, tlsCfg *tls.Config, systemdMode string) error {
+	rollingInstance, isRollingInstance := ins.(spec.RollingUpdateInstance)
+	if isRollingInstance {
+		if err := rollingInstance.PreRestart(ctx, topo, int(options.APITimeout), tlsCfg, nil); err != nil && !options.Force {
+			return err
+		}
+	}
+	if err := restartInstance(ctx, ins, options.OptTimeout, tlsCfg, systemdMode); err != nil && !options.Force {
+		return err
+	}
+	if isRollingInstance {
+		if err := rollingInstance.PostRestart(ctx, topo, tlsCfg, nil); err != nil && !options.Force {
+			return err
+		}
+	}
+	return nil
+}
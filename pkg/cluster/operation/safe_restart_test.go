@@ -0,0 +1,34 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package operator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegionHealthGateViolations(t *testing.T) {
+	gate := RegionHealthGate{MaxPendingPeer: 2}
+
+	require.Empty(t, gate.violations(map[string]int{}))
+	require.Empty(t, gate.violations(map[string]int{RegionStatePendingPeer: 2}))
+	require.Equal(t, []string{
+		"1 miss-peer regions (max 0)",
+		"3 pending-peer regions (max 2)",
+	}, gate.violations(map[string]int{
+		RegionStateMissPeer:    1,
+		RegionStatePendingPeer: 3,
+	}))
+}
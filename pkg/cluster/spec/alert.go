@@ -0,0 +1,201 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/pingcap/tiup/pkg/meta"
+	"github.com/pingcap/tiup/pkg/set"
+	"github.com/pingcap/tiup/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// AlertOverrideSpec represents the changes to the alert rules shipped with
+// Prometheus, keyed by the alert name, e.g.:
+//
+//	alert_overrides:
+//	  rules:
+//	    TiKV_server_is_down:
+//	      disable: true
+//	    PD_cluster_storage_low_space:
+//	      expr: sum(pd_cluster_status{type="storage_size"}) / sum(pd_cluster_status{type="storage_capacity"}) * 100 > 80
+//	      for: 10m
+//	      labels: { level: critical }
+//	      annotations: { runbook: "https://wiki.example.com/pd-low-space" }
+type AlertOverrideSpec struct {
+	Rules map[string]AlertRuleOverride `yaml:"rules,omitempty"`
+}
+
+// AlertRuleOverride represents the changes to an alert rule
+type AlertRuleOverride struct {
+	Disable     bool              `yaml:"disable,omitempty"`
+	Expr        string            `yaml:"expr,omitempty"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// IsEmpty returns true if no alert rule is overridden
+func (s *AlertOverrideSpec) IsEmpty() bool {
+	return len(s.Rules) == 0
+}
+
+// Validate checks the alert overrides
+func (s *AlertOverrideSpec) Validate() error {
+	for name, rule := range s.Rules {
+		if strings.TrimSpace(name) == "" {
+			return perrs.New("empty alert name in alert_overrides")
+		}
+		if rule.Disable && (rule.Expr != "" || rule.For != "" || len(rule.Labels) > 0 || len(rule.Annotations) > 0) {
+			return perrs.Errorf("alert %s in alert_overrides is disabled, no other change should be set", name)
+		}
+	}
+	return nil
+}
+
+// Apply applies the overrides to the content of a rule file, it returns the
+// new content and the names of alerts overridden
+func (s *AlertOverrideSpec) Apply(content []byte) ([]byte, []string, error) {
+	var file map[string]any
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, nil, err
+	}
+	groups, _ := file["groups"].([]any)
+
+	var applied []string
+	for _, g := range groups {
+		group, ok := g.(map[string]any)
+		if !ok {
+			continue
+		}
+		rules, _ := group["rules"].([]any)
+		kept := make([]any, 0, len(rules))
+		for _, r := range rules {
+			rule, ok := r.(map[string]any)
+			if !ok {
+				kept = append(kept, r)
+				continue
+			}
+			name, _ := rule["alert"].(string)
+			override, found := s.Rules[name]
+			if !found {
+				kept = append(kept, r)
+				continue
+			}
+			applied = append(applied, name)
+			if override.Disable {
+				continue
+			}
+			if override.Expr != "" {
+				rule["expr"] = override.Expr
+			}
+			if override.For != "" {
+				rule["for"] = override.For
+			}
+			mergeStringMap(rule, "labels", override.Labels)
+			mergeStringMap(rule, "annotations", override.Annotations)
+			kept = append(kept, rule)
+		}
+		group["rules"] = kept
+	}
+
+	if len(applied) == 0 {
+		return content, nil, nil
+	}
+	data, err := yaml.Marshal(file)
+	return data, applied, err
+}
+
+func mergeStringMap(rule map[string]any, key string, kvs map[string]string) {
+	if len(kvs) == 0 {
+		return
+	}
+	m, ok := rule[key].(map[string]any)
+	if !ok {
+		m = make(map[string]any)
+	}
+	for k, v := range kvs {
+		m[k] = v
+	}
+	rule[key] = m
+}
+
+func (s *Specification) validateAlertOverrides() error {
+	return s.AlertOverrides.Validate()
+}
+
+// overrideRules applies the alert overrides of the topology to the rule
+// files installed in the conf directory of the Prometheus instance
+func (i *MonitorInstance) overrideRules(ctx context.Context, e ctxt.Executor, paths meta.DirPaths) error {
+	topo, ok := i.topo.(*Specification)
+	if !ok || topo.AlertOverrides.IsEmpty() {
+		return nil
+	}
+
+	confDir := filepath.Join(paths.Deploy, "conf")
+	stdout, stderr, err := e.Execute(ctx, fmt.Sprintf(`find %s -maxdepth 1 -type f -name "*.rules.yml"`, confDir), false)
+	if err != nil {
+		return perrs.Annotatef(err, "stderr: %s", string(stderr))
+	}
+
+	applied := set.NewStringSet()
+	for _, remote := range strings.Fields(string(stdout)) {
+		local := filepath.Join(paths.Cache, fmt.Sprintf("%s_%d_%s", i.GetHost(), i.GetPort(), filepath.Base(remote)))
+		if err := e.Transfer(ctx, remote, local, true, 0, false); err != nil {
+			return err
+		}
+		content, err := os.ReadFile(local)
+		if err != nil {
+			return err
+		}
+		data, names, err := topo.AlertOverrides.Apply(content)
+		if err != nil {
+			return perrs.Annotatef(err, "override rules in %s", remote)
+		}
+		if len(names) == 0 {
+			continue
+		}
+		for _, name := range names {
+			applied.Insert(name)
+		}
+		if err := utils.WriteFile(local, data, 0644); err != nil {
+			return err
+		}
+		if err := e.Transfer(ctx, local, remote, false, 0, false); err != nil {
+			return err
+		}
+	}
+
+	var missing []string
+	for name := range topo.AlertOverrides.Rules {
+		if !applied.Exist(name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		logger := ctx.Value(logprinter.ContextKeyLogger).(*logprinter.Logger)
+		logger.Warnf("Alert rules not found on %s, their overrides are ignored: %s", i.ID(), strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestAlertOverrides(t *testing.T) {
+	rules := []byte(`groups:
+  - name: alert.rules
+    rules:
+      - alert: TiKV_server_is_down
+        expr: probe_success{group="tikv"} == 0
+        for: 1m
+        labels:
+          level: critical
+      - alert: TiKV_GC_can_not_work
+        expr: sum(increase(tikv_gcworker_gc_tasks_vec{task="gc"}[1d])) < 1
+        for: 5m
+        labels:
+          level: emergency
+`)
+
+	spec := AlertOverrideSpec{}
+	data, applied, err := spec.Apply(rules)
+	require.NoError(t, err)
+	require.Empty(t, applied)
+	require.Equal(t, rules, data)
+
+	require.NoError(t, yaml.Unmarshal([]byte(`
+rules:
+  TiKV_server_is_down:
+    disable: true
+  TiKV_GC_can_not_work:
+    for: 10m
+    labels: { level: warning, team: storage }
+    annotations: { runbook: "https://wiki.example.com/gc" }
+  Not_exist:
+    for: 1m
+`), &spec))
+	require.NoError(t, spec.Validate())
+
+	data, applied, err = spec.Apply(rules)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"TiKV_server_is_down", "TiKV_GC_can_not_work"}, applied)
+
+	var file struct {
+		Groups []struct {
+			Rules []struct {
+				Alert       string            `yaml:"alert"`
+				Expr        string            `yaml:"expr"`
+				For         string            `yaml:"for"`
+				Labels      map[string]string `yaml:"labels"`
+				Annotations map[string]string `yaml:"annotations"`
+			} `yaml:"rules"`
+		} `yaml:"groups"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &file))
+	require.Len(t, file.Groups, 1)
+	require.Len(t, file.Groups[0].Rules, 1)
+	rule := file.Groups[0].Rules[0]
+	require.Equal(t, "TiKV_GC_can_not_work", rule.Alert)
+	require.Equal(t, `sum(increase(tikv_gcworker_gc_tasks_vec{task="gc"}[1d])) < 1`, rule.Expr)
+	require.Equal(t, "10m", rule.For)
+	require.Equal(t, map[string]string{"level": "warning", "team": "storage"}, rule.Labels)
+	require.Equal(t, map[string]string{"runbook": "https://wiki.example.com/gc"}, rule.Annotations)
+
+	spec.Rules["TiKV_server_is_down"] = AlertRuleOverride{Disable: true, For: "1m"}
+	require.Error(t, spec.Validate())
+}
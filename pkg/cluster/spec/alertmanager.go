@@ -42,6 +42,7 @@ type AlertmanagerSpec struct {
 	LogDir          string               `yaml:"log_dir,omitempty"`
 	NumaNode        string               `yaml:"numa_node,omitempty" validate:"numa_node:editable"`
 	ResourceControl meta.ResourceControl `yaml:"resource_control,omitempty" validate:"resource_control:editable"`
+	Templates       TemplateSpec         `yaml:"templates,omitempty" validate:"templates:editable"`
 	// This field allows users to define additional arguments for customization.
 	AdditionalArgs []string `yaml:"additional_args,omitempty" validate:"additional_args:ignore"`
 	Arch           string   `yaml:"arch,omitempty"`
@@ -204,7 +205,7 @@ func (i *AlertManagerInstance) InitConfig(
 	}
 
 	fp := filepath.Join(paths.Cache, fmt.Sprintf("run_alertmanager_%s_%d.sh", i.GetHost(), i.GetPort()))
-	if err := cfg.ConfigToFile(fp); err != nil {
+	if err := i.renderRunScript(cfg, paths, fp); err != nil {
 		return err
 	}
 
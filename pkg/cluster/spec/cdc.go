@@ -50,6 +50,7 @@ type CDCSpec struct {
 	NumaNode        string               `yaml:"numa_node,omitempty" validate:"numa_node:editable"`
 	Config          map[string]any       `yaml:"config,omitempty" validate:"config:ignore"`
 	ResourceControl meta.ResourceControl `yaml:"resource_control,omitempty" validate:"resource_control:editable"`
+	Templates       TemplateSpec         `yaml:"templates,omitempty" validate:"templates:editable"`
 	Arch            string               `yaml:"arch,omitempty"`
 	OS              string               `yaml:"os,omitempty"`
 }
@@ -244,7 +245,7 @@ func (i *CDCInstance) InitConfig(
 
 	fp := filepath.Join(paths.Cache, fmt.Sprintf("run_cdc_%s_%d.sh", i.GetHost(), i.GetPort()))
 
-	if err := cfg.ConfigToFile(fp); err != nil {
+	if err := i.renderRunScript(cfg, paths, fp); err != nil {
 		return err
 	}
 	dst := filepath.Join(paths.Deploy, "scripts", "run_cdc.sh")
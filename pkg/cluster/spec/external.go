@@ -0,0 +1,122 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/template/config"
+)
+
+// ExternalEndpoints represents the components of a cluster not managed by
+// TiUP, they are scraped by the Prometheus servers of the topology so that
+// a monitoring only topology can be deployed for the cluster, e.g.:
+//
+//	external_endpoints:
+//	  pd: [ 10.0.1.1:2379 ]
+//	  tidb: [ 10.0.1.2:10080 ]
+//	  tikv: [ 10.0.1.3:20180 ]
+//
+// The addresses are the status addresses of components, i.e. the ones
+// serving the metrics.
+type ExternalEndpoints struct {
+	PD             []string `yaml:"pd,omitempty"`
+	TiDB           []string `yaml:"tidb,omitempty"`
+	TiKV           []string `yaml:"tikv,omitempty"`
+	TiFlash        []string `yaml:"tiflash,omitempty"`
+	TiFlashLearner []string `yaml:"tiflash_learner,omitempty"`
+	TiProxy        []string `yaml:"tiproxy,omitempty"`
+	CDC            []string `yaml:"cdc,omitempty"`
+	NodeExporter   []string `yaml:"node_exporter,omitempty"`
+}
+
+// IsEmpty returns true if no external endpoint is specified
+func (e *ExternalEndpoints) IsEmpty() bool {
+	v := reflect.ValueOf(e).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Len() > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Validate checks all the endpoints are valid host:port addresses
+func (e *ExternalEndpoints) Validate() error {
+	v := reflect.ValueOf(e).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		for _, addr := range v.Field(i).Interface().([]string) {
+			if _, _, err := splitEndpoint(addr); err != nil {
+				return perrs.Annotatef(err, "invalid external_endpoints.%s", name)
+			}
+		}
+	}
+	return nil
+}
+
+// addTargets adds the endpoints to the scrape configs of Prometheus
+func (e *ExternalEndpoints) addTargets(cfig *config.PrometheusConfig) {
+	add := func(addrs []string, fn func(string, uint64) *config.PrometheusConfig) {
+		for _, addr := range addrs {
+			host, port, _ := splitEndpoint(addr)
+			fn(host, port)
+		}
+	}
+	add(e.PD, cfig.AddPD)
+	add(e.TiDB, cfig.AddTiDB)
+	add(e.TiKV, cfig.AddTiKV)
+	add(e.TiFlash, cfig.AddTiFlash)
+	add(e.TiFlashLearner, cfig.AddTiFlashLearner)
+	add(e.TiProxy, cfig.AddTiProxy)
+	add(e.CDC, cfig.AddCDC)
+	add(e.NodeExporter, cfig.AddNodeExpoertor)
+}
+
+func splitEndpoint(addr string) (string, uint64, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", 0, err
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil || p == 0 || host == "" {
+		return "", 0, perrs.Errorf("invalid address '%s'", addr)
+	}
+	return host, p, nil
+}
+
+func (s *Specification) validateExternalEndpoints() error {
+	return s.ExternalEndpoints.Validate()
+}
+
+// IsMonitoringOnly returns true if the topology only contains monitoring
+// components for a cluster specified by the external endpoints
+func (s *Specification) IsMonitoringOnly() bool {
+	if s.ExternalEndpoints.IsEmpty() {
+		return false
+	}
+	monitoringOnly := true
+	s.IterInstance(func(inst Instance) {
+		switch inst.ComponentName() {
+		case ComponentGrafana, ComponentPrometheus, ComponentAlertmanager:
+		default:
+			monitoringOnly = false
+		}
+	})
+	return monitoringOnly
+}
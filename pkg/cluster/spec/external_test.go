@@ -0,0 +1,63 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiup/embed"
+	"github.com/pingcap/tiup/pkg/cluster/template/config"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExternalEndpoints(t *testing.T) {
+	example, err := embed.ReadExample("examples/cluster/monitoring-only.yaml")
+	require.NoError(t, err)
+
+	topo := Specification{}
+	require.NoError(t, yaml.Unmarshal(example, &topo))
+	require.True(t, topo.IsMonitoringOnly())
+	require.Len(t, topo.ExternalEndpoints.PD, 3)
+
+	cfig := config.NewPrometheusConfig("test", "v8.5.0", false)
+	topo.ExternalEndpoints.addTargets(cfig)
+	content, err := cfig.Config()
+	require.NoError(t, err)
+	for _, target := range []string{"10.0.1.11:2379", "10.0.1.14:10080", "10.0.1.18:20180"} {
+		require.Contains(t, string(content), target)
+	}
+
+	// not monitoring only if any other component is deployed
+	topo.TiDBServers = append(topo.TiDBServers, &TiDBSpec{Host: "10.0.1.23"})
+	require.False(t, topo.IsMonitoringOnly())
+
+	// not monitoring only without external endpoints
+	topo = Specification{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+monitoring_servers:
+  - host: 10.0.1.22
+`), &topo))
+	require.False(t, topo.IsMonitoringOnly())
+
+	topo = Specification{}
+	err = yaml.Unmarshal([]byte(`
+external_endpoints:
+  tikv: [ 10.0.1.16 ]
+monitoring_servers:
+  - host: 10.0.1.22
+`), &topo)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid external_endpoints.tikv")
+}
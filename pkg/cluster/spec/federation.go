@@ -0,0 +1,182 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/set"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// The modes of collecting the metrics of member clusters into the central one
+const (
+	// FederationModeRemoteWrite makes the Prometheus servers of member clusters
+	// push metrics to the central ones with remote write
+	FederationModeRemoteWrite = "remote-write"
+	// FederationModeFederate makes the central Prometheus servers scrape the
+	// /federate endpoint of the ones of member clusters
+	FederationModeFederate = "federate"
+)
+
+// FederationClusterLabel is the label identifying the member cluster of
+// metrics in the central cluster, it is the one used by the cluster selector
+// of the dashboards
+const FederationClusterLabel = "tidb_cluster"
+
+// FederationMember represents a cluster whose metrics are collected by the
+// Prometheus server, e.g.:
+//
+//	federation:
+//	  - cluster: tidb-prod-1
+//	    mode: federate
+//	    targets: [ 10.0.2.1:9090 ]
+//	  - cluster: tidb-prod-2
+//	    mode: remote-write
+type FederationMember struct {
+	Cluster string   `yaml:"cluster"`
+	Mode    string   `yaml:"mode"`
+	Targets []string `yaml:"targets,omitempty"` // the Prometheus servers of the member cluster in federate mode
+}
+
+// EnableRemoteWriteReceiver returns true if any member cluster pushes metrics
+// to the Prometheus server
+func (s *PrometheusSpec) EnableRemoteWriteReceiver() bool {
+	for _, member := range s.Federation {
+		if member.Mode == FederationModeRemoteWrite {
+			return true
+		}
+	}
+	return false
+}
+
+// SetFederationMember adds the member cluster or updates it if exists
+func (s *PrometheusSpec) SetFederationMember(member FederationMember) {
+	for idx := range s.Federation {
+		if s.Federation[idx].Cluster == member.Cluster {
+			s.Federation[idx] = member
+			return
+		}
+	}
+	s.Federation = append(s.Federation, member)
+}
+
+// federationRemoteWritePrefix is the name prefix of remote write configs
+// pushing metrics to the central cluster
+func federationRemoteWritePrefix(central string) string {
+	return fmt.Sprintf("federate/%s/", central)
+}
+
+// SetFederationRemoteWrite replaces the remote write configs pushing metrics
+// to the central cluster with the ones to the specified addresses
+func (s *PrometheusSpec) SetFederationRemoteWrite(central string, addrs []string) {
+	prefix := federationRemoteWritePrefix(central)
+	remoteWrite := make([]map[string]any, 0, len(s.RemoteConfig.RemoteWrite)+len(addrs))
+	for _, rw := range s.RemoteConfig.RemoteWrite {
+		if name, ok := rw["name"].(string); ok && strings.HasPrefix(name, prefix) {
+			continue
+		}
+		remoteWrite = append(remoteWrite, rw)
+	}
+	for _, addr := range addrs {
+		remoteWrite = append(remoteWrite, map[string]any{
+			"name": prefix + addr,
+			// monitor do not support tls for itself
+			"url": fmt.Sprintf("http://%s/api/v1/write", addr),
+		})
+	}
+	s.RemoteConfig.RemoteWrite = remoteWrite
+}
+
+func (s *Specification) validateFederation() error {
+	for _, monitor := range s.Monitors {
+		clusters := set.NewStringSet()
+		for _, member := range monitor.Federation {
+			if member.Cluster == "" {
+				return perrs.Errorf("monitoring_servers:%s.federation contains empty cluster name", monitor.Host)
+			}
+			if clusters.Exist(member.Cluster) {
+				return perrs.Errorf("monitoring_servers:%s.federation contains duplicated cluster '%s'", monitor.Host, member.Cluster)
+			}
+			clusters.Insert(member.Cluster)
+
+			switch member.Mode {
+			case FederationModeRemoteWrite:
+			case FederationModeFederate:
+				if len(member.Targets) == 0 {
+					return perrs.Errorf("monitoring_servers:%s.federation of cluster '%s' has no targets", monitor.Host, member.Cluster)
+				}
+				for _, target := range member.Targets {
+					if _, _, err := splitEndpoint(target); err != nil {
+						return perrs.Annotatef(err, "monitoring_servers:%s.federation of cluster '%s'", monitor.Host, member.Cluster)
+					}
+				}
+			default:
+				return perrs.Errorf("monitoring_servers:%s.federation of cluster '%s' has invalid mode '%s', should be %s or %s",
+					monitor.Host, member.Cluster, member.Mode, FederationModeRemoteWrite, FederationModeFederate)
+			}
+		}
+	}
+	return nil
+}
+
+// isFederationCentral returns true if the topology collects metrics of other
+// clusters
+func isFederationCentral(topo Topology) bool {
+	for _, monitor := range topo.BaseTopo().Monitors {
+		if len(monitor.Federation) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// showClusterSelector makes the hidden variable of the cluster label in the
+// dashboard visible, so that the metrics of member clusters can be selected
+func showClusterSelector(content []byte) ([]byte, error) {
+	var dashboard map[string]any
+	if err := json.Unmarshal(content, &dashboard); err != nil {
+		return nil, err
+	}
+	templating, _ := dashboard["templating"].(map[string]any)
+	variables, _ := templating["list"].([]any)
+
+	found := false
+	for _, v := range variables {
+		variable, ok := v.(map[string]any)
+		if !ok || variable["name"] != FederationClusterLabel {
+			continue
+		}
+		variable["hide"] = 0
+		variable["label"] = "Cluster"
+		found = true
+	}
+	if !found {
+		return content, nil
+	}
+	return json.MarshalIndent(dashboard, "", "  ")
+}
+
+// FederationTargets returns the addresses of the Prometheus servers of the
+// topology
+func FederationTargets(topo Topology) []string {
+	var addrs []string
+	for _, monitor := range topo.BaseTopo().Monitors {
+		addrs = append(addrs, utils.JoinHostPort(monitor.Host, monitor.Port))
+	}
+	return addrs
+}
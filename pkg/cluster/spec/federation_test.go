@@ -0,0 +1,107 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestFederationRemoteWrite(t *testing.T) {
+	monitor := &PrometheusSpec{}
+	monitor.RemoteConfig.RemoteWrite = []map[string]any{{"url": "http://10.0.3.1:8428/api/v1/write"}}
+
+	monitor.SetFederationRemoteWrite("central", []string{"10.0.1.1:9090", "10.0.1.2:9090"})
+	monitor.SetFederationRemoteWrite("central", []string{"10.0.1.3:9090"})
+	monitor.SetFederationRemoteWrite("central-2", []string{"10.0.4.1:9090"})
+	require.Equal(t, []map[string]any{
+		{"url": "http://10.0.3.1:8428/api/v1/write"},
+		{"name": "federate/central/10.0.1.3:9090", "url": "http://10.0.1.3:9090/api/v1/write"},
+		{"name": "federate/central-2/10.0.4.1:9090", "url": "http://10.0.4.1:9090/api/v1/write"},
+	}, monitor.RemoteConfig.RemoteWrite)
+
+	// switching to federate mode removes the remote write configs
+	monitor.SetFederationRemoteWrite("central", nil)
+	require.Len(t, monitor.RemoteConfig.RemoteWrite, 2)
+}
+
+func TestValidateFederation(t *testing.T) {
+	topo := Specification{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+monitoring_servers:
+  - host: 10.0.1.1
+    federation:
+      - cluster: prod-1
+        mode: remote-write
+      - cluster: prod-2
+        mode: federate
+        targets: [ 10.0.2.1:9090 ]
+`), &topo))
+	require.NoError(t, topo.validateFederation())
+	require.True(t, topo.Monitors[0].EnableRemoteWriteReceiver())
+	require.True(t, isFederationCentral(&topo))
+
+	topo.Monitors[0].SetFederationMember(FederationMember{Cluster: "prod-1", Mode: FederationModeFederate, Targets: []string{"10.0.2.2:9090"}})
+	require.Len(t, topo.Monitors[0].Federation, 2)
+	require.False(t, topo.Monitors[0].EnableRemoteWriteReceiver())
+	require.NoError(t, topo.validateFederation())
+
+	for _, member := range []FederationMember{
+		{Mode: FederationModeRemoteWrite},
+		{Cluster: "prod-1", Mode: FederationModeRemoteWrite},
+		{Cluster: "prod-3", Mode: "push"},
+		{Cluster: "prod-3", Mode: FederationModeFederate},
+		{Cluster: "prod-3", Mode: FederationModeFederate, Targets: []string{"10.0.2.1"}},
+	} {
+		topo := Specification{Monitors: []*PrometheusSpec{{Host: "10.0.1.1", Federation: []FederationMember{
+			{Cluster: "prod-1", Mode: FederationModeRemoteWrite},
+		}}}}
+		topo.Monitors[0].Federation = append(topo.Monitors[0].Federation, member)
+		require.Error(t, topo.validateFederation(), member)
+	}
+}
+
+func TestShowClusterSelector(t *testing.T) {
+	content := []byte(`{"uid": "tidb", "templating": {"list": [
+		{"name": "k8s_cluster", "hide": 2},
+		{"name": "tidb_cluster", "hide": 2, "label": "tidb_cluster"}
+	]}}`)
+	data, err := showClusterSelector(content)
+	require.NoError(t, err)
+
+	var dashboard struct {
+		UID        string `json:"uid"`
+		Templating struct {
+			List []struct {
+				Name  string `json:"name"`
+				Hide  int    `json:"hide"`
+				Label string `json:"label"`
+			} `json:"list"`
+		} `json:"templating"`
+	}
+	require.NoError(t, json.Unmarshal(data, &dashboard))
+	require.Equal(t, "tidb", dashboard.UID)
+	require.Equal(t, 2, dashboard.Templating.List[0].Hide)
+	require.Equal(t, 0, dashboard.Templating.List[1].Hide)
+	require.Equal(t, "Cluster", dashboard.Templating.List[1].Label)
+
+	// dashboards without the variable are not changed
+	content = []byte(`{"uid": "blackbox"}`)
+	data, err = showClusterSelector(content)
+	require.NoError(t, err)
+	require.Equal(t, content, data)
+}
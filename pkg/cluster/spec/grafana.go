@@ -14,53 +14,49 @@
 package spec
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
 	"fmt"
 	"maps"
 	"os"
-	"path"
 	"path/filepath"
-	"reflect"
 	"strings"
 	"time"
 
-	"text/template"
-
 	"github.com/pingcap/errors"
-	"github.com/pingcap/tiup/embed"
 	"github.com/pingcap/tiup/pkg/cluster/ctxt"
 	"github.com/pingcap/tiup/pkg/cluster/template/config"
 	"github.com/pingcap/tiup/pkg/cluster/template/scripts"
 	"github.com/pingcap/tiup/pkg/meta"
-	"github.com/pingcap/tiup/pkg/utils"
 	"gopkg.in/ini.v1"
 )
 
 // GrafanaSpec represents the Grafana topology specification in topology.yaml
 type GrafanaSpec struct {
-	Host              string               `yaml:"host"`
-	ManageHost        string               `yaml:"manage_host,omitempty" validate:"manage_host:editable"`
-	SSHPort           int                  `yaml:"ssh_port,omitempty" validate:"ssh_port:editable"`
-	Patched           bool                 `yaml:"patched,omitempty"`
-	IgnoreExporter    bool                 `yaml:"ignore_exporter,omitempty"`
-	Port              int                  `yaml:"port" default:"3000"`
-	DeployDir         string               `yaml:"deploy_dir,omitempty"`
-	Config            map[string]string    `yaml:"config,omitempty" validate:"config:ignore"`
-	ResourceControl   meta.ResourceControl `yaml:"resource_control,omitempty" validate:"resource_control:editable"`
-	Arch              string               `yaml:"arch,omitempty"`
-	OS                string               `yaml:"os,omitempty"`
-	DashboardDir      string               `yaml:"dashboard_dir,omitempty" validate:"dashboard_dir:editable"`
-	Username          string               `yaml:"username,omitempty" default:"admin" validate:"username:editable"`
-	Password          string               `yaml:"password,omitempty" default:"admin" validate:"password:editable"`
-	AnonymousEnable   bool                 `yaml:"anonymous_enable" default:"false" validate:"anonymous_enable:editable"`
-	RootURL           string               `yaml:"root_url" validate:"root_url:editable"`
-	Domain            string               `yaml:"domain" validate:"domain:editable"`
-	DefaultTheme      string               `yaml:"default_theme,omitempty" validate:"default_theme:editable"`
-	OrgName           string               `yaml:"org_name,omitempty" validate:"org_name:editable"`
-	OrgRole           string               `yaml:"org_role,omitempty" validate:"org_role:editable"`
-	UseVMAsDatasource bool                 `yaml:"use_vm_as_datasource,omitempty" validate:"use_vm_as_datasource:editable"`
+	Host              string                 `yaml:"host"`
+	ManageHost        string                 `yaml:"manage_host,omitempty" validate:"manage_host:editable"`
+	SSHPort           int                    `yaml:"ssh_port,omitempty" validate:"ssh_port:editable"`
+	Patched           bool                   `yaml:"patched,omitempty"`
+	IgnoreExporter    bool                   `yaml:"ignore_exporter,omitempty"`
+	Port              int                    `yaml:"port" default:"3000"`
+	DeployDir         string                 `yaml:"deploy_dir,omitempty"`
+	Config            map[string]string      `yaml:"config,omitempty" validate:"config:ignore"`
+	ResourceControl   meta.ResourceControl   `yaml:"resource_control,omitempty" validate:"resource_control:editable"`
+	Templates         TemplateSpec           `yaml:"templates,omitempty" validate:"templates:editable"`
+	Arch              string                 `yaml:"arch,omitempty"`
+	OS                string                 `yaml:"os,omitempty"`
+	DashboardDir      string                 `yaml:"dashboard_dir,omitempty" validate:"dashboard_dir:editable"`
+	Username          string                 `yaml:"username,omitempty" default:"admin" validate:"username:editable"`
+	Password          string                 `yaml:"password,omitempty" default:"admin" validate:"password:editable"`
+	AnonymousEnable   bool                   `yaml:"anonymous_enable" default:"false" validate:"anonymous_enable:editable"`
+	RootURL           string                 `yaml:"root_url" validate:"root_url:editable"`
+	Domain            string                 `yaml:"domain" validate:"domain:editable"`
+	DefaultTheme      string                 `yaml:"default_theme,omitempty" validate:"default_theme:editable"`
+	OrgName           string                 `yaml:"org_name,omitempty" validate:"org_name:editable"`
+	OrgRole           string                 `yaml:"org_role,omitempty" validate:"org_role:editable"`
+	UseVMAsDatasource bool                   `yaml:"use_vm_as_datasource,omitempty" validate:"use_vm_as_datasource:editable"`
+	Dashboards        []GrafanaDashboardRule `yaml:"dashboards,omitempty" validate:"dashboards:ignore"`
+	Datasources       []GrafanaDatasource    `yaml:"datasources,omitempty" validate:"datasources:ignore"`
 }
 
 // Role returns the component role of the instance
@@ -190,7 +186,7 @@ func (i *GrafanaInstance) InitConfig(
 	cfg := &scripts.GrafanaScript{DeployDir: paths.Deploy}
 
 	fp := filepath.Join(paths.Cache, fmt.Sprintf("run_grafana_%s_%d.sh", i.GetHost(), i.GetPort()))
-	if err := cfg.ConfigToFile(fp); err != nil {
+	if err := i.renderRunScript(cfg, paths, fp); err != nil {
 		return err
 	}
 
@@ -244,13 +240,14 @@ func (i *GrafanaInstance) InitConfig(
 	}
 
 	// initial dashboards/*.json
-	if err := i.initDashboards(ctx, e, i.InstanceSpec.(*GrafanaSpec), paths, clusterName); err != nil {
+	folders, err := i.provisionDashboards(ctx, e, spec, paths, clusterName)
+	if err != nil {
 		return errors.Annotate(err, "initial dashboards")
 	}
 
 	// transfer dashboard.yml
 	fp = filepath.Join(paths.Cache, fmt.Sprintf("dashboard_%s.yml", i.GetHost()))
-	if err := config.NewDashboardConfig(clusterName, paths.Deploy).ConfigToFile(fp); err != nil {
+	if err := config.NewDashboardConfig(clusterName, paths.Deploy).WithFolders(folders).ConfigToFile(fp); err != nil {
 		return err
 	}
 	dst = filepath.Join(paths.Deploy, "provisioning", "dashboards", "dashboard.yml")
@@ -258,77 +255,15 @@ func (i *GrafanaInstance) InitConfig(
 		return err
 	}
 
-	// Get the grafana spec
-	spec = i.InstanceSpec.(*GrafanaSpec)
-
-	// Get monitors
-	topo := reflect.ValueOf(i.topo)
-	if topo.Kind() == reflect.Pointer {
-		topo = topo.Elem()
-	}
-	val := topo.FieldByName("Monitors")
-	if (val == reflect.Value{}) {
-		return errors.Errorf("field Monitors not found in topology: %v", topo)
-	}
-	monitors := val.Interface().([]*PrometheusSpec)
 	// transfer datasource.yml
+	monitors := i.topo.BaseTopo().Monitors
 	if len(monitors) == 0 {
 		return errors.New("no prometheus found in topology")
 	}
-
-	// Create datasources configuration
-	datasources := make([]*config.DatasourceConfig, 0)
-
-	// Determine which datasource is default based on Grafana spec
-	vmIsDefault := spec.UseVMAsDatasource && monitors[0].PromRemoteWriteToVM
-	promIsDefault := !vmIsDefault
-
-	// Add Prometheus datasource
-	promDatasource := config.NewDatasourceConfig(
-		clusterName,
-		// not support tls
-		fmt.Sprintf("http://%s", utils.JoinHostPort(monitors[0].Host, monitors[0].Port)),
-	).WithIsDefault(promIsDefault)
-	datasources = append(datasources, promDatasource)
-
-	// Add VM datasource if enabled
-	if monitors[0].PromRemoteWriteToVM && monitors[0].NgPort > 0 {
-		vmDatasource := config.NewDatasourceConfig(
-			fmt.Sprintf("%s-vm", clusterName),
-			// not support tls
-			fmt.Sprintf("http://%s", utils.JoinHostPort(monitors[0].Host, monitors[0].NgPort)),
-		).WithIsDefault(vmIsDefault)
-		datasources = append(datasources, vmDatasource)
-	}
-
-	// Write datasources configuration
 	fp = filepath.Join(paths.Cache, fmt.Sprintf("datasource_%s.yml", i.GetHost()))
-	content := bytes.NewBuffer(nil)
-
-	// Create a map to hold all datasources
-	datasourceMap := map[string]any{
-		"Datasources": datasources,
-	}
-
-	// Create a template for the datasource configuration
-	tpl, err := embed.ReadTemplate(path.Join("templates", "config", "datasource.yml.tpl"))
-	if err != nil {
+	if err := grafanaDatasources(spec, monitors, clusterName).ConfigToFile(fp); err != nil {
 		return err
 	}
-
-	tmpl, err := template.New("Datasource").Parse(string(tpl))
-	if err != nil {
-		return err
-	}
-
-	if err := tmpl.Execute(content, datasourceMap); err != nil {
-		return err
-	}
-
-	if err := utils.WriteFile(fp, content.Bytes(), 0644); err != nil {
-		return err
-	}
-
 	dst = filepath.Join(paths.Deploy, "provisioning", "datasources", "datasource.yml")
 	return i.TransferLocalConfigFile(ctx, e, fp, dst)
 }
@@ -338,60 +273,6 @@ func (i *GrafanaInstance) setTLSConfig(ctx context.Context, enableTLS bool, conf
 	return nil, nil
 }
 
-func (i *GrafanaInstance) initDashboards(ctx context.Context, e ctxt.Executor, spec *GrafanaSpec, paths meta.DirPaths, clusterName string) error {
-	dashboardsDir := filepath.Join(paths.Deploy, "dashboards")
-	if spec.DashboardDir != "" {
-		return i.TransferLocalConfigDir(ctx, e, spec.DashboardDir, dashboardsDir, func(name string) bool {
-			return strings.HasSuffix(name, ".json")
-		})
-	}
-
-	cmds := []string{
-		"mkdir -p %[1]s",
-		`find %[1]s -maxdepth 1 -type f -name "*.json" -delete`,
-		`find %[2]s/bin -maxdepth 1 -type f -name "*.json" -exec cp {} %[1]s \;`,
-	}
-	_, stderr, err := e.Execute(ctx, fmt.Sprintf(strings.Join(cmds, " && "), dashboardsDir, paths.Deploy), false)
-	if err != nil {
-		return errors.Annotatef(err, "stderr: %s", string(stderr))
-	}
-
-	// Get the monitor component to check if VM is the default datasource
-	topo := reflect.ValueOf(i.topo)
-	if topo.Kind() == reflect.Pointer {
-		topo = topo.Elem()
-	}
-	val := topo.FieldByName("Monitors")
-	if (val == reflect.Value{}) {
-		return errors.Errorf("field Monitors not found in topology: %v", topo)
-	}
-
-	// Determine which datasource to use in dashboards
-	datasourceName := clusterName
-	monitors := val.Interface().([]*PrometheusSpec)
-	if len(monitors) > 0 && monitors[0].PromRemoteWriteToVM && monitors[0].NgPort > 0 && (spec.UseVMAsDatasource) {
-		datasourceName = fmt.Sprintf("%s-vm", clusterName)
-	}
-
-	// Deal with the cluster name and datasource
-	for _, cmd := range []string{
-		`find %s -type f -exec sed -i 's/\${DS_.*-CLUSTER}/%s/g' {} \;`,
-		`find %s -type f -exec sed -i 's/DS_.*-CLUSTER/%s/g' {} \;`,
-		`find %s -type f -exec sed -i 's/\${DS_LIGHTNING}/%s/g' {} \;`,
-		`find %s -type f -exec sed -i 's/DS_LIGHTNING/%s/g' {} \;`,
-		`find %s -type f -exec sed -i 's/test-cluster/%s/g' {} \;`,
-		`find %s -type f -exec sed -i 's/Test-Cluster/%s/g' {} \;`,
-	} {
-		cmd := fmt.Sprintf(cmd, dashboardsDir, datasourceName)
-		_, stderr, err := e.Execute(ctx, cmd, false)
-		if err != nil {
-			return errors.Annotatef(err, "stderr: %s", string(stderr))
-		}
-	}
-
-	return nil
-}
-
 // We only really installDashboards for dm cluster because the dashboards(*.json) packed with
 // the grafana component is designed for tidb cluster (the dm cluster use the same cluster
 // component with tidb cluster), and the dashboards for dm cluster is packed in the dm-master
@@ -0,0 +1,492 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	"github.com/pingcap/tiup/pkg/cluster/template/config"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/pingcap/tiup/pkg/meta"
+	"github.com/pingcap/tiup/pkg/set"
+	"github.com/pingcap/tiup/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// The merge policies of dashboards when they are installed again, e.g. on
+// reload and upgrade
+const (
+	// DashboardMergeAuto replaces the dashboard unless it is modified by the
+	// user since installed, which is detected by its uid and version
+	DashboardMergeAuto = "auto"
+	// DashboardMergeOverwrite always replaces the dashboard
+	DashboardMergeOverwrite = "overwrite"
+	// DashboardMergeKeep never replaces the dashboard once installed
+	DashboardMergeKeep = "keep"
+)
+
+const (
+	dashboardsDir       = "dashboards"
+	dashboardFoldersDir = "dashboards_folders"
+	dashboardStagingDir = "_tiup_dashboards"
+	dashboardManifest   = ".tiup_manifest.yaml"
+)
+
+// GrafanaDashboardRule represents how a dashboard is installed, e.g.:
+//
+//	dashboards:
+//	  - name: tikv_details.json
+//	    folder: TiKV
+//	    merge: keep
+//	  - name: "*lightning*"
+//	    disable: true
+type GrafanaDashboardRule struct {
+	Name    string `yaml:"name"` // the file name (glob supported) or the uid of dashboards
+	Folder  string `yaml:"folder,omitempty"`
+	Disable bool   `yaml:"disable,omitempty"`
+	Merge   string `yaml:"merge,omitempty"`
+}
+
+// GrafanaDatasource represents an extra datasource of Grafana, e.g.:
+//
+//	datasources:
+//	  - name: loki
+//	    type: loki
+//	    url: http://10.0.1.30:3100
+//	  - name: es-logs
+//	    type: elasticsearch
+//	    url: http://10.0.1.31:9200
+//	    json_data: { index: "tidb-*", timeField: "@timestamp" }
+type GrafanaDatasource struct {
+	Name      string         `yaml:"name"`
+	Type      string         `yaml:"type"`
+	URL       string         `yaml:"url"`
+	IsDefault bool           `yaml:"is_default,omitempty"`
+	JSONData  map[string]any `yaml:"json_data,omitempty"`
+}
+
+func (s *GrafanaSpec) validateProvisioning() error {
+	for _, rule := range s.Dashboards {
+		if rule.Name == "" {
+			return perrs.New("empty name of dashboard rule")
+		}
+		if _, err := filepath.Match(rule.Name, ""); err != nil {
+			return perrs.Annotatef(err, "invalid name '%s' of dashboard rule", rule.Name)
+		}
+		switch rule.Merge {
+		case "", DashboardMergeAuto, DashboardMergeOverwrite, DashboardMergeKeep:
+		default:
+			return perrs.Errorf("invalid merge policy '%s' of dashboard %s, should be one of %s, %s and %s",
+				rule.Merge, rule.Name, DashboardMergeAuto, DashboardMergeOverwrite, DashboardMergeKeep)
+		}
+	}
+
+	names := set.NewStringSet()
+	defaults := 0
+	for _, ds := range s.Datasources {
+		if ds.Name == "" || ds.Type == "" || ds.URL == "" {
+			return perrs.Errorf("name, type and url of datasource '%s' must be set", ds.Name)
+		}
+		if names.Exist(ds.Name) {
+			return perrs.Errorf("duplicated datasource '%s'", ds.Name)
+		}
+		names.Insert(ds.Name)
+		if ds.IsDefault {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return perrs.New("only one datasource can be the default")
+	}
+	return nil
+}
+
+// ValidateGrafanaProvisioning checks the dashboard rules and datasources of
+// Grafana servers in the topology
+func ValidateGrafanaProvisioning(topo Topology) error {
+	for _, grafana := range topo.BaseTopo().Grafanas {
+		if err := grafana.validateProvisioning(); err != nil {
+			return perrs.Annotatef(err, "grafana %s", utils.JoinHostPort(grafana.Host, grafana.Port))
+		}
+	}
+	return nil
+}
+
+func (s *Specification) validateGrafanaProvisioning() error {
+	return ValidateGrafanaProvisioning(s)
+}
+
+// dashboardRule returns the first rule matching the dashboard
+func (s *GrafanaSpec) dashboardRule(file, uid string) GrafanaDashboardRule {
+	for _, rule := range s.Dashboards {
+		if matched, _ := filepath.Match(rule.Name, file); matched || (uid != "" && rule.Name == uid) {
+			return rule
+		}
+	}
+	return GrafanaDashboardRule{}
+}
+
+// dashboardRecord records a dashboard installed by TiUP
+type dashboardRecord struct {
+	Path    string `yaml:"path"`
+	UID     string `yaml:"uid,omitempty"`
+	Version int64  `yaml:"version,omitempty"`
+}
+
+// dashboardInfo returns the uid and version of a dashboard
+func dashboardInfo(content []byte) (string, int64, error) {
+	var dashboard struct {
+		UID     string `json:"uid"`
+		Version int64  `json:"version"`
+	}
+	err := json.Unmarshal(content, &dashboard)
+	return dashboard.UID, dashboard.Version, err
+}
+
+var folderDirRegexp = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// dashboardFolderDir returns the directory of dashboards in the folder
+func dashboardFolderDir(folder string) string {
+	return filepath.Join(dashboardFoldersDir, folderDirRegexp.ReplaceAllString(folder, "_"))
+}
+
+// dashboardResolution is the result of merging the dashboards to install
+// with the ones installed
+type dashboardResolution struct {
+	Files     map[string][]byte // relative path in the deploy directory -> content
+	Manifest  map[string]dashboardRecord
+	Folders   []string
+	Preserved []string
+}
+
+// resolveDashboards merges the dashboards to install with the deployed ones
+// by the rules, the staged and manifest are keyed by the file name of
+// dashboards, and the deployed are keyed by the relative path in the deploy
+// directory.
+func (s *GrafanaSpec) resolveDashboards(staged, deployed map[string][]byte, manifest map[string]dashboardRecord) dashboardResolution {
+	res := dashboardResolution{
+		Files:    make(map[string][]byte),
+		Manifest: make(map[string]dashboardRecord),
+	}
+	folders := set.NewStringSet()
+
+	names := make([]string, 0, len(staged))
+	for name := range staged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		content := staged[name]
+		uid, version, _ := dashboardInfo(content)
+		rule := s.dashboardRule(name, uid)
+		if rule.Disable {
+			continue
+		}
+
+		target := filepath.Join(dashboardsDir, name)
+		if rule.Folder != "" {
+			target = filepath.Join(dashboardFolderDir(rule.Folder), name)
+			folders.Insert(rule.Folder)
+		}
+		record := dashboardRecord{Path: target, UID: uid, Version: version}
+
+		prev, installed := manifest[name]
+		if !installed {
+			prev = dashboardRecord{Path: target}
+		}
+		if old, ok := deployed[prev.Path]; ok {
+			oldUID, oldVersion, err := dashboardInfo(old)
+			switch rule.Merge {
+			case DashboardMergeOverwrite:
+			case DashboardMergeKeep:
+				content = old
+				record.UID, record.Version = oldUID, oldVersion
+				if installed {
+					record.UID, record.Version = prev.UID, prev.Version
+				}
+			default:
+				if installed && err == nil && (oldUID != prev.UID || oldVersion != prev.Version) {
+					content = old
+					record.UID, record.Version = prev.UID, prev.Version
+					res.Preserved = append(res.Preserved, name)
+				}
+			}
+		}
+
+		res.Files[target] = content
+		res.Manifest[name] = record
+	}
+
+	res.Folders = folders.Slice()
+	sort.Strings(res.Folders)
+	return res
+}
+
+// provisionDashboards installs the dashboards packed with the component or in
+// the dashboard_dir, and returns the folders of them besides the one of the
+// cluster. The dashboards are merged with the installed ones by the rules if
+// any rule is set, otherwise they are replaced as before.
+func (i *GrafanaInstance) provisionDashboards(ctx context.Context, e ctxt.Executor, spec *GrafanaSpec, paths meta.DirPaths, clusterName string) ([]config.DashboardFolder, error) {
+	if len(spec.Dashboards) == 0 && !isFederationCentral(i.topo) {
+		// drop the folders and manifest of the rules set before
+		cmd := fmt.Sprintf("cd %s && rm -rf %s %s", paths.Deploy, dashboardFoldersDir, filepath.Join(dashboardsDir, dashboardManifest))
+		if _, stderr, err := e.Execute(ctx, cmd, false); err != nil {
+			return nil, perrs.Annotatef(err, "stderr: %s", string(stderr))
+		}
+		return nil, i.initDashboards(ctx, e, spec, paths, clusterName)
+	}
+
+	stagingDir := filepath.Join(paths.Deploy, dashboardStagingDir)
+	if spec.DashboardDir != "" {
+		if _, stderr, err := e.Execute(ctx, fmt.Sprintf("rm -rf %[1]s && mkdir -p %[1]s", stagingDir), false); err != nil {
+			return nil, perrs.Annotatef(err, "stderr: %s", string(stderr))
+		}
+		if err := i.TransferLocalConfigDir(ctx, e, spec.DashboardDir, stagingDir, func(name string) bool {
+			return strings.HasSuffix(name, ".json")
+		}); err != nil {
+			return nil, err
+		}
+	} else if err := i.copyPackageDashboards(ctx, e, spec, stagingDir, paths, clusterName); err != nil {
+		return nil, err
+	}
+
+	// fetch the staged and deployed dashboards
+	tarball := filepath.Join(paths.Deploy, dashboardStagingDir+".tar.gz")
+	dirs := strings.Join([]string{dashboardStagingDir, dashboardsDir, dashboardFoldersDir}, " ")
+	cmd := fmt.Sprintf("cd %s && mkdir -p %s && tar -czf %s %s", paths.Deploy, dirs, tarball, dirs)
+	if _, stderr, err := e.Execute(ctx, cmd, false); err != nil {
+		return nil, perrs.Annotatef(err, "stderr: %s", string(stderr))
+	}
+	cacheDir := filepath.Join(paths.Cache, fmt.Sprintf("dashboards_%s_%d", i.GetHost(), i.GetPort()))
+	if err := os.RemoveAll(cacheDir); err != nil {
+		return nil, err
+	}
+	localTarball := cacheDir + ".tar.gz"
+	if err := e.Transfer(ctx, tarball, localTarball, true, 0, false); err != nil {
+		return nil, err
+	}
+	staged, deployed, manifest, err := readDashboards(localTarball, filepath.Join(cacheDir, "fetched"))
+	if err != nil {
+		return nil, err
+	}
+
+	if isFederationCentral(i.topo) {
+		for name, content := range staged {
+			if staged[name], err = showClusterSelector(content); err != nil {
+				return nil, perrs.Annotatef(err, "parse dashboard %s", name)
+			}
+		}
+	}
+
+	res := spec.resolveDashboards(staged, deployed, manifest)
+	if len(res.Preserved) > 0 {
+		logger := ctx.Value(logprinter.ContextKeyLogger).(*logprinter.Logger)
+		logger.Warnf("Dashboards modified on %s are preserved, set merge policy of them to %s to replace: %s",
+			i.ID(), DashboardMergeOverwrite, strings.Join(res.Preserved, ", "))
+	}
+
+	// install the resolved dashboards
+	if err := writeDashboards(res, filepath.Join(cacheDir, "resolved"), localTarball); err != nil {
+		return nil, err
+	}
+	if err := e.Transfer(ctx, localTarball, tarball, false, 0, false); err != nil {
+		return nil, err
+	}
+	cmd = fmt.Sprintf("cd %s && rm -rf %s && tar --no-same-owner -xzf %s && rm -f %s", paths.Deploy, dirs, tarball, tarball)
+	if _, stderr, err := e.Execute(ctx, cmd, false); err != nil {
+		return nil, perrs.Annotatef(err, "stderr: %s", string(stderr))
+	}
+
+	folders := make([]config.DashboardFolder, 0, len(res.Folders))
+	for _, folder := range res.Folders {
+		folders = append(folders, config.DashboardFolder{
+			Name: folder,
+			Path: filepath.Join(paths.Deploy, dashboardFolderDir(folder)),
+		})
+	}
+	return folders, nil
+}
+
+// initDashboards replaces the dashboards with the ones packed with the
+// component or in the dashboard_dir
+func (i *GrafanaInstance) initDashboards(ctx context.Context, e ctxt.Executor, spec *GrafanaSpec, paths meta.DirPaths, clusterName string) error {
+	dir := filepath.Join(paths.Deploy, dashboardsDir)
+	if spec.DashboardDir != "" {
+		return i.TransferLocalConfigDir(ctx, e, spec.DashboardDir, dir, func(name string) bool {
+			return strings.HasSuffix(name, ".json")
+		})
+	}
+	return i.copyPackageDashboards(ctx, e, spec, dir, paths, clusterName)
+}
+
+// copyPackageDashboards copies the dashboards packed with the component to
+// the directory and replaces the cluster name and datasource in them
+func (i *GrafanaInstance) copyPackageDashboards(ctx context.Context, e ctxt.Executor, spec *GrafanaSpec, dir string, paths meta.DirPaths, clusterName string) error {
+	cmds := []string{
+		"mkdir -p %[1]s",
+		`find %[1]s -maxdepth 1 -type f -name "*.json" -delete`,
+		`find %[2]s/bin -maxdepth 1 -type f -name "*.json" -exec cp {} %[1]s \;`,
+	}
+	_, stderr, err := e.Execute(ctx, fmt.Sprintf(strings.Join(cmds, " && "), dir, paths.Deploy), false)
+	if err != nil {
+		return perrs.Annotatef(err, "stderr: %s", string(stderr))
+	}
+
+	// Determine which datasource to use in dashboards
+	datasourceName := clusterName
+	monitors := i.topo.BaseTopo().Monitors
+	if len(monitors) > 0 && monitors[0].PromRemoteWriteToVM && monitors[0].NgPort > 0 && (spec.UseVMAsDatasource) {
+		datasourceName = fmt.Sprintf("%s-vm", clusterName)
+	}
+
+	// Deal with the cluster name and datasource
+	for _, cmd := range []string{
+		`find %s -type f -exec sed -i 's/\${DS_.*-CLUSTER}/%s/g' {} \;`,
+		`find %s -type f -exec sed -i 's/DS_.*-CLUSTER/%s/g' {} \;`,
+		`find %s -type f -exec sed -i 's/\${DS_LIGHTNING}/%s/g' {} \;`,
+		`find %s -type f -exec sed -i 's/DS_LIGHTNING/%s/g' {} \;`,
+		`find %s -type f -exec sed -i 's/test-cluster/%s/g' {} \;`,
+		`find %s -type f -exec sed -i 's/Test-Cluster/%s/g' {} \;`,
+	} {
+		cmd := fmt.Sprintf(cmd, dir, datasourceName)
+		_, stderr, err := e.Execute(ctx, cmd, false)
+		if err != nil {
+			return perrs.Annotatef(err, "stderr: %s", string(stderr))
+		}
+	}
+	return nil
+}
+
+// readDashboards extracts the tarball fetched from the Grafana server and
+// returns the staged dashboards, deployed dashboards and the manifest
+func readDashboards(tarball, dir string) (staged, deployed map[string][]byte, manifest map[string]dashboardRecord, err error) {
+	f, err := os.Open(tarball)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer f.Close()
+	if err := utils.Untar(f, dir); err != nil {
+		return nil, nil, nil, err
+	}
+
+	staged = make(map[string][]byte)
+	deployed = make(map[string][]byte)
+	manifest = make(map[string]dashboardRecord)
+	err = filepath.Walk(dir, func(fp string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, fp)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(fp)
+		if err != nil {
+			return err
+		}
+		switch {
+		case rel == filepath.Join(dashboardsDir, dashboardManifest):
+			return yaml.Unmarshal(content, &manifest)
+		case !strings.HasSuffix(rel, ".json"):
+		case filepath.Dir(rel) == dashboardStagingDir:
+			staged[filepath.Base(rel)] = content
+		default:
+			deployed[rel] = content
+		}
+		return nil
+	})
+	return staged, deployed, manifest, err
+}
+
+// writeDashboards writes the resolved dashboards and the manifest to the
+// directory, and packs them into the tarball
+func writeDashboards(res dashboardResolution, dir, tarball string) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	for _, d := range []string{dashboardsDir, dashboardFoldersDir} {
+		if err := utils.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			return err
+		}
+	}
+	for rel, content := range res.Files {
+		if err := utils.MkdirAll(filepath.Dir(filepath.Join(dir, rel)), 0755); err != nil {
+			return err
+		}
+		if err := utils.WriteFile(filepath.Join(dir, rel), content, 0644); err != nil {
+			return err
+		}
+	}
+	data, err := yaml.Marshal(res.Manifest)
+	if err != nil {
+		return err
+	}
+	if err := utils.WriteFile(filepath.Join(dir, dashboardsDir, dashboardManifest), data, 0644); err != nil {
+		return err
+	}
+
+	f, err := os.Create(tarball)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return utils.Tar(f, dir)
+}
+
+// grafanaDatasources returns the datasources of the Grafana server
+func grafanaDatasources(spec *GrafanaSpec, monitors []*PrometheusSpec, clusterName string) config.DatasourcesConfig {
+	datasources := make(config.DatasourcesConfig, 0, len(spec.Datasources)+2)
+
+	// Determine which datasource is default based on Grafana spec
+	customDefault := false
+	for _, ds := range spec.Datasources {
+		customDefault = customDefault || ds.IsDefault
+	}
+	vmIsDefault := !customDefault && spec.UseVMAsDatasource && monitors[0].PromRemoteWriteToVM
+	promIsDefault := !customDefault && !vmIsDefault
+
+	// Add Prometheus datasource
+	datasources = append(datasources, config.NewDatasourceConfig(
+		clusterName,
+		// not support tls
+		fmt.Sprintf("http://%s", utils.JoinHostPort(monitors[0].Host, monitors[0].Port)),
+	).WithIsDefault(promIsDefault))
+
+	// Add VM datasource if enabled
+	if monitors[0].PromRemoteWriteToVM && monitors[0].NgPort > 0 {
+		datasources = append(datasources, config.NewDatasourceConfig(
+			fmt.Sprintf("%s-vm", clusterName),
+			// not support tls
+			fmt.Sprintf("http://%s", utils.JoinHostPort(monitors[0].Host, monitors[0].NgPort)),
+		).WithIsDefault(vmIsDefault))
+	}
+
+	for _, ds := range spec.Datasources {
+		datasources = append(datasources, config.NewDatasourceConfig(ds.Name, ds.URL).
+			WithType(ds.Type).
+			WithIsDefault(ds.IsDefault).
+			WithJSONData(ds.JSONData))
+	}
+	return datasources
+}
@@ -0,0 +1,218 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	"github.com/pingcap/tiup/pkg/cluster/template/config"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/pingcap/tiup/pkg/meta"
+	"github.com/pingcap/tiup/pkg/utils"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestResolveDashboards(t *testing.T) {
+	spec := &GrafanaSpec{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+dashboards:
+  - name: "*lightning*"
+    disable: true
+  - name: tikv_details.json
+    folder: TiKV Details
+  - name: pd-uid
+    merge: overwrite
+  - name: binlog.json
+    merge: keep
+`), spec))
+	require.NoError(t, spec.validateProvisioning())
+
+	staged := map[string][]byte{
+		"lightning.json":    []byte(`{"uid": "lightning", "version": 1}`),
+		"tikv_details.json": []byte(`{"uid": "tikv", "version": 2}`),
+		"pd.json":           []byte(`{"uid": "pd-uid", "version": 2}`),
+		"tidb.json":         []byte(`{"uid": "tidb", "version": 2}`),
+		"overview.json":     []byte(`{"uid": "overview", "version": 2}`),
+		"binlog.json":       []byte(`{"uid": "binlog", "version": 2}`),
+	}
+
+	// first installation
+	res := spec.resolveDashboards(staged, nil, nil)
+	require.Empty(t, res.Preserved)
+	require.Equal(t, []string{"TiKV Details"}, res.Folders)
+	require.Len(t, res.Files, 5)
+	require.NotContains(t, res.Manifest, "lightning.json")
+	tikvPath := filepath.Join(dashboardFoldersDir, "TiKV_Details", "tikv_details.json")
+	require.Equal(t, staged["tikv_details.json"], res.Files[tikvPath])
+	require.Equal(t, dashboardRecord{Path: tikvPath, UID: "tikv", Version: 2}, res.Manifest["tikv_details.json"])
+
+	// the user modifies tidb, pd and binlog dashboards, then upgrades
+	deployed := res.Files
+	deployed[filepath.Join(dashboardsDir, "tidb.json")] = []byte(`{"uid": "tidb", "version": 5}`)
+	deployed[filepath.Join(dashboardsDir, "pd.json")] = []byte(`{"uid": "pd-uid", "version": 5}`)
+	deployed[filepath.Join(dashboardsDir, "binlog.json")] = []byte(`{"uid": "binlog", "version": 5}`)
+	upgraded := map[string][]byte{}
+	for name := range staged {
+		upgraded[name] = []byte(`{"uid": "` + name + `", "version": 3}`)
+	}
+	upgraded["tidb.json"] = []byte(`{"uid": "tidb", "version": 3}`)
+	upgraded["pd.json"] = []byte(`{"uid": "pd-uid", "version": 3}`)
+
+	res = spec.resolveDashboards(upgraded, deployed, res.Manifest)
+	require.Equal(t, []string{"tidb.json"}, res.Preserved)
+	require.Equal(t, deployed[filepath.Join(dashboardsDir, "tidb.json")], res.Files[filepath.Join(dashboardsDir, "tidb.json")])
+	require.Equal(t, upgraded["pd.json"], res.Files[filepath.Join(dashboardsDir, "pd.json")])
+	require.Equal(t, deployed[filepath.Join(dashboardsDir, "binlog.json")], res.Files[filepath.Join(dashboardsDir, "binlog.json")])
+	require.Equal(t, upgraded["overview.json"], res.Files[filepath.Join(dashboardsDir, "overview.json")])
+	require.Equal(t, upgraded["tikv_details.json"], res.Files[tikvPath])
+	// the preserved dashboard is still detected as modified next time
+	require.Equal(t, int64(2), res.Manifest["tidb.json"].Version)
+}
+
+func TestValidateGrafanaProvisioning(t *testing.T) {
+	for _, tc := range []string{
+		`dashboards: [{ folder: TiKV }]`,
+		`dashboards: [{ name: "[", folder: TiKV }]`,
+		`dashboards: [{ name: tikv.json, merge: replace }]`,
+		`datasources: [{ name: loki, type: loki }]`,
+		`datasources: [{ name: loki, type: loki, url: "http://a" }, { name: loki, type: loki, url: "http://b" }]`,
+		`datasources: [{ name: a, type: loki, url: "http://a", is_default: true }, { name: b, type: loki, url: "http://b", is_default: true }]`,
+	} {
+		spec := &GrafanaSpec{}
+		require.NoError(t, yaml.Unmarshal([]byte(tc), spec))
+		require.Error(t, spec.validateProvisioning(), tc)
+	}
+}
+
+func TestGrafanaDatasources(t *testing.T) {
+	spec := &GrafanaSpec{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+datasources:
+  - name: loki
+    type: loki
+    url: http://10.0.1.30:3100
+  - name: es-logs
+    type: elasticsearch
+    url: http://10.0.1.31:9200
+    is_default: true
+    json_data: { index: "tidb-*", timeField: "@timestamp" }
+`), spec))
+	monitors := []*PrometheusSpec{{Host: "10.0.1.1", Port: 9090}}
+
+	data, err := grafanaDatasources(spec, monitors, "test").Config()
+	require.NoError(t, err)
+
+	var cfg struct {
+		Datasources []struct {
+			Name      string         `yaml:"name"`
+			Type      string         `yaml:"type"`
+			URL       string         `yaml:"url"`
+			IsDefault bool           `yaml:"isDefault"`
+			JSONData  map[string]any `yaml:"jsonData"`
+		} `yaml:"datasources"`
+	}
+	require.NoError(t, yaml.Unmarshal(data, &cfg))
+	require.Len(t, cfg.Datasources, 3)
+	require.Equal(t, "test", cfg.Datasources[0].Name)
+	require.Equal(t, "http://10.0.1.1:9090", cfg.Datasources[0].URL)
+	require.False(t, cfg.Datasources[0].IsDefault)
+	require.Equal(t, "loki", cfg.Datasources[1].Type)
+	require.Nil(t, cfg.Datasources[1].JSONData)
+	require.True(t, cfg.Datasources[2].IsDefault)
+	require.Equal(t, map[string]any{"index": "tidb-*", "timeField": "@timestamp"}, cfg.Datasources[2].JSONData)
+}
+
+// localExecutor runs the commands and transfers the files on the local host
+type localExecutor struct{}
+
+func (e *localExecutor) Execute(_ context.Context, cmd string, _ bool, _ ...time.Duration) ([]byte, []byte, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.Command("bash", "-c", cmd)
+	c.Stdout, c.Stderr = &stdout, &stderr
+	err := c.Run()
+	return stdout.Bytes(), stderr.Bytes(), err
+}
+
+func (e *localExecutor) Transfer(_ context.Context, src, dst string, _ bool, _ int, _ bool) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return utils.Copy(src, dst)
+}
+
+func TestProvisionDashboards(t *testing.T) {
+	ctx := ctxt.New(context.Background(), 0, logprinter.NewLogger(""))
+	deployDir := t.TempDir()
+	paths := meta.DirPaths{Deploy: deployDir, Cache: t.TempDir()}
+	require.NoError(t, os.MkdirAll(filepath.Join(deployDir, "bin"), 0755))
+	for name, content := range map[string]string{
+		"tidb.json":      `{"uid": "tidb", "version": 1, "title": "Test-Cluster-TiDB"}`,
+		"tikv.json":      `{"uid": "tikv", "version": 1}`,
+		"lightning.json": `{"uid": "lightning", "version": 1}`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(deployDir, "bin", name), []byte(content), 0644))
+	}
+
+	topo := new(Specification)
+	topo.Monitors = []*PrometheusSpec{{Host: "127.0.0.1", Port: 9090}}
+	topo.Grafanas = []*GrafanaSpec{{Host: "127.0.0.1", Port: 3000}}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+dashboards:
+  - name: tikv.json
+    folder: TiKV
+  - name: lightning
+    disable: true
+`), topo.Grafanas[0]))
+	comp := GrafanaComponent{topo}
+	grafana := comp.Instances()[0].(*GrafanaInstance)
+
+	folders, err := grafana.provisionDashboards(ctx, &localExecutor{}, topo.Grafanas[0], paths, "test")
+	require.NoError(t, err)
+	require.Equal(t, []config.DashboardFolder{{Name: "TiKV", Path: filepath.Join(deployDir, dashboardFoldersDir, "TiKV")}}, folders)
+	content, err := os.ReadFile(filepath.Join(deployDir, dashboardsDir, "tidb.json"))
+	require.NoError(t, err)
+	require.Equal(t, `{"uid": "tidb", "version": 1, "title": "test-TiDB"}`, string(content))
+	require.FileExists(t, filepath.Join(deployDir, dashboardFoldersDir, "TiKV", "tikv.json"))
+	require.NoFileExists(t, filepath.Join(deployDir, dashboardsDir, "lightning.json"))
+	require.NoFileExists(t, filepath.Join(deployDir, dashboardStagingDir+".tar.gz"))
+
+	// the dashboard modified by the user is preserved
+	modified := `{"uid": "tidb", "version": 3}`
+	require.NoError(t, os.WriteFile(filepath.Join(deployDir, dashboardsDir, "tidb.json"), []byte(modified), 0644))
+	_, err = grafana.provisionDashboards(ctx, &localExecutor{}, topo.Grafanas[0], paths, "test")
+	require.NoError(t, err)
+	content, err = os.ReadFile(filepath.Join(deployDir, dashboardsDir, "tidb.json"))
+	require.NoError(t, err)
+	require.Equal(t, modified, string(content))
+
+	// all dashboards are replaced without rules
+	topo.Grafanas[0].Dashboards = nil
+	folders, err = grafana.provisionDashboards(ctx, &localExecutor{}, topo.Grafanas[0], paths, "test")
+	require.NoError(t, err)
+	require.Empty(t, folders)
+	require.FileExists(t, filepath.Join(deployDir, dashboardsDir, "lightning.json"))
+	require.NoDirExists(t, filepath.Join(deployDir, dashboardFoldersDir))
+	require.NoFileExists(t, filepath.Join(deployDir, dashboardsDir, dashboardManifest))
+	content, err = os.ReadFile(filepath.Join(deployDir, dashboardsDir, "tidb.json"))
+	require.NoError(t, err)
+	require.Equal(t, `{"uid": "tidb", "version": 1, "title": "test-TiDB"}`, string(content))
+}
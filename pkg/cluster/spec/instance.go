@@ -202,13 +202,8 @@ func (i *BaseInstance) InitConfig(ctx context.Context, e ctxt.Executor, opt Glob
 
 	resource := MergeResourceControl(opt.ResourceControl, i.ResourceControl())
 	systemCfg := system.NewConfig(comp, user, paths.Deploy).
-		WithMemoryLimit(resource.MemoryLimit).
-		WithCPUQuota(resource.CPUQuota).
-		WithLimitCORE(resource.LimitCORE).
-		WithTimeoutStartSec(resource.TimeoutStartSec).
-		WithTimeoutStopSec(resource.TimeoutStopSec).
-		WithIOReadBandwidthMax(resource.IOReadBandwidthMax).
-		WithIOWriteBandwidthMax(resource.IOWriteBandwidthMax).
+		WithResourceControl(resource).
+		WithReadWritePaths(paths.HostDirs()...).
 		WithSystemdMode(string(systemdMode))
 
 	// For not auto start if using binlogctl to offline.
@@ -217,7 +212,24 @@ func (i *BaseInstance) InitConfig(ctx context.Context, e ctxt.Executor, opt Glob
 		systemCfg.Restart = "on-failure"
 	}
 
-	if err := systemCfg.ConfigToFile(sysCfg); err != nil {
+	tpls := i.localTemplates(paths)
+	if tpls.EnvFile != "" {
+		envFile := filepath.Join(paths.Deploy, "scripts", comp+".env")
+		if err := e.Transfer(ctx, tpls.EnvFile, envFile, false, 0, false); err != nil {
+			return errors.Annotatef(err, "transfer from %s to %s failed", tpls.EnvFile, envFile)
+		}
+		systemCfg.WithEnvironmentFile(envFile)
+	}
+
+	if tpls.SystemdUnit != "" {
+		content, err := renderTemplateFile(tpls.SystemdUnit, systemCfg)
+		if err != nil {
+			return err
+		}
+		if err := utils.WriteFile(sysCfg, content, 0755); err != nil {
+			return errors.Trace(err)
+		}
+	} else if err := systemCfg.ConfigToFile(sysCfg); err != nil {
 		return errors.Trace(err)
 	}
 	tgt := filepath.Join("/tmp", comp+"_"+uuid.New().String()+".service")
@@ -534,6 +546,39 @@ func MergeResourceControl(lhs, rhs meta.ResourceControl) meta.ResourceControl {
 	if rhs.TimeoutStopSec != "" {
 		lhs.TimeoutStopSec = rhs.TimeoutStopSec
 	}
+	if rhs.MemoryHigh != "" {
+		lhs.MemoryHigh = rhs.MemoryHigh
+	}
+	if rhs.MemorySwapMax != "" {
+		lhs.MemorySwapMax = rhs.MemorySwapMax
+	}
+	if rhs.AllowedCPUs != "" {
+		lhs.AllowedCPUs = rhs.AllowedCPUs
+	}
+	if rhs.CPUAffinity != "" {
+		lhs.CPUAffinity = rhs.CPUAffinity
+	}
+	if rhs.IODeviceWeight != "" {
+		lhs.IODeviceWeight = rhs.IODeviceWeight
+	}
+	if rhs.TasksMax != "" {
+		lhs.TasksMax = rhs.TasksMax
+	}
+	if rhs.LimitNOFILE != "" {
+		lhs.LimitNOFILE = rhs.LimitNOFILE
+	}
+	if rhs.OOMScoreAdjust != "" {
+		lhs.OOMScoreAdjust = rhs.OOMScoreAdjust
+	}
+	if rhs.ProtectSystem != "" {
+		lhs.ProtectSystem = rhs.ProtectSystem
+	}
+	if rhs.PrivateTmp != nil {
+		lhs.PrivateTmp = rhs.PrivateTmp
+	}
+	if rhs.NoNewPrivileges != nil {
+		lhs.NoNewPrivileges = rhs.NoNewPrivileges
+	}
 	return lhs
 }
 
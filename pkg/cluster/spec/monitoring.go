@@ -61,12 +61,14 @@ type PrometheusSpec struct {
 	RetentionSize         string                 `yaml:"storage_retention_size,omitempty" validate:"storage_retention_size:editable"`
 	RetentionTime         string                 `yaml:"storage_retention_time,omitempty" validate:"storage_retention_time:editable"`
 	ResourceControl       meta.ResourceControl   `yaml:"resource_control,omitempty" validate:"resource_control:editable"`
+	Templates             TemplateSpec           `yaml:"templates,omitempty" validate:"templates:editable"`
 	Arch                  string                 `yaml:"arch,omitempty"`
 	OS                    string                 `yaml:"os,omitempty"`
 	RuleDir               string                 `yaml:"rule_dir,omitempty" validate:"rule_dir:editable"`
 	AdditionalScrapeConf  map[string]any         `yaml:"additional_scrape_conf,omitempty" validate:"additional_scrape_conf:ignore"`
 	ScrapeInterval        string                 `yaml:"scrape_interval,omitempty" validate:"scrape_interval:editable"`
 	ScrapeTimeout         string                 `yaml:"scrape_timeout,omitempty" validate:"scrape_timeout:editable"`
+	Federation            []FederationMember     `yaml:"federation,omitempty" validate:"federation:ignore"`
 
 	AdditionalArgs     []string       `yaml:"additional_args,omitempty" validate:"additional_args:ignore"`
 	NgMonitoringConfig map[string]any `yaml:"ng_monitoring_config,omitempty" validate:"ng_monitoring_config:ignore"`
@@ -286,6 +288,8 @@ func (i *MonitorInstance) InitConfig(
 		NumaNode: spec.NumaNode,
 
 		AdditionalArgs: spec.AdditionalArgs,
+
+		EnableRemoteWriteReceiver: spec.EnableRemoteWriteReceiver(),
 	}
 	// Set retention policy
 	logPtr := ctx.Value(logprinter.ContextKeyLogger).(*logprinter.Logger)
@@ -300,7 +304,7 @@ func (i *MonitorInstance) InitConfig(
 	cfg.EnablePromAgentMode = cfg.EnablePromAgentMode || slices.Contains(spec.AdditionalArgs, "--enable-feature=agent")
 
 	fp := filepath.Join(paths.Cache, fmt.Sprintf("run_prometheus_%s_%d.sh", i.GetHost(), i.GetPort()))
-	if err := cfg.ConfigToFile(fp); err != nil {
+	if err := i.renderRunScript(cfg, paths, fp); err != nil {
 		return err
 	}
 
@@ -322,6 +326,11 @@ func (i *MonitorInstance) InitConfig(
 	cfig := config.NewPrometheusConfig(clusterName, clusterVersion, enableTLS)
 	// Pass topology external_labels through to the Prometheus config object.
 	cfig.SetExternalLabels(spec.ExternalLabels)
+	for _, member := range spec.Federation {
+		if member.Mode == FederationModeFederate {
+			cfig.AddFederateJob(member.Cluster, FederationClusterLabel, member.Targets)
+		}
+	}
 	if monitoredOptions != nil {
 		cfig.AddBlackbox(i.GetHost(), uint64(monitoredOptions.BlackboxExporterPort))
 	}
@@ -466,6 +475,10 @@ func (i *MonitorInstance) InitConfig(
 		}
 	}
 
+	if s, ok := i.topo.(*Specification); ok {
+		s.ExternalEndpoints.addTargets(cfig)
+	}
+
 	if monitoredOptions != nil {
 		for host := range uniqueHosts {
 			cfig.AddNodeExpoertor(host, uint64(monitoredOptions.NodeExporterPort))
@@ -511,6 +524,9 @@ func (i *MonitorInstance) InitConfig(
 				pdAddrs = append(pdAddrs, utils.JoinHostPort(pd.Host, pd.ClientPort))
 			}
 		}
+		if s, ok := i.topo.(*Specification); ok {
+			pdAddrs = append(pdAddrs, s.ExternalEndpoints.PD...)
+		}
 
 		// Build base ng-monitoring config as a map so user overrides via
 		// server_configs.ng_monitoring and per-instance ng_monitoring_config
@@ -682,7 +698,7 @@ func (i *MonitorInstance) initRules(ctx context.Context, e ctxt.Executor, spec *
 		}
 	}
 
-	return nil
+	return i.overrideRules(ctx, e, paths)
 }
 
 // ScaleConfig deploy temporary config on scaling
@@ -0,0 +1,188 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/gofrs/flock"
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/tui"
+	"github.com/pingcap/tiup/pkg/utils"
+	"go.uber.org/zap"
+)
+
+// OperationLockName is the lock file preventing concurrent operations on a cluster
+const OperationLockName = ".operation.lock"
+
+// ErrOperationLocked is the error when the cluster is being operated by others
+var ErrOperationLocked = errNS.NewType("operation_locked")
+
+// OperationLock describes who is operating the cluster
+type OperationLock struct {
+	User    string    `json:"user"`
+	Host    string    `json:"host"`
+	PID     int       `json:"pid"`
+	Command string    `json:"command"`
+	Since   time.Time `json:"since"`
+}
+
+// NewOperationLock returns the lock of the current process running command
+func NewOperationLock(command string) OperationLock {
+	host, _ := os.Hostname()
+	return OperationLock{
+		User:    utils.CurrentUser(),
+		Host:    host,
+		PID:     os.Getpid(),
+		Command: command,
+		Since:   time.Now(),
+	}
+}
+
+// String implements the fmt.Stringer interface
+func (l OperationLock) String() string {
+	return fmt.Sprintf("%s@%s (pid %d) running `%s` since %s",
+		l.User, l.Host, l.PID, l.Command, l.Since.Format(time.RFC3339))
+}
+
+// IsOwned checks if the lock is held by the current process
+func (l OperationLock) IsOwned() bool {
+	host, _ := os.Hostname()
+	return l.Host == host && l.PID == os.Getpid()
+}
+
+// IsStale checks if the holder of the lock is a process on this machine and
+// it has exited. Locks held on other machines can not be checked.
+func (l OperationLock) IsStale() bool {
+	host, _ := os.Hostname()
+	if l.Host != host || l.PID <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(l.PID)
+	if err != nil {
+		return true
+	}
+	err = p.Signal(syscall.Signal(0))
+	return err != nil && !errors.Is(err, syscall.EPERM)
+}
+
+// AcquireOperationLock locks the cluster for the operation, a stale lock
+// left by an exited process is taken over. It is not an error if the lock
+// is already held by the current process.
+func (s *SpecManager) AcquireOperationLock(clusterName string, lock OperationLock) error {
+	if err := s.ensureDir(clusterName); err != nil {
+		return err
+	}
+	// the guard is shared by the clusters, so that it's not left in the
+	// directories of the clusters which do not exist
+	guard := filepath.Join(s.base, OperationLockName+".guard")
+	holder, err := AcquireLockFile(s.Path(clusterName, OperationLockName), guard, lock, 0644)
+	if err != nil || holder == nil || holder.IsOwned() {
+		return err
+	}
+	return ErrOperationLocked.New("Cluster '%s' is being operated by %s", clusterName, holder).
+		WithProperty(tui.SuggestionFromFormat(
+			"Please wait for the operation to finish, or run `%s lock release %s` if it is not running anymore.",
+			tui.OsArgs0(), clusterName))
+}
+
+// OperationLock returns the lock of the cluster, nil is returned if the
+// cluster is not locked
+func (s *SpecManager) OperationLock(clusterName string) (*OperationLock, error) {
+	lock, err := ReadLockFile(s.Path(clusterName, OperationLockName))
+	if err != nil {
+		return nil, perrs.Annotatef(err, "read operation lock of cluster %s", clusterName)
+	}
+	return lock, nil
+}
+
+// AcquireLockFile writes the lock to the file at path if it is not held,
+// a stale lock left by an exited process is taken over. The holder is
+// returned if it is held, including by the current process. The file guard is locked while
+// checking and taking over the lock so that only one process wins, and the
+// lock file is replaced by renaming so that it's never read partially.
+func AcquireLockFile(path, guard string, lock OperationLock, perm os.FileMode) (*OperationLock, error) {
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return nil, perrs.AddStack(err)
+	}
+
+	fl := flock.New(guard)
+	if err := fl.Lock(); err != nil {
+		return nil, perrs.Annotatef(err, "lock %s", guard)
+	}
+	defer func() { _ = fl.Unlock() }()
+
+	holder, err := ReadLockFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if holder != nil {
+		if !holder.IsStale() {
+			return holder, nil
+		}
+		zap.L().Warn("Take over stale lock", zap.String("path", path), zap.Stringer("holder", holder))
+	}
+
+	tmp := fmt.Sprintf("%s.%d.tmp", path, os.Getpid())
+	if err := os.WriteFile(tmp, data, perm); err != nil {
+		return nil, perrs.AddStack(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return nil, perrs.AddStack(err)
+	}
+	return nil, nil
+}
+
+// ReadLockFile returns the lock in the file at path, nil is returned if it
+// does not exist. The file may be written partially by the versions which
+// do not write it by renaming, so it's read again for a while if invalid.
+func ReadLockFile(path string) (*OperationLock, error) {
+	var err error
+	for i := 0; i < 5; i++ {
+		if i > 0 {
+			time.Sleep(20 * time.Millisecond)
+		}
+		var data []byte
+		data, err = os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, perrs.AddStack(err)
+		}
+		lock := &OperationLock{}
+		if err = json.Unmarshal(data, lock); err == nil {
+			return lock, nil
+		}
+	}
+	return nil, perrs.Annotatef(err, "parse lock file %s", path)
+}
+
+// ReleaseOperationLock removes the lock of the cluster, it is not an error
+// if the cluster is not locked
+func (s *SpecManager) ReleaseOperationLock(clusterName string) error {
+	err := os.Remove(s.Path(clusterName, OperationLockName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return perrs.AddStack(err)
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joomcode/errorx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationLock(t *testing.T) {
+	s := NewSpec(t.TempDir(), func() Metadata {
+		return new(TestMetadata)
+	})
+
+	holder, err := s.OperationLock("test")
+	require.NoError(t, err)
+	require.Nil(t, holder)
+
+	lock := NewOperationLock("tiup-cluster upgrade test v8.5.0")
+	require.NoError(t, s.AcquireOperationLock("test", lock))
+	holder, err = s.OperationLock("test")
+	require.NoError(t, err)
+	require.Equal(t, lock.Command, holder.Command)
+	require.True(t, holder.IsOwned())
+	require.False(t, holder.IsStale())
+
+	// it is re-entrant in the same process
+	require.NoError(t, s.AcquireOperationLock("test", NewOperationLock("tiup-cluster reload test")))
+
+	// held by a process on another machine
+	other := lock
+	other.Host = "other-" + lock.Host
+	require.NoError(t, s.ReleaseOperationLock("test"))
+	require.NoError(t, s.AcquireOperationLock("test", other))
+	err = s.AcquireOperationLock("test", lock)
+	require.True(t, errorx.IsOfType(err, ErrOperationLocked))
+	require.Contains(t, err.Error(), "tiup-cluster upgrade test v8.5.0")
+
+	// held by an exited process on this machine
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	stale := lock
+	stale.PID = cmd.Process.Pid
+	require.True(t, stale.IsStale())
+	require.NoError(t, s.ReleaseOperationLock("test"))
+	require.NoError(t, s.AcquireOperationLock("test", stale))
+	require.NoError(t, s.AcquireOperationLock("test", lock))
+	holder, err = s.OperationLock("test")
+	require.NoError(t, err)
+	require.True(t, holder.IsOwned())
+
+	require.NoError(t, s.ReleaseOperationLock("test"))
+	require.NoError(t, s.ReleaseOperationLock("test"))
+	holder, err = s.OperationLock("test")
+	require.NoError(t, err)
+	require.Nil(t, holder)
+}
+
+func TestAcquireLockFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.lock")
+	guard := filepath.Join(dir, "guard")
+
+	// a stale lock is taken over by only one of the processes
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	require.NoError(t, cmd.Run())
+	stale := NewOperationLock("tiup-cluster upgrade test")
+	stale.PID = cmd.Process.Pid
+	holder, err := AcquireLockFile(path, guard, stale, 0644)
+	require.NoError(t, err)
+	require.Nil(t, holder)
+
+	var wg sync.WaitGroup
+	var acquired atomic.Int32
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			lock := NewOperationLock(fmt.Sprintf("tiup-cluster reload test %d", i))
+			lock.Host = "other-" + lock.Host
+			holder, err := AcquireLockFile(path, guard, lock, 0644)
+			assert.NoError(t, err)
+			if holder == nil {
+				acquired.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), acquired.Load())
+
+	// the lock file written partially is read again
+	require.NoError(t, os.WriteFile(path, nil, 0644))
+	_, err = ReadLockFile(path)
+	require.Error(t, err)
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		data, _ := json.Marshal(stale)
+		_ = os.WriteFile(path, data, 0644)
+	}()
+	holder, err = ReadLockFile(path)
+	require.NoError(t, err)
+	require.Equal(t, stale.Command, holder.Command)
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"net"
+	"sort"
+	"strings"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/set"
+)
+
+// PlacementSpec represents the rules to generate TiKV location labels
+// from the host an instance is placed on, e.g.:
+//
+//	placement:
+//	  host_label: host
+//	  rules:
+//	    - labels: { zone: z1, rack: r1 }
+//	      hosts: [ 10.0.1.1, 10.0.1.2 ]
+//	    - labels: { zone: z2 }
+//	      cidrs: [ 10.0.2.0/24 ]
+type PlacementSpec struct {
+	// HostLabel is the label name to be filled with the host of instance
+	HostLabel string          `yaml:"host_label,omitempty"`
+	Rules     []PlacementRule `yaml:"rules,omitempty"`
+}
+
+// PlacementRule maps a group of hosts to location labels
+type PlacementRule struct {
+	Hosts  []string          `yaml:"hosts,omitempty"`
+	CIDRs  []string          `yaml:"cidrs,omitempty"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+// IsEmpty returns true if there is no placement rule defined
+func (p *PlacementSpec) IsEmpty() bool {
+	return p.HostLabel == "" && len(p.Rules) == 0
+}
+
+// Match returns true if the host belongs to the group of the rule
+func (r *PlacementRule) Match(host string) bool {
+	for _, h := range r.Hosts {
+		if h == host {
+			return true
+		}
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, c := range r.CIDRs {
+		if _, ipnet, err := net.ParseCIDR(c); err == nil && ipnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// LabelsOf returns the labels derived from placement rules for a host,
+// rules are applied in order and the latter ones overwrite the former ones.
+func (p *PlacementSpec) LabelsOf(host string) map[string]string {
+	lbs := make(map[string]string)
+	for _, r := range p.Rules {
+		if !r.Match(host) {
+			continue
+		}
+		for k, v := range r.Labels {
+			lbs[k] = v
+		}
+	}
+	if p.HostLabel != "" {
+		lbs[p.HostLabel] = host
+	}
+	return lbs
+}
+
+// Validate checks if the placement rules are valid, the label names must be
+// listed in location labels if any.
+func (p *PlacementSpec) Validate(locationLabels []string) error {
+	lbs := set.NewStringSet(locationLabels...)
+	checkName := func(name string) error {
+		if name == "" || strings.Contains(name, ".") {
+			return perrs.Errorf("placement: invalid label name '%s'", name)
+		}
+		if len(lbs) > 0 && !lbs.Exist(name) {
+			return perrs.Errorf("placement: label name '%s' is not specified in pd config (replication.location-labels: %v)", name, locationLabels)
+		}
+		return nil
+	}
+
+	if p.HostLabel != "" {
+		if err := checkName(p.HostLabel); err != nil {
+			return err
+		}
+	}
+	for i, r := range p.Rules {
+		if len(r.Hosts) == 0 && len(r.CIDRs) == 0 {
+			return perrs.Errorf("placement: rule #%d matches no host, either hosts or cidrs must be set", i)
+		}
+		if len(r.Labels) == 0 {
+			return perrs.Errorf("placement: rule #%d has no label", i)
+		}
+		for _, c := range r.CIDRs {
+			if _, _, err := net.ParseCIDR(c); err != nil {
+				return perrs.Annotatef(err, "placement: rule #%d has invalid cidr", i)
+			}
+		}
+		for k := range r.Labels {
+			if err := checkName(k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ApplyPlacementLabels fills `server.labels` of TiKV servers with labels
+// derived from placement rules, labels explicitly set in the instance config
+// take precedence over the generated ones.
+func (s *Specification) ApplyPlacementLabels() error {
+	if s.Placement.IsEmpty() {
+		return nil
+	}
+
+	for _, kv := range s.TiKVServers {
+		current, err := kv.Labels()
+		if err != nil {
+			return err
+		}
+		derived := s.Placement.LabelsOf(kv.Host)
+		keys := make([]string, 0, len(derived))
+		for k := range derived {
+			if _, ok := current[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		sort.Strings(keys)
+		if kv.Config == nil {
+			kv.Config = make(map[string]any)
+		}
+		for _, k := range keys {
+			kv.Config["server.labels."+k] = derived[k]
+		}
+	}
+	return nil
+}
+
+// validatePlacement checks the placement rules against location labels
+func (s *Specification) validatePlacement() error {
+	if s.Placement.IsEmpty() {
+		return nil
+	}
+	lbs, err := s.LocationLabels()
+	if err != nil {
+		return err
+	}
+	return s.Placement.Validate(lbs)
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPlacementLabels(t *testing.T) {
+	topo := Specification{}
+	err := yaml.Unmarshal([]byte(`
+server_configs:
+  pd:
+    replication.location-labels: ["zone", "rack", "host"]
+placement:
+  host_label: host
+  rules:
+    - labels: { zone: z1, rack: r1 }
+      hosts: [ 172.16.5.1 ]
+    - labels: { zone: z2 }
+      cidrs: [ 172.16.6.0/24 ]
+    - labels: { rack: r2 }
+      hosts: [ 172.16.6.2 ]
+tikv_servers:
+  - host: 172.16.5.1
+  - host: 172.16.6.2
+  - host: 172.16.6.3
+    config:
+      server.labels: { zone: z3 }
+  - host: 172.16.7.1
+`), &topo)
+	require.NoError(t, err)
+	require.NoError(t, topo.ApplyPlacementLabels())
+
+	expected := []map[string]string{
+		{"zone": "z1", "rack": "r1", "host": "172.16.5.1"},
+		{"zone": "z2", "rack": "r2", "host": "172.16.6.2"},
+		{"zone": "z3", "host": "172.16.6.3"},
+		{"host": "172.16.7.1"},
+	}
+	for i, kv := range topo.TiKVServers {
+		lbs, err := kv.Labels()
+		require.NoError(t, err)
+		require.Equal(t, expected[i], lbs)
+	}
+
+	// the generated labels pass the existing label check
+	lbs, err := topo.LocationLabels()
+	require.NoError(t, err)
+	require.NoError(t, CheckTiKVLabels(lbs, &topo))
+}
+
+func TestPlacementValidate(t *testing.T) {
+	topo := Specification{}
+	err := yaml.Unmarshal([]byte(`
+server_configs:
+  pd:
+    replication.location-labels: ["zone", "host"]
+placement:
+  rules:
+    - labels: { rack: r1 }
+      hosts: [ 172.16.5.1 ]
+tikv_servers:
+  - host: 172.16.5.1
+`), &topo)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "label name 'rack' is not specified")
+
+	topo = Specification{}
+	err = yaml.Unmarshal([]byte(`
+placement:
+  rules:
+    - labels: { zone: z1 }
+      cidrs: [ 172.16.5.0/33 ]
+tikv_servers:
+  - host: 172.16.5.1
+`), &topo)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid cidr")
+
+	topo = Specification{}
+	err = yaml.Unmarshal([]byte(`
+placement:
+  rules:
+    - labels: { zone: z1 }
+tikv_servers:
+  - host: 172.16.5.1
+`), &topo)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "matches no host")
+}
@@ -82,6 +82,12 @@ func Initialize(base string) error {
 	return utils.MkdirAll(profileDir, 0755)
 }
 
+// SetClusterBaseDir changes the directory of the cluster metadata, which is
+// the clusters dir in the profile dir by default.
+func SetClusterBaseDir(dir string) {
+	GetSpecManager().base = dir
+}
+
 // ProfileDir returns the full profile directory path of TiUP.
 func ProfileDir() string {
 	return profileDir
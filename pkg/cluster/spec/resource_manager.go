@@ -49,6 +49,7 @@ type ResourceManagerSpec struct {
 	Source    string         `yaml:"source,omitempty" validate:"source:editable"`
 	NumaNode  string         `yaml:"numa_node,omitempty" validate:"numa_node:editable"`
 	Config    map[string]any `yaml:"config,omitempty" validate:"config:ignore"`
+	Templates TemplateSpec   `yaml:"templates,omitempty" validate:"templates:editable"`
 	Arch      string         `yaml:"arch,omitempty"`
 	OS        string         `yaml:"os,omitempty"`
 }
@@ -238,7 +239,7 @@ func (i *ResourceManagerInstance) InitConfig(
 	}
 
 	fp := filepath.Join(paths.Cache, fmt.Sprintf("run_resource-manager_%s_%d.sh", i.GetHost(), i.GetPort()))
-	if err := cfg.ConfigToFile(fp); err != nil {
+	if err := i.renderRunScript(cfg, paths, fp); err != nil {
 		return err
 	}
 	dst := filepath.Join(paths.Deploy, "scripts", "run_resource-manager.sh")
@@ -49,6 +49,7 @@ type SchedulingSpec struct {
 	Source    string         `yaml:"source,omitempty" validate:"source:editable"`
 	NumaNode  string         `yaml:"numa_node,omitempty" validate:"numa_node:editable"`
 	Config    map[string]any `yaml:"config,omitempty" validate:"config:ignore"`
+	Templates TemplateSpec   `yaml:"templates,omitempty" validate:"templates:editable"`
 	Arch      string         `yaml:"arch,omitempty"`
 	OS        string         `yaml:"os,omitempty"`
 }
@@ -239,7 +240,7 @@ func (i *SchedulingInstance) InitConfig(
 	}
 
 	fp := filepath.Join(paths.Cache, fmt.Sprintf("run_scheduling_%s_%d.sh", i.GetHost(), i.GetPort()))
-	if err := cfg.ConfigToFile(fp); err != nil {
+	if err := i.renderRunScript(cfg, paths, fp); err != nil {
 		return err
 	}
 	dst := filepath.Join(paths.Deploy, "scripts", "run_scheduling.sh")
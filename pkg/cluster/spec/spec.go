@@ -181,6 +181,10 @@ type (
 		ComponentVersions      ComponentVersions      `yaml:"component_versions,omitempty" validate:"component_versions:editable"`
 		ComponentSources       ComponentSources       `yaml:"component_sources,omitempty" validate:"component_sources:editable"`
 		ServerConfigs          ServerConfigs          `yaml:"server_configs,omitempty" validate:"server_configs:ignore"`
+		Placement              PlacementSpec          `yaml:"placement,omitempty" validate:"placement:editable"`
+		Templates              ComponentTemplates     `yaml:"templates,omitempty" validate:"templates:editable"`
+		ExternalEndpoints      ExternalEndpoints      `yaml:"external_endpoints,omitempty" validate:"external_endpoints:ignore"`
+		AlertOverrides         AlertOverrideSpec      `yaml:"alert_overrides,omitempty" validate:"alert_overrides:ignore"`
 		TiDBServers            []*TiDBSpec            `yaml:"tidb_servers"`
 		TiKVServers            []*TiKVSpec            `yaml:"tikv_servers"`
 		TiKVWorkerServers      []*TiKVWorkerSpec      `yaml:"tikv_worker_servers,omitempty"`
@@ -281,6 +285,10 @@ func (s *Specification) NewPart() Topology {
 		MonitoredOptions:  s.MonitoredOptions,
 		ServerConfigs:     s.ServerConfigs,
 		ComponentVersions: s.ComponentVersions,
+		Placement:         s.Placement,
+		Templates:         s.Templates,
+		ExternalEndpoints: s.ExternalEndpoints,
+		AlertOverrides:    s.AlertOverrides,
 	}
 }
 
@@ -570,6 +578,10 @@ func (s *Specification) Merge(that Topology) Topology {
 		MonitoredOptions:       s.MonitoredOptions,
 		ServerConfigs:          s.ServerConfigs,
 		ComponentVersions:      s.ComponentVersions.Merge(spec.ComponentVersions),
+		Placement:              s.Placement,
+		Templates:              s.Templates,
+		ExternalEndpoints:      s.ExternalEndpoints,
+		AlertOverrides:         s.AlertOverrides,
 		TiDBServers:            append(s.TiDBServers, spec.TiDBServers...),
 		TiKVServers:            append(s.TiKVServers, spec.TiKVServers...),
 		TiKVWorkerServers:      append(s.TiKVWorkerServers, spec.TiKVWorkerServers...),
@@ -638,12 +650,16 @@ var (
 	serverConfigsTypeName     = reflect.TypeFor[ServerConfigs]().Name()
 	componentVersionsTypeName = reflect.TypeFor[ComponentVersions]().Name()
 	componentSourcesTypeName  = reflect.TypeFor[ComponentSources]().Name()
+	placementTypeName         = reflect.TypeFor[PlacementSpec]().Name()
+	templatesTypeName         = reflect.TypeFor[ComponentTemplates]().Name()
+	externalEndpointsTypeName = reflect.TypeFor[ExternalEndpoints]().Name()
+	alertOverridesTypeName    = reflect.TypeFor[AlertOverrideSpec]().Name()
 )
 
 // Skip global/monitored options
 func isSkipField(field reflect.Value) bool {
 	tp := field.Type().Name()
-	return tp == globalOptionTypeName || tp == monitorOptionTypeName || tp == serverConfigsTypeName || tp == componentVersionsTypeName || tp == componentSourcesTypeName
+	return tp == globalOptionTypeName || tp == monitorOptionTypeName || tp == serverConfigsTypeName || tp == componentVersionsTypeName || tp == componentSourcesTypeName || tp == placementTypeName || tp == templatesTypeName || tp == externalEndpointsTypeName || tp == alertOverridesTypeName
 }
 
 func setDefaultDir(parent, role, port string, field reflect.Value) {
@@ -0,0 +1,326 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"text/template"
+
+	perrs "github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/meta"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// TemplatesDirName is the directory in the cluster directory to keep the
+// copies of the overridden templates
+const TemplatesDirName = "templates"
+
+// TemplateSpec represents the local files overriding the built-in templates
+// of an instance. The run script and systemd unit are rendered with the same
+// data as the built-in templates, the env file is copied as is and loaded by
+// the systemd unit, e.g.:
+//
+//	templates:
+//	  run_script: /home/tidb/templates/run_tikv.sh.tpl
+//	  systemd_unit: /home/tidb/templates/tikv.service.tpl
+//	  env_file: /home/tidb/templates/tikv.env
+type TemplateSpec struct {
+	RunScript   string `yaml:"run_script,omitempty" validate:"run_script:editable"`
+	SystemdUnit string `yaml:"systemd_unit,omitempty" validate:"systemd_unit:editable"`
+	EnvFile     string `yaml:"env_file,omitempty" validate:"env_file:editable"`
+}
+
+// ComponentTemplates represents the template overrides of each component
+type ComponentTemplates struct {
+	TiDB            TemplateSpec `yaml:"tidb,omitempty" validate:"tidb:editable"`
+	TiKV            TemplateSpec `yaml:"tikv,omitempty" validate:"tikv:editable"`
+	TiKVWorker      TemplateSpec `yaml:"tikv_worker,omitempty" validate:"tikv_worker:editable"`
+	TiFlash         TemplateSpec `yaml:"tiflash,omitempty" validate:"tiflash:editable"`
+	PD              TemplateSpec `yaml:"pd,omitempty" validate:"pd:editable"`
+	TSO             TemplateSpec `yaml:"tso,omitempty" validate:"tso:editable"`
+	Scheduling      TemplateSpec `yaml:"scheduling,omitempty" validate:"scheduling:editable"`
+	ResourceManager TemplateSpec `yaml:"resource_manager,omitempty" validate:"resource_manager:editable"`
+	Router          TemplateSpec `yaml:"router,omitempty" validate:"router:editable"`
+	Dashboard       TemplateSpec `yaml:"tidb_dashboard,omitempty" validate:"tidb_dashboard:editable"`
+	Pump            TemplateSpec `yaml:"pump,omitempty" validate:"pump:editable"`
+	Drainer         TemplateSpec `yaml:"drainer,omitempty" validate:"drainer:editable"`
+	CDC             TemplateSpec `yaml:"cdc,omitempty" validate:"cdc:editable"`
+	TiKVCDC         TemplateSpec `yaml:"kvcdc,omitempty" validate:"kvcdc:editable"`
+	TiProxy         TemplateSpec `yaml:"tiproxy,omitempty" validate:"tiproxy:editable"`
+	Prometheus      TemplateSpec `yaml:"prometheus,omitempty" validate:"prometheus:editable"`
+	Grafana         TemplateSpec `yaml:"grafana,omitempty" validate:"grafana:editable"`
+	AlertManager    TemplateSpec `yaml:"alertmanager,omitempty" validate:"alertmanager:editable"`
+}
+
+// Of returns the template overrides of the component
+func (t *ComponentTemplates) Of(comp string) TemplateSpec {
+	switch comp {
+	case ComponentTiDB:
+		return t.TiDB
+	case ComponentTiKV:
+		return t.TiKV
+	case ComponentTiKVWorker:
+		return t.TiKVWorker
+	case ComponentTiFlash:
+		return t.TiFlash
+	case ComponentPD:
+		return t.PD
+	case ComponentTSO:
+		return t.TSO
+	case ComponentScheduling:
+		return t.Scheduling
+	case ComponentResourceManager:
+		return t.ResourceManager
+	case ComponentRouter:
+		return t.Router
+	case ComponentDashboard:
+		return t.Dashboard
+	case ComponentPump:
+		return t.Pump
+	case ComponentDrainer:
+		return t.Drainer
+	case ComponentCDC:
+		return t.CDC
+	case ComponentTiKVCDC:
+		return t.TiKVCDC
+	case ComponentTiProxy:
+		return t.TiProxy
+	case ComponentPrometheus:
+		return t.Prometheus
+	case ComponentGrafana:
+		return t.Grafana
+	case ComponentAlertmanager:
+		return t.AlertManager
+	}
+	return TemplateSpec{}
+}
+
+// IsEmpty returns true if no template is overridden
+func (t TemplateSpec) IsEmpty() bool {
+	return t == TemplateSpec{}
+}
+
+// MergeTemplateSpec merges the component level and instance level template
+// overrides, the instance level ones take precedence
+func MergeTemplateSpec(comp, ins TemplateSpec) TemplateSpec {
+	if ins.RunScript != "" {
+		comp.RunScript = ins.RunScript
+	}
+	if ins.SystemdUnit != "" {
+		comp.SystemdUnit = ins.SystemdUnit
+	}
+	if ins.EnvFile != "" {
+		comp.EnvFile = ins.EnvFile
+	}
+	return comp
+}
+
+// templateFiles returns the (field, path) pairs of the overridden templates
+func (t TemplateSpec) templateFiles() [][2]string {
+	files := make([][2]string, 0, 3)
+	for _, f := range [][2]string{
+		{"run_script", t.RunScript},
+		{"systemd_unit", t.SystemdUnit},
+		{"env_file", t.EnvFile},
+	} {
+		if f[1] != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// validate checks the paths of the overridden templates are absolute
+func (t TemplateSpec) validate(owner string) error {
+	for _, f := range t.templateFiles() {
+		if !filepath.IsAbs(f[1]) {
+			return perrs.Errorf("relative path is not allowed for templates.%s of %s: %s", f[0], owner, f[1])
+		}
+	}
+	return nil
+}
+
+// Check checks the overridden templates exist and can be parsed
+func (t TemplateSpec) Check() error {
+	for _, f := range t.templateFiles() {
+		data, err := os.ReadFile(f[1])
+		if err != nil {
+			return perrs.Annotatef(err, "read templates.%s", f[0])
+		}
+		if f[0] == "env_file" {
+			continue
+		}
+		if _, err := template.New(f[0]).Parse(string(data)); err != nil {
+			return perrs.Annotatef(err, "parse templates.%s %s", f[0], f[1])
+		}
+	}
+	return nil
+}
+
+// Templates returns the template overrides of the instance, with the
+// component level overrides of the topology merged
+func (i *BaseInstance) Templates() TemplateSpec {
+	var ins TemplateSpec
+	if v := reflect.Indirect(reflect.ValueOf(i.InstanceSpec)).FieldByName("Templates"); v.IsValid() {
+		ins, _ = v.Interface().(TemplateSpec)
+	}
+
+	var comp TemplateSpec
+	if i.Component != nil {
+		if v := reflect.Indirect(reflect.ValueOf(i.Component)).FieldByName("Topology"); v.IsValid() {
+			if topo, ok := v.Interface().(*Specification); ok && topo != nil {
+				comp = topo.Templates.Of(i.ComponentName())
+			}
+		}
+	}
+	return MergeTemplateSpec(comp, ins)
+}
+
+// localTemplates returns the template overrides of the instance with the
+// paths replaced by their copies in the cluster directory, the cache dir of
+// configs is in the cluster directory too. The paths without a copy are kept
+// as is, e.g. the ones of a cluster deployed before the copies are made.
+func (i *BaseInstance) localTemplates(paths meta.DirPaths) TemplateSpec {
+	dir := filepath.Join(filepath.Dir(paths.Cache), TemplatesDirName)
+	tpls := i.Templates()
+	for _, p := range []*string{&tpls.RunScript, &tpls.SystemdUnit, &tpls.EnvFile} {
+		if *p == "" {
+			continue
+		}
+		if local := filepath.Join(dir, templateCopyName(*p)); utils.IsExist(local) {
+			*p = local
+		}
+	}
+	return tpls
+}
+
+// templateCopyName returns the file name of the copy of a template, it is
+// unique for each path of the template
+func templateCopyName(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return fmt.Sprintf("%x-%s", sum[:8], filepath.Base(path))
+}
+
+// CopyTemplates copies the overridden templates used by the instances of the
+// topology to dir, so that the instances are rendered with the copies later
+// instead of reading the local paths again, which may be changed or removed.
+func (s *Specification) CopyTemplates(dir string) error {
+	var err error
+	s.IterInstance(func(ins Instance) {
+		if err != nil {
+			return
+		}
+		bi, ok := ins.(interface{ Templates() TemplateSpec })
+		if !ok {
+			return
+		}
+		for _, f := range bi.Templates().templateFiles() {
+			local := filepath.Join(dir, templateCopyName(f[1]))
+			// keep the copy if the template is removed from the local path
+			if !utils.IsExist(f[1]) && utils.IsExist(local) {
+				continue
+			}
+			if err = utils.MkdirAll(dir, 0755); err != nil {
+				return
+			}
+			if e := utils.Copy(f[1], local); e != nil {
+				err = perrs.Annotatef(e, "copy templates.%s of %s", f[0], ins.ID())
+				return
+			}
+		}
+	})
+	return err
+}
+
+// scriptConfig is the data used to render a run script
+type scriptConfig interface {
+	ConfigToFile(file string) error
+}
+
+// renderRunScript renders the run script of the instance to file, with the
+// overridden template if set, otherwise with the built-in one
+func (i *BaseInstance) renderRunScript(cfg scriptConfig, paths meta.DirPaths, file string) error {
+	tpl := i.localTemplates(paths).RunScript
+	if tpl == "" {
+		return cfg.ConfigToFile(file)
+	}
+	content, err := renderTemplateFile(tpl, cfg)
+	if err != nil {
+		return err
+	}
+	return utils.WriteFile(file, content, 0755)
+}
+
+// renderTemplateFile renders a local template file with data
+func renderTemplateFile(path string, data any) ([]byte, error) {
+	tpl, err := os.ReadFile(path)
+	if err != nil {
+		return nil, perrs.Annotatef(err, "read template %s", path)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Parse(string(tpl))
+	if err != nil {
+		return nil, perrs.Annotatef(err, "parse template %s", path)
+	}
+	content := bytes.NewBufferString("")
+	if err := tmpl.Execute(content, data); err != nil {
+		return nil, perrs.Annotatef(err, "render template %s", path)
+	}
+	return content.Bytes(), nil
+}
+
+// validateTemplates checks the component level and instance level template
+// overrides of the topology
+func (s *Specification) validateTemplates() error {
+	v := reflect.ValueOf(s.Templates)
+	for i := 0; i < v.NumField(); i++ {
+		comp := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if err := v.Field(i).Interface().(TemplateSpec).validate(comp); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	s.IterInstance(func(ins Instance) {
+		if err != nil {
+			return
+		}
+		if bi, ok := ins.(interface{ Templates() TemplateSpec }); ok {
+			err = bi.Templates().validate(ins.ID())
+		}
+	})
+	return err
+}
+
+// CheckTemplates checks all the template overrides used by the instances
+// of the topology exist on the local host and can be parsed
+func (s *Specification) CheckTemplates() error {
+	var err error
+	s.IterInstance(func(ins Instance) {
+		if err != nil {
+			return
+		}
+		if bi, ok := ins.(interface{ Templates() TemplateSpec }); ok {
+			if e := bi.Templates().Check(); e != nil {
+				err = perrs.Annotatef(e, "check templates of %s", ins.ID())
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/cluster/template/scripts"
+	system "github.com/pingcap/tiup/pkg/cluster/template/systemd"
+	"github.com/pingcap/tiup/pkg/meta"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestTemplatesMerge(t *testing.T) {
+	topo := Specification{}
+	err := yaml.Unmarshal([]byte(`
+templates:
+  tikv:
+    run_script: /tpl/run_tikv.sh.tpl
+    env_file: /tpl/tikv.env
+tikv_servers:
+  - host: 172.16.5.1
+  - host: 172.16.5.2
+    templates:
+      run_script: /tpl/run_tikv_numa.sh.tpl
+      systemd_unit: /tpl/tikv.service.tpl
+tidb_servers:
+  - host: 172.16.5.1
+`), &topo)
+	require.NoError(t, err)
+
+	tpls := make(map[string]TemplateSpec)
+	topo.IterInstance(func(ins Instance) {
+		tpls[ins.ID()] = ins.(interface{ Templates() TemplateSpec }).Templates()
+	})
+	require.Equal(t, TemplateSpec{
+		RunScript: "/tpl/run_tikv.sh.tpl",
+		EnvFile:   "/tpl/tikv.env",
+	}, tpls["172.16.5.1:20160"])
+	require.Equal(t, TemplateSpec{
+		RunScript:   "/tpl/run_tikv_numa.sh.tpl",
+		SystemdUnit: "/tpl/tikv.service.tpl",
+		EnvFile:     "/tpl/tikv.env",
+	}, tpls["172.16.5.2:20160"])
+	require.True(t, tpls["172.16.5.1:4000"].IsEmpty())
+
+	// the component level templates are kept when scaling out
+	newPart := topo.NewPart().(*Specification)
+	require.Equal(t, topo.Templates, newPart.Templates)
+}
+
+func TestTemplatesValidate(t *testing.T) {
+	topo := Specification{}
+	err := yaml.Unmarshal([]byte(`
+templates:
+  tikv:
+    run_script: run_tikv.sh.tpl
+tikv_servers:
+  - host: 172.16.5.1
+`), &topo)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "relative path is not allowed for templates.run_script of tikv")
+
+	topo = Specification{}
+	err = yaml.Unmarshal([]byte(`
+tikv_servers:
+  - host: 172.16.5.1
+    templates:
+      env_file: tikv.env
+`), &topo)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "templates.env_file of 172.16.5.1:20160")
+}
+
+func TestTemplatesCheckAndRender(t *testing.T) {
+	dir := t.TempDir()
+	runScript := filepath.Join(dir, "run_tidb.sh.tpl")
+	unit := filepath.Join(dir, "tidb.service.tpl")
+	require.NoError(t, os.WriteFile(runScript, []byte("exec taskset -c 0-3 {{.DeployDir}}/bin/tidb-server -P {{.Port}}\n"), 0644))
+	require.NoError(t, os.WriteFile(unit, []byte("ExecStart={{.DeployDir}}/scripts/run_{{.ServiceName}}.sh\nEnvironmentFile={{.EnvironmentFile}}\n"), 0644))
+
+	topo := Specification{}
+	err := yaml.Unmarshal(fmt.Appendf(nil, `
+tidb_servers:
+  - host: 172.16.5.1
+    templates:
+      run_script: %s
+      systemd_unit: %s
+`, runScript, unit), &topo)
+	require.NoError(t, err)
+	require.NoError(t, topo.CheckTemplates())
+
+	var tidb *TiDBInstance
+	for _, comp := range topo.ComponentsByStartOrder() {
+		if comp.Name() == ComponentTiDB {
+			tidb = comp.Instances()[0].(*TiDBInstance)
+		}
+	}
+	require.NotNil(t, tidb)
+
+	paths := meta.DirPaths{Cache: filepath.Join(dir, "cluster", TempConfigPath)}
+	fp := filepath.Join(dir, "run_tidb.sh")
+	require.NoError(t, tidb.renderRunScript(&scripts.TiDBScript{Port: 4000, DeployDir: "/deploy"}, paths, fp))
+	content, err := os.ReadFile(fp)
+	require.NoError(t, err)
+	require.Equal(t, "exec taskset -c 0-3 /deploy/bin/tidb-server -P 4000\n", string(content))
+
+	// the copies in the cluster directory are used once made
+	require.NoError(t, topo.CopyTemplates(filepath.Join(dir, "cluster", TemplatesDirName)))
+	require.NoError(t, os.WriteFile(runScript, []byte("exec {{.DeployDir}}/bin/tidb-server\n"), 0644))
+	require.NoError(t, tidb.renderRunScript(&scripts.TiDBScript{Port: 4000, DeployDir: "/deploy"}, paths, fp))
+	content, err = os.ReadFile(fp)
+	require.NoError(t, err)
+	require.Equal(t, "exec taskset -c 0-3 /deploy/bin/tidb-server -P 4000\n", string(content))
+	require.Equal(t, filepath.Join(dir, "cluster", TemplatesDirName, templateCopyName(unit)), tidb.localTemplates(paths).SystemdUnit)
+
+	// the copies are kept if the templates are removed from the local paths
+	require.NoError(t, os.Rename(unit, unit+".bak"))
+	require.NoError(t, topo.CopyTemplates(filepath.Join(dir, "cluster", TemplatesDirName)))
+	require.NoError(t, os.Rename(unit+".bak", unit))
+
+	cfg := system.NewConfig("tidb", "tidb", "/deploy").WithEnvironmentFile("/deploy/scripts/tidb.env")
+	content, err = renderTemplateFile(unit, cfg)
+	require.NoError(t, err)
+	require.Equal(t, "ExecStart=/deploy/scripts/run_tidb.sh\nEnvironmentFile=/deploy/scripts/tidb.env\n", string(content))
+
+	// the built-in unit loads the env file too
+	content, err = cfg.WithSystemdMode(string(SystemMode)).Config()
+	require.NoError(t, err)
+	require.Contains(t, string(content), "EnvironmentFile=-/deploy/scripts/tidb.env\n")
+
+	// templates failed to parse are rejected
+	require.NoError(t, os.WriteFile(runScript, []byte("{{.Port"), 0644))
+	err = topo.CheckTemplates()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "parse templates.run_script")
+
+	require.NoError(t, os.Remove(unit))
+	require.NoError(t, os.WriteFile(runScript, []byte("{{.Port}}"), 0644))
+	err = topo.CheckTemplates()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "read templates.systemd_unit")
+}
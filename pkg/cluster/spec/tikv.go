@@ -65,6 +65,7 @@ type TiKVSpec struct {
 	NumaCores           string               `yaml:"numa_cores,omitempty" validate:"numa_cores:editable"`
 	Config              map[string]any       `yaml:"config,omitempty" validate:"config:ignore"`
 	ResourceControl     meta.ResourceControl `yaml:"resource_control,omitempty" validate:"resource_control:editable"`
+	Templates           TemplateSpec         `yaml:"templates,omitempty" validate:"templates:editable"`
 	Arch                string               `yaml:"arch,omitempty"`
 	OS                  string               `yaml:"os,omitempty"`
 }
@@ -273,7 +274,7 @@ func (i *TiKVInstance) InitConfig(
 	}
 
 	fp := filepath.Join(paths.Cache, fmt.Sprintf("run_tikv_%s_%d.sh", i.GetHost(), i.GetPort()))
-	if err := cfg.ConfigToFile(fp); err != nil {
+	if err := i.renderRunScript(cfg, paths, fp); err != nil {
 		return err
 	}
 	dst := filepath.Join(paths.Deploy, "scripts", "run_tikv.sh")
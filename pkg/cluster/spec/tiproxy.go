@@ -37,6 +37,7 @@ type TiProxySpec struct {
 	DeployDir  string         `yaml:"deploy_dir,omitempty"`
 	NumaNode   string         `yaml:"numa_node,omitempty" validate:"numa_node:editable"`
 	Config     map[string]any `yaml:"config,omitempty" validate:"config:ignore"`
+	Templates  TemplateSpec   `yaml:"templates,omitempty" validate:"templates:editable"`
 	Arch       string         `yaml:"arch,omitempty"`
 	OS         string         `yaml:"os,omitempty"`
 }
@@ -214,7 +215,7 @@ func (i *TiProxyInstance) InitConfig(
 
 	fp := filepath.Join(paths.Cache, fmt.Sprintf("run_tiproxy_%s_%d.sh", i.GetHost(), i.GetPort()))
 
-	if err := cfg.ConfigToFile(fp); err != nil {
+	if err := i.renderRunScript(cfg, paths, fp); err != nil {
 		return err
 	}
 	dst := filepath.Join(paths.Deploy, "scripts", "run_tiproxy.sh")
@@ -49,6 +49,34 @@ type ClusterMeta struct {
 	OpsVer string `yaml:"last_ops_ver,omitempty"` // the version of ourself that updated the meta last time
 
 	Topology *Specification `yaml:"topology"`
+
+	// Maintenance is the maintenance window of the cluster, nil if the
+	// cluster is not in maintenance mode
+	Maintenance *MaintenanceWindow `yaml:"maintenance,omitempty"`
+}
+
+// MaintenanceWindow records what is changed when the cluster enters the
+// maintenance mode, so that they can be reverted when it is turned off
+type MaintenanceWindow struct {
+	Start   time.Time `yaml:"start" json:"start"`
+	End     time.Time `yaml:"end" json:"end"`
+	Nodes   []string  `yaml:"nodes,omitempty" json:"nodes,omitempty"`
+	Comment string    `yaml:"comment,omitempty" json:"comment,omitempty"`
+	// Silences are the IDs of the silences created in Alertmanager
+	Silences []string `yaml:"silences,omitempty" json:"silences,omitempty"`
+	// PausedSchedulers are the PD schedulers paused
+	PausedSchedulers []string `yaml:"paused_schedulers,omitempty" json:"paused_schedulers,omitempty"`
+	// StoreLimit is the store limit set, OriginStoreLimits are the limits of
+	// the stores keyed by store IDs, they are restored when the maintenance
+	// mode is turned off
+	StoreLimit        int                              `yaml:"store_limit,omitempty" json:"store_limit,omitempty"`
+	OriginStoreLimits map[string]MaintenanceStoreLimit `yaml:"origin_store_limits,omitempty" json:"origin_store_limits,omitempty"`
+}
+
+// MaintenanceStoreLimit is the limit of a store before the maintenance
+type MaintenanceStoreLimit struct {
+	AddPeer    float64 `yaml:"add_peer" json:"add_peer"`
+	RemovePeer float64 `yaml:"remove_peer" json:"remove_peer"`
 }
 
 var _ UpgradableMetadata = &ClusterMeta{}
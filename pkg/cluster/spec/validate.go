@@ -1072,6 +1072,35 @@ func (s *Specification) validatePrometheusExternalLabels() error {
 	return nil
 }
 
+// ValidateResourceControl checks the global, monitored and instance level
+// resource_control of the topology
+func ValidateResourceControl(topo Topology) error {
+	base := topo.BaseTopo()
+	if err := base.GlobalOptions.ResourceControl.Validate(); err != nil {
+		return errors.Annotate(err, "global")
+	}
+	if base.MonitoredOptions != nil {
+		if err := base.MonitoredOptions.ResourceControl.Validate(); err != nil {
+			return errors.Annotate(err, "monitored")
+		}
+	}
+
+	var err error
+	topo.IterInstance(func(ins Instance) {
+		if err != nil {
+			return
+		}
+		if e := ins.ResourceControl().Validate(); e != nil {
+			err = errors.Annotate(e, ins.ID())
+		}
+	})
+	return err
+}
+
+func (s *Specification) validateResourceControl() error {
+	return ValidateResourceControl(s)
+}
+
 // validateMonitorAgent checks for conflicts in topology for different ignore_exporter
 // settings for multiple instances on the same host / IP
 func (s *Specification) validateMonitorAgent() error {
@@ -1145,6 +1174,13 @@ func (s *Specification) Validate() error {
 		s.validateTiFlashConfigs,
 		s.validatePrometheusExternalLabels,
 		s.validateMonitorAgent,
+		s.validatePlacement,
+		s.validateTemplates,
+		s.validateResourceControl,
+		s.validateExternalEndpoints,
+		s.validateAlertOverrides,
+		s.validateGrafanaProvisioning,
+		s.validateFederation,
 	}
 
 	for _, v := range validators {
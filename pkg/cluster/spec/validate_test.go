@@ -1226,3 +1226,26 @@ tikv_servers:
 		require.Equal(t, "spec.deploy.dir_overlap: Deploy directory overlaps to another instance", err.Error())
 	}
 }
+
+func TestInvalidResourceControl(t *testing.T) {
+	topo := Specification{}
+	err := yaml.Unmarshal([]byte(`
+global:
+  resource_control:
+    protect_system: readonly
+tidb_servers:
+  - host: 172.16.5.138
+`), &topo)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "global: invalid protect_system 'readonly'")
+
+	topo = Specification{}
+	err = yaml.Unmarshal([]byte(`
+tidb_servers:
+  - host: 172.16.5.138
+    resource_control:
+      oom_score_adjust: "-2000"
+`), &topo)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "172.16.5.138:4000: invalid oom_score_adjust '-2000'")
+}
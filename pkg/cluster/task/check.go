@@ -103,6 +103,9 @@ func (c *CheckSys) Execute(ctx context.Context) error {
 			// FIXME: set firewalld rules in deploy, and not disabling it anymore
 			operator.CheckServices(ctx, e, c.host, "firewalld", true, spec.SystemdMode(string(c.topo.BaseTopo().GlobalOptions.SystemdMode))),
 		)
+
+		// check if the resource_control directives are supported
+		results = append(results, operator.CheckSystemdDirectives(ctx, e, c.host, c.topo)...)
 		storeResults(ctx, c.host, results)
 	case CheckTypePackage: // check if a command present, and if a package installed
 		e, ok := ctxt.GetInner(ctx).GetExecutor(c.host)
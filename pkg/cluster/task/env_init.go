@@ -59,6 +59,13 @@ func (e *EnvInit) exec(ctx context.Context) error {
 	}
 
 	if !e.skipCreateUser {
+		// the user is removed on rollback only if it is created by us
+		if journalOf(ctx) != nil {
+			if _, _, err := exec.Execute(ctx, fmt.Sprintf("id -u %s > /dev/null 2>&1", e.deployUser), false); err != nil {
+				record(ctx, e.host, "remove user "+e.deployUser, e.removeUser)
+			}
+		}
+
 		um := module.NewUserModule(module.UserModuleConfig{
 			Action: module.UserActionAdd,
 			Name:   e.deployUser,
@@ -109,6 +116,19 @@ func (e *EnvInit) exec(ctx context.Context) error {
 	return nil
 }
 
+// removeUser removes the deploy user and its sudoers file
+func (e *EnvInit) removeUser(ctx context.Context, exec ctxt.Executor) error {
+	um := module.NewUserModule(module.UserModuleConfig{
+		Action: module.UserActionDel,
+		Name:   e.deployUser,
+	})
+	if _, _, err := um.Execute(ctx, exec); err != nil {
+		return err
+	}
+	_, _, err := exec.Execute(ctx, "rm -f /etc/sudoers.d/"+e.deployUser, true)
+	return err
+}
+
 // Rollback implements the Task interface
 func (e *EnvInit) Rollback(ctx context.Context) error {
 	return ErrUnsupportedRollback
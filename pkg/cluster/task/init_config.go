@@ -16,6 +16,7 @@ package task
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiup/pkg/cluster/ctxt"
@@ -47,7 +48,30 @@ func (c *InitConfig) Execute(ctx context.Context) error {
 		return errors.Annotatef(err, "create cache directory failed: %s", c.paths.Cache)
 	}
 
+	// the systemd unit files existing before are kept on rollback
+	var units []string
+	if journalOf(ctx) != nil {
+		service := fmt.Sprintf("%s-%d.service", c.instance.ComponentName(), c.instance.GetPort())
+		for _, unit := range []string{"/etc/systemd/system/" + service, "~/.config/systemd/user/" + service} {
+			if _, _, err := exec.Execute(ctx, "test -e "+unit, false); err != nil {
+				units = append(units, unit)
+			}
+		}
+	}
+
 	err := c.instance.InitConfig(ctx, exec, c.clusterName, c.clusterVersion, c.deployUser, c.paths)
+	for _, unit := range units {
+		if _, _, err := exec.Execute(ctx, "test -e "+unit, false); err == nil {
+			// the unit in system scope is owned by root, systemd is reloaded
+			// so that it forgets the removed unit
+			sudo, systemctl := true, "systemctl"
+			if !strings.HasPrefix(unit, "/") {
+				sudo, systemctl = false, "systemctl --user"
+			}
+			recordCommand(ctx, c.instance.GetManageHost(), "remove systemd unit "+unit,
+				fmt.Sprintf("rm -f %s && %s daemon-reload", unit, systemctl), sudo)
+		}
+	}
 	if err != nil {
 		if c.ignoreCheck && errors.Cause(err) == spec.ErrorCheckConfig {
 			return nil
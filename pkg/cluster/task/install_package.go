@@ -19,9 +19,11 @@ import (
 	"fmt"
 	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	"github.com/pingcap/tiup/pkg/set"
 )
 
 // InstallPackage is used to copy all files related the specific version a component
@@ -43,6 +45,15 @@ func (c *InstallPackage) Execute(ctx context.Context) error {
 	dstDir := filepath.Join(c.dstDir, "bin")
 	dstPath := filepath.Join(dstDir, path.Base(c.srcPath))
 
+	// the files existing before installing are kept on rollback
+	var existing []string
+	if journalOf(ctx) != nil {
+		var err error
+		if existing, err = listDir(ctx, exec, dstDir); err != nil {
+			return err
+		}
+	}
+
 	err := exec.Transfer(ctx, c.srcPath, dstPath, false, 0, false)
 	if err != nil {
 		return errors.Annotatef(err, "failed to scp %s to %s:%s", c.srcPath, c.host, dstPath)
@@ -57,9 +68,34 @@ func (c *InstallPackage) Execute(ctx context.Context) error {
 		}
 		return errors.Annotatef(err, "stderr: %s", string(stderr))
 	}
+
+	if journalOf(ctx) != nil {
+		installed, err := listDir(ctx, exec, dstDir)
+		if err != nil {
+			return err
+		}
+		old := set.NewStringSet(existing...)
+		for _, f := range installed {
+			if old.Exist(f) {
+				continue
+			}
+			f = filepath.Join(dstDir, f)
+			recordCommand(ctx, c.host, "remove installed "+f, fmt.Sprintf("rm -rf %s", f), false)
+		}
+	}
 	return nil
 }
 
+// listDir returns the names of entries in the dir, an empty list is
+// returned if the dir does not exist
+func listDir(ctx context.Context, exec ctxt.Executor, dir string) ([]string, error) {
+	stdout, stderr, err := exec.Execute(ctx, fmt.Sprintf("ls -A %[1]s 2>/dev/null || test ! -e %[1]s", dir), false)
+	if err != nil {
+		return nil, errors.Annotatef(err, "list %s, stderr: %s", dir, string(stderr))
+	}
+	return strings.Fields(string(stdout)), nil
+}
+
 // Rollback implements the Task interface
 func (c *InstallPackage) Rollback(ctx context.Context) error {
 	return ErrUnsupportedRollback
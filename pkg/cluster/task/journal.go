@@ -0,0 +1,122 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+)
+
+type journalKey struct{}
+
+// Journal records the changes made on remote hosts by tasks, so that the
+// completed steps of a failed operation can be undone in reverse order.
+// Tasks only record the changes if a journal is attached to the context.
+type Journal struct {
+	mu      sync.Mutex
+	entries []journalEntry
+}
+
+type journalEntry struct {
+	host string
+	desc string
+	undo func(ctx context.Context, e ctxt.Executor) error
+}
+
+// WithJournal attaches a new journal to the context
+func WithJournal(ctx context.Context) (context.Context, *Journal) {
+	j := &Journal{}
+	return context.WithValue(ctx, journalKey{}, j), j
+}
+
+// journalOf returns the journal attached to the context, nil if not exist
+func journalOf(ctx context.Context) *Journal {
+	j, _ := ctx.Value(journalKey{}).(*Journal)
+	return j
+}
+
+// CommitJournal drops all recorded changes of the journal attached to the
+// context, it is used when the changes are persisted to the meta and should
+// not be undone any more.
+func CommitJournal(ctx context.Context) {
+	if j := journalOf(ctx); j != nil {
+		j.mu.Lock()
+		j.entries = nil
+		j.mu.Unlock()
+	}
+}
+
+// record adds an undo function to the journal attached to the context
+func record(ctx context.Context, host, desc string, undo func(ctx context.Context, e ctxt.Executor) error) {
+	j := journalOf(ctx)
+	if j == nil {
+		return
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.entries = append(j.entries, journalEntry{host: host, desc: desc, undo: undo})
+}
+
+// recordCommand adds a shell command to undo a change to the journal
+func recordCommand(ctx context.Context, host, desc, cmd string, sudo bool) {
+	record(ctx, host, desc, func(ctx context.Context, e ctxt.Executor) error {
+		_, stderr, err := e.Execute(ctx, cmd, sudo)
+		if err != nil {
+			return errors.Annotatef(err, "stderr: %s", string(stderr))
+		}
+		return nil
+	})
+}
+
+// Len returns the count of recorded changes
+func (j *Journal) Len() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.entries)
+}
+
+// Rollback undoes the recorded changes in reverse order, it continues on
+// errors so that as much as possible is cleaned up, and returns all errors.
+func (j *Journal) Rollback(ctx context.Context) error {
+	j.mu.Lock()
+	entries := j.entries
+	j.entries = nil
+	j.mu.Unlock()
+
+	logger := ctx.Value(logprinter.ContextKeyLogger).(*logprinter.Logger)
+	var errs []string
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		logger.Infof("Rollback: %s on %s", entry.desc, entry.host)
+		e, found := ctxt.GetInner(ctx).GetExecutor(entry.host)
+		if !found {
+			errs = append(errs, fmt.Sprintf("%s on %s: %s", entry.desc, entry.host, ErrNoExecutor))
+			continue
+		}
+		if err := entry.undo(ctx, e); err != nil {
+			logger.Warnf("Rollback: failed to %s on %s: %s", entry.desc, entry.host, err)
+			errs = append(errs, fmt.Sprintf("%s on %s: %s", entry.desc, entry.host, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Errorf("failed to rollback %d changes:\n%s", len(errs), strings.Join(errs, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pingcap/tiup/pkg/cluster/ctxt"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDirExecutor emulates mkdir on a host with some existing directories
+type fakeDirExecutor struct {
+	mu       sync.Mutex
+	dirs     map[string]bool
+	commands []string
+}
+
+func (e *fakeDirExecutor) Execute(_ context.Context, cmd string, _ bool, _ ...time.Duration) ([]byte, []byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.commands = append(e.commands, cmd)
+	if dir, ok := strings.CutPrefix(cmd, "test -d "); ok {
+		dir, _, _ = strings.Cut(dir, " ")
+		if e.dirs[dir] {
+			return nil, nil, nil
+		}
+		e.dirs[dir] = true
+		return []byte("created\n"), nil, nil
+	}
+	if dir, ok := strings.CutPrefix(cmd, "rm -rf "); ok {
+		for d := range e.dirs {
+			if d == dir || strings.HasPrefix(d, dir+"/") {
+				delete(e.dirs, d)
+			}
+		}
+		return nil, nil, nil
+	}
+	if strings.HasPrefix(cmd, "systemctl ") {
+		return nil, nil, nil
+	}
+	return nil, nil, errors.New("unexpected command")
+}
+
+func (e *fakeDirExecutor) Transfer(_ context.Context, _, _ string, _ bool, _ int, _ bool) error {
+	return nil
+}
+
+func TestJournalRollback(t *testing.T) {
+	exec := &fakeDirExecutor{dirs: map[string]bool{"/data": true}}
+	ctx := ctxt.New(context.Background(), 0, logprinter.NewLogger(""))
+	ctxt.GetInner(ctx).SetExecutor("n1", exec)
+	ctx, journal := WithJournal(ctx)
+
+	err := NewBuilder(nil).
+		Mkdir("tidb", "n1", false, "/data/deploy/tidb-4000/bin", "/data/deploy/tidb-4000/log").
+		Mkdir("tidb", "n1", false, "/tidb-data").
+		Func("fail", func(context.Context) error { return errors.New("failed") }).
+		Build().Execute(ctx)
+	require.Error(t, err)
+	// only the top most created dir of each path is recorded
+	require.Equal(t, 3, journal.Len())
+
+	exec.commands = nil
+	require.NoError(t, journal.Rollback(ctx))
+	require.Equal(t, []string{
+		"rm -rf /tidb-data",
+		"rm -rf /data/deploy/tidb-4000/log",
+		"rm -rf /data/deploy",
+	}, exec.commands)
+	require.Equal(t, map[string]bool{"/data": true}, exec.dirs)
+	require.Equal(t, 0, journal.Len())
+
+	// nothing is recorded after commit
+	require.NoError(t, NewBuilder(nil).Mkdir("tidb", "n1", false, "/tidb-data").Build().Execute(ctx))
+	CommitJournal(ctx)
+	require.Equal(t, 0, journal.Len())
+}
+
+func TestJournalSystemCtl(t *testing.T) {
+	exec := &fakeDirExecutor{dirs: map[string]bool{}}
+	ctx := ctxt.New(context.Background(), 0, logprinter.NewLogger(""))
+	ctxt.GetInner(ctx).SetExecutor("n1", exec)
+	ctx, journal := WithJournal(ctx)
+
+	require.NoError(t, NewBuilder(nil).
+		SystemCtl("n1", "tidb-4000.service", "enable", true, false, "system").
+		SystemCtl("n1", "tidb-4000.service", "start", false, false, "system").
+		SystemCtl("n1", "tidb-4000.service", "restart", false, false, "system").
+		Build().Execute(ctx))
+	require.Equal(t, 2, journal.Len())
+
+	exec.commands = nil
+	require.NoError(t, journal.Rollback(ctx))
+	require.Equal(t, []string{
+		"systemctl stop tidb-4000.service",
+		"systemctl disable tidb-4000.service",
+	}, exec.commands)
+}
+
+func TestJournalNotAttached(t *testing.T) {
+	exec := &fakeDirExecutor{dirs: map[string]bool{}}
+	ctx := ctxt.New(context.Background(), 0, logprinter.NewLogger(""))
+	ctxt.GetInner(ctx).SetExecutor("n1", exec)
+
+	require.NoError(t, NewBuilder(nil).Mkdir("tidb", "n1", false, "/tidb-data").Build().Execute(ctx))
+	require.Nil(t, journalOf(ctx))
+}
@@ -50,29 +50,36 @@ func (m *Mkdir) Execute(ctx context.Context) error {
 		// 		test -d /a || (mkdir /a && chown tidb:tidb /a)
 		//		test -d /a/b || (mkdir /a/b && chown tidb:tidb /a/b)
 		//		test -d /a/b/c || (mkdir /a/b/c && chown tidb:tidb /a/b/c)
+		created := false
 		for i := range xs {
 			if xs[i] == "" {
 				continue
 			}
 
+			path := strings.Join(xs[:i+1], "/")
 			cmd := ""
 			if m.sudo {
 				cmd = fmt.Sprintf(
-					`test -d %[1]s || (mkdir -p %[1]s && chown %[2]s:$(id -g -n %[2]s) %[1]s)`,
-					strings.Join(xs[:i+1], "/"),
+					`test -d %[1]s || (mkdir -p %[1]s && chown %[2]s:$(id -g -n %[2]s) %[1]s && echo created)`,
+					path,
 					m.user,
 				)
 			} else {
 				cmd = fmt.Sprintf(
-					`test -d %[1]s || (mkdir -p %[1]s)`,
-					strings.Join(xs[:i+1], "/"),
+					`test -d %[1]s || (mkdir -p %[1]s && echo created)`,
+					path,
 				)
 			}
 
-			_, _, err := exec.Execute(ctx, cmd, m.sudo) // use root to create the dir
+			stdout, _, err := exec.Execute(ctx, cmd, m.sudo) // use root to create the dir
 			if err != nil {
 				return errors.Trace(err)
 			}
+			// only the top most created directory needs to be removed on rollback
+			if !created && strings.TrimSpace(string(stdout)) == "created" {
+				created = true
+				recordCommand(ctx, m.host, "remove directory "+path, fmt.Sprintf("rm -rf %s", path), m.sudo)
+			}
 		}
 	}
 
@@ -104,13 +104,8 @@ func (m *MonitoredConfig) syncMonitoredSystemConfig(ctx context.Context, exec ct
 
 	resource := spec.MergeResourceControl(m.globResCtl, m.options.ResourceControl)
 	systemCfg := system.NewConfig(comp, m.deployUser, m.paths.Deploy).
-		WithMemoryLimit(resource.MemoryLimit).
-		WithCPUQuota(resource.CPUQuota).
-		WithLimitCORE(resource.LimitCORE).
-		WithTimeoutStartSec(resource.TimeoutStartSec).
-		WithTimeoutStopSec(resource.TimeoutStopSec).
-		WithIOReadBandwidthMax(resource.IOReadBandwidthMax).
-		WithIOWriteBandwidthMax(resource.IOWriteBandwidthMax).
+		WithResourceControl(resource).
+		WithReadWritePaths(m.paths.HostDirs()...).
 		WithSystemdMode(string(systemdMode))
 
 	// blackbox_exporter needs cap_net_raw to send ICMP ping packets
@@ -53,6 +53,20 @@ func (c *SystemCtl) Execute(ctx context.Context) error {
 		return errors.Annotatef(err, "stdout: %s, stderr:%s", string(stdout), string(stderr))
 	}
 
+	if undo, ok := map[string]string{"start": "stop", "enable": "disable"}[c.action]; ok {
+		record(ctx, c.host, fmt.Sprintf("%s %s", undo, c.unit), func(ctx context.Context, e ctxt.Executor) error {
+			systemd := module.NewSystemdModule(module.SystemdModuleConfig{
+				Unit:   c.unit,
+				Action: undo,
+				Scope:  c.scope,
+			})
+			_, stderr, err := systemd.Execute(ctx, e)
+			if err != nil {
+				return errors.Annotatef(err, "stderr: %s", string(stderr))
+			}
+			return nil
+		})
+	}
 	return nil
 }
 
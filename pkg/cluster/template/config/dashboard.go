@@ -26,6 +26,13 @@ import (
 type DashboardConfig struct {
 	ClusterName string
 	DeployDir   string
+	Folders     []DashboardFolder
+}
+
+// DashboardFolder is a Grafana folder provisioned from a directory of dashboards
+type DashboardFolder struct {
+	Name string // the folder name shown in Grafana
+	Path string // the directory of the dashboards
 }
 
 // NewDashboardConfig returns a DashboardConfig
@@ -36,6 +43,12 @@ func NewDashboardConfig(cluster, deployDir string) *DashboardConfig {
 	}
 }
 
+// WithFolders sets the folders provisioned besides the one of the cluster
+func (c *DashboardConfig) WithFolders(folders []DashboardFolder) *DashboardConfig {
+	c.Folders = folders
+	return c
+}
+
 // Config generate the config file data.
 func (c *DashboardConfig) Config() ([]byte, error) {
 	fp := path.Join("templates", "config", "dashboard.yml.tpl")
@@ -15,6 +15,7 @@ package config
 
 import (
 	"bytes"
+	"encoding/json"
 	"path"
 	"text/template"
 
@@ -28,6 +29,7 @@ type DatasourceConfig struct {
 	Type      string
 	URL       string
 	IsDefault bool
+	JSONData  map[string]any
 }
 
 // NewDatasourceConfig returns a DatasourceConfig
@@ -58,6 +60,13 @@ func (c *DatasourceConfig) WithIsDefault(isDefault bool) *DatasourceConfig {
 	return c
 }
 
+// WithJSONData sets the type specific settings of datasource, e.g. the index
+// of an Elasticsearch datasource
+func (c *DatasourceConfig) WithJSONData(data map[string]any) *DatasourceConfig {
+	c.JSONData = data
+	return c
+}
+
 // ConfigToFile write config content to specific path
 func (c *DatasourceConfig) ConfigToFile(file string) error {
 	config, err := c.Config()
@@ -69,20 +78,43 @@ func (c *DatasourceConfig) ConfigToFile(file string) error {
 
 // Config generate the config file data.
 func (c *DatasourceConfig) Config() ([]byte, error) {
+	return DatasourcesConfig{c}.Config()
+}
+
+// DatasourcesConfig represent the data to generate the config of multiple
+// datasources in one provisioning file
+type DatasourcesConfig []*DatasourceConfig
+
+// ConfigToFile write config content to specific path
+func (c DatasourcesConfig) ConfigToFile(file string) error {
+	config, err := c.Config()
+	if err != nil {
+		return err
+	}
+	return utils.WriteFile(file, config, 0644)
+}
+
+// Config generate the config file data.
+func (c DatasourcesConfig) Config() ([]byte, error) {
 	fp := path.Join("templates", "config", "datasource.yml.tpl")
 	tpl, err := embed.ReadTemplate(fp)
 	if err != nil {
 		return nil, err
 	}
 
-	tmpl, err := template.New("Datasource").Parse(string(tpl))
+	tmpl, err := template.New("Datasource").Funcs(template.FuncMap{
+		"toJSON": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(string(tpl))
 	if err != nil {
 		return nil, err
 	}
 
 	content := bytes.NewBufferString("")
 	if err := tmpl.Execute(content, map[string]any{
-		"Datasources": []any{c},
+		"Datasources": []*DatasourceConfig(c),
 	}); err != nil {
 		return nil, err
 	}
@@ -64,6 +64,16 @@ type PrometheusConfig struct {
 
 	LocalRules   []string
 	RemoteConfig string
+
+	FederateJobs []FederateJob
+}
+
+// FederateJob represents the job scraping the /federate endpoint of the
+// Prometheus servers of another cluster
+type FederateJob struct {
+	Cluster string
+	Label   string // the label identifying the cluster of metrics
+	Targets []string
 }
 
 // NewPrometheusConfig returns a PrometheusConfig
@@ -239,6 +249,13 @@ func (c *PrometheusConfig) AddLocalRule(rule string) *PrometheusConfig {
 	return c
 }
 
+// AddFederateJob adds a job scraping the /federate endpoint of the Prometheus
+// servers of the cluster
+func (c *PrometheusConfig) AddFederateJob(cluster, label string, targets []string) *PrometheusConfig {
+	c.FederateJobs = append(c.FederateJobs, FederateJob{Cluster: cluster, Label: label, Targets: targets})
+	return c
+}
+
 // SetRemoteConfig set remote read/write config
 func (c *PrometheusConfig) SetRemoteConfig(cfg string) *PrometheusConfig {
 	c.RemoteConfig = cfg
@@ -179,3 +179,40 @@ func TestConfigToFileWithAgentMode(t *testing.T) {
 		t.Error("Agent mode config should not contain rule_files section")
 	}
 }
+
+func TestPrometheusConfigFederateJobs(t *testing.T) {
+	cfg := NewPrometheusConfig("central", "v6.1.0", false)
+	cfg.AddFederateJob("prod-1", "tidb_cluster", []string{"10.0.2.1:9090", "10.0.2.2:9090"})
+
+	content, err := cfg.Config()
+	require.NoError(t, err)
+
+	var rendered struct {
+		ScrapeConfigs []struct {
+			JobName       string              `yaml:"job_name"`
+			HonorLabels   bool                `yaml:"honor_labels"`
+			MetricsPath   string              `yaml:"metrics_path"`
+			Params        map[string][]string `yaml:"params"`
+			StaticConfigs []struct {
+				Targets []string          `yaml:"targets"`
+				Labels  map[string]string `yaml:"labels"`
+			} `yaml:"static_configs"`
+		} `yaml:"scrape_configs"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &rendered))
+
+	found := false
+	for _, job := range rendered.ScrapeConfigs {
+		if job.JobName != "federate-prod-1" {
+			continue
+		}
+		found = true
+		require.True(t, job.HonorLabels)
+		require.Equal(t, "/federate", job.MetricsPath)
+		require.Equal(t, []string{`{job=~".+"}`}, job.Params["match[]"])
+		require.Len(t, job.StaticConfigs, 1)
+		require.Equal(t, []string{"10.0.2.1:9090", "10.0.2.2:9090"}, job.StaticConfigs[0].Targets)
+		require.Equal(t, map[string]string{"tidb_cluster": "prod-1"}, job.StaticConfigs[0].Labels)
+	}
+	require.True(t, found, string(content))
+}
@@ -38,6 +38,8 @@ type PrometheusScript struct {
 	NumaNode string
 
 	AdditionalArgs []string
+
+	EnableRemoteWriteReceiver bool
 }
 
 // ConfigToFile write config content to specific path
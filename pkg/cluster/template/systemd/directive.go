@@ -0,0 +1,75 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// directiveVersions are the minimal systemd versions of the directives which
+// are not available in all the supported systems, directives not listed here
+// are supported by any systemd version we meet. The legacy directives were
+// rendered before the versions are checked, systemd ignores them with a
+// warning if they are not supported, so existing topologies keep working.
+var directiveVersions = []struct {
+	name    string
+	version int
+	legacy  bool
+	used    func(c *Config) bool
+}{
+	{"NoNewPrivileges", 187, false, func(c *Config) bool { return c.NoNewPrivileges }},
+	{"ProtectSystem", 214, false, func(c *Config) bool { return c.ProtectSystem != "" }},
+	{"TasksMax", 227, false, func(c *Config) bool { return c.TasksMax != "" }},
+	{"IOReadBandwidthMax", 230, true, func(c *Config) bool { return c.IOReadBandwidthMax != "" }},
+	{"IOWriteBandwidthMax", 230, true, func(c *Config) bool { return c.IOWriteBandwidthMax != "" }},
+	{"IODeviceWeight", 230, true, func(c *Config) bool { return c.IODeviceWeight != "" }},
+	{"MemoryHigh", 231, false, func(c *Config) bool { return c.MemoryHigh != "" }},
+	{"MemorySwapMax", 232, false, func(c *Config) bool { return c.MemorySwapMax != "" }},
+	{"ProtectSystem=strict", 232, false, func(c *Config) bool { return c.ProtectSystem == "strict" }},
+	{"AllowedCPUs", 244, false, func(c *Config) bool { return c.AllowedCPUs != "" }},
+}
+
+// UnsupportedDirective is a directive not supported by the systemd version
+type UnsupportedDirective struct {
+	Version int  // the minimal systemd version supporting the directive
+	Legacy  bool // the directive was rendered before the versions are checked
+}
+
+// UnsupportedDirectives returns the directives used in the config but not
+// supported by the given systemd version
+func (c *Config) UnsupportedDirectives(version int) map[string]UnsupportedDirective {
+	unsupported := make(map[string]UnsupportedDirective)
+	for _, d := range directiveVersions {
+		if d.used(c) && version < d.version {
+			unsupported[d.name] = UnsupportedDirective{Version: d.version, Legacy: d.legacy}
+		}
+	}
+	return unsupported
+}
+
+// ParseVersion parses the version number from the output of `systemctl --version`,
+// e.g. "systemd 239 (239-58.el8)"
+func ParseVersion(output string) (int, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 2 || fields[0] != "systemd" {
+		return 0, fmt.Errorf("unknown systemd version: %s", strings.TrimSpace(output))
+	}
+	version, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, fmt.Errorf("unknown systemd version: %s", fields[1])
+	}
+	return version, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package system
+
+import (
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/meta"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResourceControlDirectives(t *testing.T) {
+	enabled := true
+	cfg := NewConfig("tikv", "tidb", "/deploy").
+		WithResourceControl(meta.ResourceControl{
+			MemoryHigh:      "30G",
+			AllowedCPUs:     "0-7",
+			TasksMax:        "8192",
+			LimitNOFILE:     "2000000",
+			OOMScoreAdjust:  "-500",
+			ProtectSystem:   "strict",
+			IODeviceWeight:  "/dev/sda 200",
+			PrivateTmp:      &enabled,
+			NoNewPrivileges: &enabled,
+		}).
+		WithReadWritePaths("/deploy", "/data").
+		WithSystemdMode("system")
+
+	content, err := cfg.Config()
+	require.NoError(t, err)
+	for _, line := range []string{
+		"MemoryHigh=30G\n",
+		"AllowedCPUs=0-7\n",
+		"TasksMax=8192\n",
+		"LimitNOFILE=2000000\n",
+		"OOMScoreAdjust=-500\n",
+		"ProtectSystem=strict\n",
+		"ReadWritePaths=/deploy /data\n",
+		"PrivateTmp=yes\n",
+		"NoNewPrivileges=yes\n",
+	} {
+		require.Contains(t, string(content), line)
+	}
+	require.NotContains(t, string(content), "LimitNOFILE=1000000")
+	require.NotContains(t, string(content), "MemorySwapMax")
+
+	// the default limit is kept if not overridden
+	content, err = NewConfig("tidb", "tidb", "/deploy").WithSystemdMode("system").Config()
+	require.NoError(t, err)
+	require.Contains(t, string(content), "LimitNOFILE=1000000\n")
+	require.NotContains(t, string(content), "ReadWritePaths")
+
+	require.Empty(t, cfg.UnsupportedDirectives(252))
+	require.Equal(t, map[string]UnsupportedDirective{
+		"AllowedCPUs": {Version: 244},
+	}, cfg.UnsupportedDirectives(239))
+	require.Equal(t, map[string]UnsupportedDirective{
+		"AllowedCPUs":          {Version: 244},
+		"IODeviceWeight":       {Version: 230, Legacy: true},
+		"MemoryHigh":           {Version: 231},
+		"ProtectSystem":        {Version: 214},
+		"ProtectSystem=strict": {Version: 232},
+		"TasksMax":             {Version: 227},
+	}, cfg.UnsupportedDirectives(195))
+
+	// the sandboxing options enabled globally can be disabled per instance
+	disabled := false
+	content, err = NewConfig("tidb", "tidb", "/deploy").
+		WithResourceControl(meta.ResourceControl{PrivateTmp: &disabled}).
+		WithSystemdMode("system").
+		Config()
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "PrivateTmp")
+}
+
+func TestParseVersion(t *testing.T) {
+	version, err := ParseVersion("systemd 239 (239-58.el8)\n+PAM +AUDIT +SELINUX\n")
+	require.NoError(t, err)
+	require.Equal(t, 239, version)
+
+	_, err = ParseVersion("bash: systemctl: command not found")
+	require.Error(t, err)
+}
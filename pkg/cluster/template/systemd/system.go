@@ -19,6 +19,7 @@ import (
 	"text/template"
 
 	"github.com/pingcap/tiup/embed"
+	"github.com/pingcap/tiup/pkg/meta"
 	"github.com/pingcap/tiup/pkg/utils"
 )
 
@@ -27,19 +28,35 @@ type Config struct {
 	ServiceName         string
 	User                string
 	MemoryLimit         string
+	MemoryHigh          string
+	MemorySwapMax       string
 	CPUQuota            string
+	AllowedCPUs         string
+	CPUAffinity         string
 	IOReadBandwidthMax  string
 	IOWriteBandwidthMax string
+	IODeviceWeight      string
+	TasksMax            string
 	LimitCORE           string
-	DeployDir           string
-	DisableSendSigkill  bool
-	TimeoutStopSec      string
-	TimeoutStartSec     string
-	GrantCapNetRaw      bool
+	LimitNOFILE         string
+	OOMScoreAdjust      string
+	ProtectSystem       string
+	PrivateTmp          bool
+	NoNewPrivileges     bool
+	// ReadWritePaths are kept writable if the file system is read-only
+	// for the service, i.e. ProtectSystem=strict
+	ReadWritePaths     []string
+	DeployDir          string
+	DisableSendSigkill bool
+	TimeoutStopSec     string
+	TimeoutStartSec    string
+	GrantCapNetRaw     bool
 	// Takes one of no, on-success, on-failure, on-abnormal, on-watchdog, on-abort, or always.
 	// The Template set as always if this is not set.
 	Restart     string
 	SystemdMode string
+	// EnvironmentFile is the path of an extra env file loaded by the service
+	EnvironmentFile string
 }
 
 // NewConfig returns a Config with given arguments
@@ -51,6 +68,35 @@ func NewConfig(service, user, deployDir string) *Config {
 	}
 }
 
+// WithResourceControl set the resource control and sandboxing fields of Config
+func (c *Config) WithResourceControl(rc meta.ResourceControl) *Config {
+	c.MemoryLimit = rc.MemoryLimit
+	c.MemoryHigh = rc.MemoryHigh
+	c.MemorySwapMax = rc.MemorySwapMax
+	c.CPUQuota = rc.CPUQuota
+	c.AllowedCPUs = rc.AllowedCPUs
+	c.CPUAffinity = rc.CPUAffinity
+	c.IOReadBandwidthMax = rc.IOReadBandwidthMax
+	c.IOWriteBandwidthMax = rc.IOWriteBandwidthMax
+	c.IODeviceWeight = rc.IODeviceWeight
+	c.TasksMax = rc.TasksMax
+	c.LimitCORE = rc.LimitCORE
+	c.LimitNOFILE = rc.LimitNOFILE
+	c.OOMScoreAdjust = rc.OOMScoreAdjust
+	c.TimeoutStartSec = rc.TimeoutStartSec
+	c.TimeoutStopSec = rc.TimeoutStopSec
+	c.ProtectSystem = rc.ProtectSystem
+	c.PrivateTmp = rc.PrivateTmp != nil && *rc.PrivateTmp
+	c.NoNewPrivileges = rc.NoNewPrivileges != nil && *rc.NoNewPrivileges
+	return c
+}
+
+// WithReadWritePaths set the ReadWritePaths field of Config
+func (c *Config) WithReadWritePaths(paths ...string) *Config {
+	c.ReadWritePaths = paths
+	return c
+}
+
 // WithMemoryLimit set the MemoryLimit field of Config
 func (c *Config) WithMemoryLimit(mem string) *Config {
 	c.MemoryLimit = mem
@@ -99,6 +145,12 @@ func (c *Config) WithTimeoutStartSec(sec string) *Config {
 	return c
 }
 
+// WithEnvironmentFile set the EnvironmentFile field of Config
+func (c *Config) WithEnvironmentFile(file string) *Config {
+	c.EnvironmentFile = file
+	return c
+}
+
 // ConfigToFile write config content to specific path
 func (c *Config) ConfigToFile(file string) error {
 	config, err := c.Config()
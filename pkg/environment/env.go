@@ -19,6 +19,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/pingcap/errors"
@@ -63,9 +64,13 @@ var EnvList = []string{
 
 	// Misc runtime controls
 	localdata.EnvNameKeepSourceTarget,
+	localdata.EnvNameDownloadCache,
 	localdata.EnvNameMirrorSyncScript,
 	localdata.EnvNameLogPath,
 	localdata.EnvNameDebug,
+	localdata.EnvNameLockFile,
+	localdata.EnvNameClusterMetaBackend,
+	localdata.EnvNameClusterMetaKeyFile,
 	localdata.EnvTag,
 }
 
@@ -119,6 +124,11 @@ type Environment struct {
 	// repo represents the components repository of TiUP, it can be a
 	// local file system or a HTTP URL
 	v1Repo repository.Repository
+
+	// lock pins the versions of components, it's loaded on demand
+	lock     *Lockfile
+	lockErr  error
+	lockOnce sync.Once
 }
 
 // InitEnv creates a new Environment object configured using env vars and defaults.
@@ -150,7 +160,7 @@ func InitEnv(options repository.Options, mOpt repository.MirrorOptions) (*Enviro
 
 	zap.L().Debug("Initialize repository finished", zap.Duration("duration", time.Since(initRepo)))
 
-	return &Environment{profile, v1repo}, nil
+	return &Environment{profile: profile, v1Repo: v1repo}, nil
 }
 
 // V1Repository returns the initialized v1 repository
@@ -189,18 +199,70 @@ func (env *Environment) LocalPath(path ...string) string {
 }
 
 // UpdateComponents updates or installs all components described by specs.
+// The components pinned by the lock file are installed with the pinned
+// versions if no version is specified.
 func (env *Environment) UpdateComponents(specs []string, nightly, force bool) error {
 	var v1specs []repository.ComponentSpec
 	for _, spec := range specs {
 		component, v := ParseCompVersion(spec)
+		pinned, hash, err := env.PinnedVersion(component)
+		if err != nil {
+			return err
+		}
+		if v == "" {
+			v = pinned
+		}
+		if v != pinned {
+			hash = ""
+		}
 		if v == "" && nightly {
 			v = utils.NightlyVersionAlias
 		}
-		v1specs = append(v1specs, repository.ComponentSpec{ID: component, Version: v.String(), Force: force})
+		v1specs = append(v1specs, repository.ComponentSpec{ID: component, Version: v.String(), Force: force, Hash: hash})
 	}
 	return env.v1Repo.UpdateComponents(v1specs)
 }
 
+// InstallBundle installs the components described by specs from the offline
+// bundle, which is either a tarball or an extracted directory. All components
+// in the bundle are installed if specs is empty.
+func (env *Environment) InstallBundle(bundle string, specs []string, force bool) error {
+	fi, err := os.Stat(bundle)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	dir := bundle
+	if !fi.IsDir() {
+		dir, err = os.MkdirTemp("", "tiup-bundle")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer os.RemoveAll(dir)
+
+		if err := env.v1Repo.ExtractBundle(bundle, dir); err != nil {
+			return err
+		}
+	}
+
+	if len(specs) == 0 {
+		b, err := repository.LoadBundle(dir)
+		if err != nil {
+			return err
+		}
+		for _, comp := range b.Components {
+			specs = append(specs, comp.ID+":"+comp.Version)
+		}
+	}
+
+	var v1specs []repository.ComponentSpec
+	for _, spec := range specs {
+		component, v := ParseCompVersion(spec)
+		v1specs = append(v1specs, repository.ComponentSpec{ID: component, Version: v.String(), Force: force})
+	}
+	return env.v1Repo.InstallBundle(dir, v1specs)
+}
+
 // SelfUpdate updates TiUP.
 func (env *Environment) SelfUpdate() error {
 	if err := env.v1Repo.DownloadTiUP(env.LocalPath("bin")); err != nil {
@@ -22,6 +22,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -285,6 +286,23 @@ func HideSensitiveInfo(args []string) []string {
 	return r
 }
 
+var (
+	// sensitiveConfigRegexp matches items of password, token and secret in
+	// yaml, toml, json and `key=value` formatted configs
+	sensitiveConfigRegexp = regexp.MustCompile(`(?im)^(\s*["']?[\w.-]*(?:password|passwd|token|secret|access[-_]key)[\w.-]*["']?\s*[:=]\s*)(.+?)(,?)[ \t]*$`)
+	// uriRegexp matches URIs that may contain credentials
+	uriRegexp = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"']+`)
+)
+
+// HideSensitiveConfig replaces password and other sensitive info in config
+// files with ******
+func HideSensitiveConfig(data []byte) []byte {
+	data = sensitiveConfigRegexp.ReplaceAll(data, []byte(`${1}"******"${3}`))
+	return uriRegexp.ReplaceAllFunc(data, func(uri []byte) []byte {
+		return []byte(filterURI(string(uri)))
+	})
+}
+
 // Redacts a URI by filtering out query values based on their key.
 // values are replaced by ******
 func filterURI(uri string) string {
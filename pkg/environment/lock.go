@@ -0,0 +1,218 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package environment
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/localdata"
+	"github.com/pingcap/tiup/pkg/repository/v1manifest"
+	"github.com/pingcap/tiup/pkg/set"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+const lockfileHeader = "# This file is generated by `tiup lock`, do not edit it manually.\n\n"
+
+// LockedComponent is a component version pinned by the lock file
+type LockedComponent struct {
+	Name    string `toml:"name"`
+	Version string `toml:"version"`
+	// Hashes are the sha256 of the tarballs keyed by platform, e.g. linux/amd64
+	Hashes map[string]string `toml:"hashes"`
+}
+
+// Lockfile pins the versions of components, so that the same versions are
+// used by `tiup <component>`, `tiup install` and `tiup update` between runs
+type Lockfile struct {
+	path       string
+	Components []*LockedComponent `toml:"component"`
+}
+
+// FindLockfile returns the path of the lock file, which is specified by
+// TIUP_LOCK_FILE or discovered upward from the working directory. An empty
+// path is returned if there is no lock file.
+func FindLockfile() (string, error) {
+	if path := os.Getenv(localdata.EnvNameLockFile); path != "" {
+		return filepath.Abs(path)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for {
+		path := filepath.Join(dir, localdata.LockFilename)
+		if utils.IsExist(path) {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+		dir = parent
+	}
+}
+
+// NewLockfile returns an empty lock file to be saved at path
+func NewLockfile(path string) *Lockfile {
+	return &Lockfile{path: path}
+}
+
+// LoadLockfile loads the lock file at path
+func LoadLockfile(path string) (*Lockfile, error) {
+	lock := NewLockfile(path)
+	if _, err := toml.DecodeFile(path, lock); err != nil {
+		return nil, errors.Annotatef(err, "load lock file %s", path)
+	}
+
+	names := set.NewStringSet()
+	for _, comp := range lock.Components {
+		if comp.Name == "" || comp.Version == "" {
+			return nil, errors.Errorf("lock file %s contains component without name or version", path)
+		}
+		if names.Exist(comp.Name) {
+			return nil, errors.Errorf("lock file %s contains duplicated component %s", path, comp.Name)
+		}
+		names.Insert(comp.Name)
+	}
+	return lock, nil
+}
+
+// Path returns the path of the lock file
+func (l *Lockfile) Path() string {
+	return l.path
+}
+
+// Get returns the locked component, nil is returned if it is not locked
+func (l *Lockfile) Get(name string) *LockedComponent {
+	for _, comp := range l.Components {
+		if comp.Name == name {
+			return comp
+		}
+	}
+	return nil
+}
+
+// Set locks the component or replaces the locked one
+func (l *Lockfile) Set(comp *LockedComponent) {
+	for idx := range l.Components {
+		if l.Components[idx].Name == comp.Name {
+			l.Components[idx] = comp
+			return
+		}
+	}
+	l.Components = append(l.Components, comp)
+}
+
+// Pin returns the version of the component pinned and the sha256 of the
+// tarball on the platform, the hash is empty if it's not locked for the
+// platform
+func (l *Lockfile) Pin(name, platform string) (version utils.Version, hash string, ok bool) {
+	comp := l.Get(name)
+	if comp == nil {
+		return "", "", false
+	}
+	return utils.Version(comp.Version), comp.Hashes[platform], true
+}
+
+// Platforms returns all platforms the components are locked for
+func (l *Lockfile) Platforms() []string {
+	platforms := set.NewStringSet()
+	for _, comp := range l.Components {
+		for platform := range comp.Hashes {
+			platforms.Insert(platform)
+		}
+	}
+	result := platforms.Slice()
+	sort.Strings(result)
+	return result
+}
+
+// Save writes the lock file to disk
+func (l *Lockfile) Save() error {
+	sort.Slice(l.Components, func(i, j int) bool {
+		return l.Components[i].Name < l.Components[j].Name
+	})
+
+	buf := bytes.NewBufferString(lockfileHeader)
+	if err := toml.NewEncoder(buf).Encode(l); err != nil {
+		return errors.Trace(err)
+	}
+	return utils.WriteFile(l.path, buf.Bytes(), 0644)
+}
+
+// Lockfile returns the lock file pinning the component versions, nil is
+// returned if there is no lock file
+func (env *Environment) Lockfile() (*Lockfile, error) {
+	env.lockOnce.Do(func() {
+		path, err := FindLockfile()
+		if err != nil || path == "" {
+			env.lockErr = err
+			return
+		}
+		env.lock, env.lockErr = LoadLockfile(path)
+	})
+	return env.lock, env.lockErr
+}
+
+// PinnedVersion returns the version of the component pinned by the lock file
+// and the expected sha256 of the tarball on the current platform, an empty
+// version is returned if the component is not pinned
+func (env *Environment) PinnedVersion(component string) (utils.Version, string, error) {
+	lock, err := env.Lockfile()
+	if err != nil || lock == nil {
+		return "", "", err
+	}
+	version, hash, _ := lock.Pin(component, env.v1Repo.PlatformString())
+	return version, hash, nil
+}
+
+// LockComponents resolves the versions of components described by specs and
+// pins them with the hashes of the tarballs on the platforms into the lock
+// file
+func (env *Environment) LockComponents(lock *Lockfile, specs []string, platforms []string) error {
+	for _, spec := range specs {
+		component, constraint := ParseCompVersion(spec)
+		manifest, err := env.v1Repo.GetComponentManifest(component, false)
+		if err != nil {
+			return errors.Annotatef(err, "component %s", component)
+		}
+		version, err := env.v1Repo.ResolveComponentVersion(component, constraint.String())
+		if err != nil {
+			return err
+		}
+
+		locked := &LockedComponent{
+			Name:    component,
+			Version: version.String(),
+			Hashes:  make(map[string]string),
+		}
+		for _, platform := range platforms {
+			item := manifest.VersionItem(platform, version.String(), false)
+			if item == nil {
+				return errors.Errorf("component %s:%s is not available on %s", component, version, platform)
+			}
+			locked.Hashes[platform] = item.Hashes[v1manifest.SHA256]
+		}
+		lock.Set(locked)
+		fmt.Printf("Locked component %s:%s\n", component, version)
+	}
+	return lock.Save()
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/localdata"
+	"github.com/pingcap/tiup/pkg/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), localdata.LockFilename)
+	lock := NewLockfile(path)
+	lock.Set(&LockedComponent{Name: "ctl", Version: "v7.5.0", Hashes: map[string]string{"linux/amd64": "abc"}})
+	lock.Set(&LockedComponent{Name: "cluster", Version: "v1.15.0", Hashes: map[string]string{"darwin/arm64": "def"}})
+	lock.Set(&LockedComponent{Name: "cluster", Version: "v1.16.0", Hashes: map[string]string{"linux/amd64": "123"}})
+	require.NoError(t, lock.Save())
+
+	loaded, err := LoadLockfile(path)
+	require.NoError(t, err)
+	require.Equal(t, path, loaded.Path())
+	require.Len(t, loaded.Components, 2)
+	require.Equal(t, "cluster", loaded.Components[0].Name)
+	require.Equal(t, []string{"linux/amd64"}, loaded.Platforms())
+
+	version, hash, ok := loaded.Pin("cluster", "linux/amd64")
+	require.True(t, ok)
+	require.Equal(t, utils.Version("v1.16.0"), version)
+	require.Equal(t, "123", hash)
+	version, hash, ok = loaded.Pin("ctl", "darwin/arm64")
+	require.True(t, ok)
+	require.Equal(t, utils.Version("v7.5.0"), version)
+	require.Empty(t, hash)
+	_, _, ok = loaded.Pin("tidb", "linux/amd64")
+	require.False(t, ok)
+
+	// invalid lock files
+	require.NoError(t, os.WriteFile(path, []byte("[[component]]\nname = \"ctl\"\n"), 0644))
+	_, err = LoadLockfile(path)
+	require.ErrorContains(t, err, "without name or version")
+	require.NoError(t, os.WriteFile(path, []byte("[[component]]\nname = \"ctl\"\nversion = \"v1\"\n[[component]]\nname = \"ctl\"\nversion = \"v2\"\n"), 0644))
+	_, err = LoadLockfile(path)
+	require.ErrorContains(t, err, "duplicated component ctl")
+}
+
+func TestFindLockfile(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+	root := t.TempDir()
+	sub := filepath.Join(root, "a", "b")
+	require.NoError(t, os.MkdirAll(sub, 0755))
+	require.NoError(t, os.Chdir(sub))
+
+	t.Setenv(localdata.EnvNameLockFile, "")
+	path, err := FindLockfile()
+	require.NoError(t, err)
+	require.Empty(t, path)
+
+	lock := filepath.Join(root, localdata.LockFilename)
+	require.NoError(t, NewLockfile(lock).Save())
+	path, err = FindLockfile()
+	require.NoError(t, err)
+	require.Equal(t, lock, path)
+
+	custom := filepath.Join(t.TempDir(), "custom.lock")
+	t.Setenv(localdata.EnvNameLockFile, custom)
+	path, err = FindLockfile()
+	require.NoError(t, err)
+	require.Equal(t, custom, path)
+}
@@ -44,6 +44,13 @@ var skipStartingMessages = map[string]bool{
 // RunComponent start a component and wait it
 func RunComponent(env *environment.Environment, tag, spec, binPath string, forcePull bool, args []string) error {
 	component, version := environment.ParseCompVersion(spec)
+	if version == "" {
+		pinned, _, err := env.PinnedVersion(component)
+		if err != nil {
+			return err
+		}
+		version = pinned
+	}
 
 	if version == "" {
 		cmdCheckUpdate(component, version)
@@ -268,6 +275,11 @@ func PrepareBinary(component string, version utils.Version, binPath string, forc
 				Version: string(ver),
 				Force:   force,
 			}
+			if pinned, hash, err := env.PinnedVersion(component); err != nil {
+				return "", err
+			} else if pinned == ver {
+				spec.Hash = hash
+			}
 			if err := env.V1Repository().UpdateComponents([]repository.ComponentSpec{spec}); err != nil {
 				return "", err
 			}
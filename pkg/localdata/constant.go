@@ -106,6 +106,24 @@ const (
 	// EnvNameDebug is the variable name by which user can set tiup runs in debug mode(eg. print panic logs)
 	EnvNameDebug = "TIUP_CLUSTER_DEBUG"
 
+	// EnvNameDownloadCache is the variable name by which user can specify the directory of the download cache shared between profiles
+	EnvNameDownloadCache = "TIUP_DOWNLOAD_CACHE"
+
+	// EnvNameLockFile is the variable name by which user can specify the lock file pinning component versions
+	EnvNameLockFile = "TIUP_LOCK_FILE"
+
+	// EnvNameClusterMetaBackend is the variable name by which user can specify the backend storing the metadata of clusters
+	EnvNameClusterMetaBackend = "TIUP_CLUSTER_META_BACKEND"
+
+	// EnvNameClusterMetaKeyFile is the variable name by which user can specify the keyfile to encrypt the metadata of clusters
+	EnvNameClusterMetaKeyFile = "TIUP_CLUSTER_META_KEYFILE"
+
+	// EnvNameClusterMetaPassphrase is the variable name by which user can specify the passphrase to encrypt the metadata of clusters
+	EnvNameClusterMetaPassphrase = "TIUP_CLUSTER_META_PASSPHRASE"
+
+	// LockFilename represents the file name of the lock file pinning component versions
+	LockFilename = "tiup.lock"
+
 	// MetaFilename represents the process meta file name
 	MetaFilename = "tiup_process_meta"
 )
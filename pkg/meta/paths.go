@@ -35,3 +35,18 @@ func (p DirPaths) String() string {
 		p.Cache,
 	)
 }
+
+// HostDirs returns the deploy, data and log directories on the host where
+// the component is deployed, the local cache directory is not included
+func (p DirPaths) HostDirs() []string {
+	dirs := []string{p.Deploy}
+	for _, dir := range p.Data {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	if p.Log != "" {
+		dirs = append(dirs, p.Log)
+	}
+	return dirs
+}
@@ -13,14 +13,50 @@
 
 package meta
 
+import (
+	"fmt"
+	"strconv"
+)
+
 // ResourceControl is used to control the system resource
 // See: https://www.freedesktop.org/software/systemd/man/systemd.resource-control.html
 type ResourceControl struct {
 	MemoryLimit         string `yaml:"memory_limit,omitempty" validate:"memory_limit:editable"`
+	MemoryHigh          string `yaml:"memory_high,omitempty" validate:"memory_high:editable"`
+	MemorySwapMax       string `yaml:"memory_swap_max,omitempty" validate:"memory_swap_max:editable"`
 	CPUQuota            string `yaml:"cpu_quota,omitempty" validate:"cpu_quota:editable"`
+	AllowedCPUs         string `yaml:"allowed_cpus,omitempty" validate:"allowed_cpus:editable"`
+	CPUAffinity         string `yaml:"cpu_affinity,omitempty" validate:"cpu_affinity:editable"`
 	IOReadBandwidthMax  string `yaml:"io_read_bandwidth_max,omitempty" validate:"io_read_bandwidth_max:editable"`
 	IOWriteBandwidthMax string `yaml:"io_write_bandwidth_max,omitempty" validate:"io_write_bandwidth_max:editable"`
+	IODeviceWeight      string `yaml:"io_device_weight,omitempty" validate:"io_device_weight:editable"`
+	TasksMax            string `yaml:"tasks_max,omitempty" validate:"tasks_max:editable"`
 	LimitCORE           string `yaml:"limit_core,omitempty" validate:"limit_core:editable"`
+	LimitNOFILE         string `yaml:"limit_nofile,omitempty" validate:"limit_nofile:editable"`
+	OOMScoreAdjust      string `yaml:"oom_score_adjust,omitempty" validate:"oom_score_adjust:editable"`
 	TimeoutStopSec      string `yaml:"timeout_stop_sec,omitempty" validate:"timeout_stop_sec:editable"`
 	TimeoutStartSec     string `yaml:"timeout_start_sec,omitempty" validate:"timeout_start_sec:editable"`
+	// The sandboxing options, the bool options are pointers so that they
+	// can be set to false per instance if they are enabled globally
+	// See: https://www.freedesktop.org/software/systemd/man/systemd.exec.html#Sandboxing
+	ProtectSystem   string `yaml:"protect_system,omitempty" validate:"protect_system:editable"`
+	PrivateTmp      *bool  `yaml:"private_tmp,omitempty" validate:"private_tmp:editable"`
+	NoNewPrivileges *bool  `yaml:"no_new_privileges,omitempty" validate:"no_new_privileges:editable"`
+}
+
+// Validate checks the values of the options which can not be passed to
+// systemd as is
+func (r ResourceControl) Validate() error {
+	switch r.ProtectSystem {
+	case "", "true", "false", "yes", "no", "full", "strict":
+	default:
+		return fmt.Errorf("invalid protect_system '%s', should be one of true, false, full and strict", r.ProtectSystem)
+	}
+	if r.OOMScoreAdjust != "" {
+		score, err := strconv.Atoi(r.OOMScoreAdjust)
+		if err != nil || score < -1000 || score > 1000 {
+			return fmt.Errorf("invalid oom_score_adjust '%s', should be an integer between -1000 and 1000", r.OOMScoreAdjust)
+		}
+	}
+	return nil
 }
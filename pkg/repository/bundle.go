@@ -0,0 +1,418 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/localdata"
+	"github.com/pingcap/tiup/pkg/repository/v1manifest"
+	"github.com/pingcap/tiup/pkg/set"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// BundleManifestFilename is the file describing the components in a bundle
+const BundleManifestFilename = "bundle.json"
+
+// Bundle describes the components packed in an offline bundle, the bundle
+// also contains the signed manifests of the mirror it is created from, so
+// the components can be verified against the trusted root of the profile.
+type Bundle struct {
+	Platform   string            `json:"platform"`
+	Components []BundleComponent `json:"components"`
+}
+
+// BundleComponent is a component version packed in a bundle
+type BundleComponent struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+// version returns the version of the component packed in the bundle
+func (b *Bundle) version(id string) (string, bool) {
+	for _, comp := range b.Components {
+		if comp.ID == id {
+			return comp.Version, true
+		}
+	}
+	return "", false
+}
+
+// recordMirror saves every resource fetched from the mirror into a directory,
+// the directory is then a mirror containing exactly the resources needed.
+type recordMirror struct {
+	Mirror
+	dir string
+}
+
+// Download implements the Mirror interface
+func (m *recordMirror) Download(resource, targetDir string) error {
+	if err := m.Mirror.Download(resource, m.dir); err != nil {
+		return err
+	}
+	if err := utils.MkdirAll(targetDir, 0755); err != nil {
+		return errors.Trace(err)
+	}
+	return utils.Copy(filepath.Join(m.dir, resource), filepath.Join(targetDir, resource))
+}
+
+// Fetch implements the Mirror interface
+func (m *recordMirror) Fetch(resource string, maxSize int64) (io.ReadCloser, error) {
+	reader, err := m.Mirror.Fetch(resource, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	path := filepath.Join(m.dir, resource)
+	if err := utils.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := utils.WriteFile(path, data, 0644); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// bundleManifests verifies the manifests of a bundle in an isolated store,
+// while the components are installed into the local profile, so that the
+// manifests cached in the profile are not affected by the bundle.
+type bundleManifests struct {
+	*v1manifest.FsManifests
+	target v1manifest.LocalManifests
+}
+
+// ComponentInstalled implements LocalManifests.
+func (ms *bundleManifests) ComponentInstalled(component, version string) (bool, error) {
+	return ms.target.ComponentInstalled(component, version)
+}
+
+// InstallComponent implements LocalManifests.
+func (ms *bundleManifests) InstallComponent(reader io.Reader, targetDir, component, version, filename string, noExpand bool) error {
+	return ms.target.InstallComponent(reader, targetDir, component, version, filename, noExpand)
+}
+
+// TargetRootDir implements LocalManifests.
+func (ms *bundleManifests) TargetRootDir() string {
+	return ms.target.TargetRootDir()
+}
+
+// isolatedManifests creates a manifest store in dir which only trusts the
+// root of the local store
+func (r *V1Repository) isolatedManifests(dir string) (*v1manifest.FsManifests, error) {
+	var root v1manifest.Root
+	manifest, exists, err := r.local.LoadManifest(&root)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.New("no trusted root found in the local profile")
+	}
+
+	manifestDir := filepath.Join(dir, localdata.ManifestParentDir)
+	if err := utils.MkdirAll(manifestDir, 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := v1manifest.WriteManifestFile(filepath.Join(manifestDir, v1manifest.ManifestFilenameRoot), manifest); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return v1manifest.NewManifests(localdata.NewProfile(dir, &localdata.TiUPConfig{}))
+}
+
+// CreateBundle packs the components described by specs into a gzipped
+// tarball at output, the manifests from the trusted root to the components
+// are packed as they are, so the bundle can be verified when installing.
+func (r *V1Repository) CreateBundle(specs []ComponentSpec, output string) error {
+	if len(specs) == 0 {
+		return errors.New("no component specified")
+	}
+
+	stage, err := os.MkdirTemp("", "tiup-bundle")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(stage)
+
+	local, err := r.isolatedManifests(filepath.Join(stage, "profile"))
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(stage, "bundle")
+	mirror := &recordMirror{Mirror: r.mirror, dir: dir}
+	repo := NewV1Repo(mirror, r.Options, local)
+
+	// The whole root chain is packed, so that the profiles trusting an older
+	// root are able to verify the bundle too.
+	for version := uint(1); ; version++ {
+		reader, err := mirror.Fetch(FnameWithVersion(v1manifest.ManifestURLRoot, version), int64(maxRootSize))
+		if err != nil {
+			if errors.Cause(err) == ErrNotFound {
+				break
+			}
+			return err
+		}
+		reader.Close()
+	}
+
+	if err := repo.ensureManifests(); err != nil {
+		return err
+	}
+
+	bundle := Bundle{Platform: r.PlatformString()}
+	for _, spec := range specs {
+		if _, ok := bundle.version(spec.ID); ok {
+			return errors.Errorf("component %s is specified more than once", spec.ID)
+		}
+		manifest, err := repo.updateComponentManifest(spec.ID, false)
+		if err != nil {
+			return errors.Annotatef(err, "component %s", spec.ID)
+		}
+
+		version := spec.Version
+		if version == utils.NightlyVersionAlias {
+			if !manifest.HasNightly(r.PlatformString()) {
+				return errors.Errorf("component %s on platform %s does not have a nightly version", spec.ID, r.PlatformString())
+			}
+			version = manifest.Nightly
+		}
+		if version == "" {
+			ver, _, err := repo.LatestStableVersion(spec.ID, false, nil)
+			if err != nil {
+				return err
+			}
+			version = ver.String()
+		}
+
+		item, err := repo.ComponentVersion(spec.ID, version, false)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(stage, "download", item.URL)
+		if err := repo.DownloadComponent(item, target); err != nil {
+			return err
+		}
+		// the tarball is not fetched from the mirror if it's found in the
+		// shared download cache, pack the verified copy in that case
+		if packed := filepath.Join(dir, item.URL); utils.IsNotExist(packed) {
+			if err := utils.MkdirAll(filepath.Dir(packed), 0755); err != nil {
+				return errors.Trace(err)
+			}
+			if err := os.Rename(target, packed); err != nil {
+				return errors.Trace(err)
+			}
+		}
+		_ = os.Remove(target)
+
+		fmt.Printf("Packed component %s:%s (%s)\n", spec.ID, version, r.PlatformString())
+		bundle.Components = append(bundle.Components, BundleComponent{ID: spec.ID, Version: version})
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := utils.WriteFile(filepath.Join(dir, BundleManifestFilename), data, 0644); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := utils.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return errors.Trace(err)
+	}
+	file, err := os.Create(output)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer file.Close()
+	return utils.Tar(file, dir)
+}
+
+// LoadBundle loads the description of the extracted bundle in dir
+func LoadBundle(dir string) (*Bundle, error) {
+	data, err := os.ReadFile(filepath.Join(dir, BundleManifestFilename))
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid bundle %s", dir)
+	}
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, errors.Annotatef(err, "invalid bundle %s", dir)
+	}
+	return &bundle, nil
+}
+
+// ExtractBundle extracts the bundle tarball into dir. The manifests in the
+// bundle are extracted and verified against the trusted root of the local
+// profile first, then only the tarballs of the components described by the
+// verified manifests are extracted, so that nothing else in the bundle is
+// written out.
+func (r *V1Repository) ExtractBundle(tarball, dir string) error {
+	if err := extractBundleFiles(tarball, dir, func(name string) bool {
+		return strings.HasSuffix(name, ".json")
+	}); err != nil {
+		return err
+	}
+	bundle, err := LoadBundle(dir)
+	if err != nil {
+		return err
+	}
+	files, err := r.verifyBundle(dir, bundle)
+	if err != nil {
+		return errors.Annotatef(err, "verify bundle %s", tarball)
+	}
+	return extractBundleFiles(tarball, dir, files.Exist)
+}
+
+// verifyBundle verifies the manifests of the extracted bundle in dir, and
+// returns the tarballs of the components in the bundle
+func (r *V1Repository) verifyBundle(dir string, bundle *Bundle) (set.StringSet, error) {
+	if bundle.Platform != r.PlatformString() {
+		return nil, errors.Errorf("the bundle is created for %s, but the current platform is %s", bundle.Platform, r.PlatformString())
+	}
+
+	stage, err := os.MkdirTemp("", "tiup-bundle")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer os.RemoveAll(stage)
+
+	local, err := r.isolatedManifests(stage)
+	if err != nil {
+		return nil, err
+	}
+	mirror := NewMirror(dir, MirrorOptions{})
+	if err := mirror.Open(); err != nil {
+		return nil, err
+	}
+	defer mirror.Close()
+
+	repo := NewV1Repo(mirror, r.Options, local)
+	if err := repo.ensureManifests(); err != nil {
+		return nil, err
+	}
+	files := set.NewStringSet()
+	for _, comp := range bundle.Components {
+		manifest, err := repo.updateComponentManifest(comp.ID, false)
+		if err != nil {
+			return nil, errors.Annotatef(err, "component %s", comp.ID)
+		}
+		item := manifest.VersionItem(r.PlatformString(), comp.Version, false)
+		if item == nil {
+			return nil, errors.Errorf("version %s of component %s is not found in the manifest", comp.Version, comp.ID)
+		}
+		files.Insert(strings.TrimPrefix(item.URL, "/"))
+	}
+	return files, nil
+}
+
+// extractBundleFiles extracts the files accepted by the filter from the
+// bundle tarball into dir, the bundle only contains regular files in the top
+// level directory, and other entries are rejected.
+func extractBundleFiles(tarball, dir string, filter func(name string) bool) error {
+	file, err := os.Open(tarball)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer file.Close()
+	gr, err := gzip.NewReader(file)
+	if err != nil {
+		return errors.Annotatef(err, "invalid bundle %s", tarball)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Annotatef(err, "invalid bundle %s", tarball)
+		}
+		name := path.Clean(hdr.Name)
+		if hdr.Typeflag != tar.TypeReg || strings.ContainsAny(name, `/\`) || name == ".." || name == "." {
+			return errors.Errorf("invalid bundle %s: unexpected entry %s", tarball, hdr.Name)
+		}
+		if !filter(name) {
+			continue
+		}
+		if err := utils.MkdirAll(dir, 0755); err != nil {
+			return errors.Trace(err)
+		}
+		fw, err := os.OpenFile(filepath.Join(dir, name), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, err = io.Copy(fw, tr)
+		fw.Close()
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// InstallBundle installs the components described by specs from the
+// extracted bundle in dir. The manifests of the bundle are verified against
+// the trusted root of the local profile without touching the cached ones,
+// and the mirror of the profile is left as it is.
+func (r *V1Repository) InstallBundle(dir string, specs []ComponentSpec) error {
+	bundle, err := LoadBundle(dir)
+	if err != nil {
+		return err
+	}
+	if bundle.Platform != r.PlatformString() {
+		return errors.Errorf("the bundle is created for %s, but the current platform is %s", bundle.Platform, r.PlatformString())
+	}
+
+	for idx := range specs {
+		version, ok := bundle.version(specs[idx].ID)
+		if !ok {
+			return errors.Errorf("component %s is not included in the bundle", specs[idx].ID)
+		}
+		if specs[idx].Version == "" {
+			specs[idx].Version = version
+		}
+	}
+
+	stage, err := os.MkdirTemp("", "tiup-bundle")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(stage)
+
+	local, err := r.isolatedManifests(stage)
+	if err != nil {
+		return err
+	}
+	mirror := NewMirror(dir, MirrorOptions{})
+	if err := mirror.Open(); err != nil {
+		return err
+	}
+	defer mirror.Close()
+
+	repo := NewV1Repo(mirror, r.Options, &bundleManifests{FsManifests: local, target: r.local})
+	return repo.UpdateComponents(specs)
+}
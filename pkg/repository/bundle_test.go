@@ -0,0 +1,178 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/localdata"
+	"github.com/pingcap/tiup/pkg/repository/v1manifest"
+	"github.com/pingcap/tiup/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bundleMirror returns a mock mirror with component foo and the local
+// manifests trusting the root of it
+func bundleMirror(t *testing.T) (*MockMirror, *v1manifest.MockManifests) {
+	mirror := &MockMirror{Resources: map[string]string{}}
+	local := v1manifest.NewMockManifests()
+	local.RootDir = t.TempDir()
+
+	root, priv := rootManifest(t)
+	setRoot(local, root)
+	bytes, err := priv.Serialize()
+	require.NoError(t, err)
+	signed, err := v1manifest.SignManifest(root, v1manifest.NewKeyInfo(bytes))
+	require.NoError(t, err)
+	local.Manifests[v1manifest.ManifestFilenameRoot] = signed
+
+	index, indexPriv := indexManifest(t)
+	snapshot := snapshotManifest()
+	snapStr := serialize(t, snapshot, priv)
+	ts := timestampManifest()
+	ts.Meta[v1manifest.ManifestURLSnapshot].Hashes[v1manifest.SHA256] = hash(snapStr)
+	indexURL, _, _ := snapshot.VersionedURL(v1manifest.ManifestURLIndex)
+	mirror.Resources[indexURL] = serialize(t, index, priv)
+	mirror.Resources[v1manifest.ManifestURLSnapshot] = snapStr
+	mirror.Resources[v1manifest.ManifestURLTimestamp] = serialize(t, ts, priv)
+	mirror.Resources["/7.foo.json"] = serialize(t, componentManifest(), indexPriv)
+	mirror.Resources["/foo-2.0.1.tar.gz"] = "foo201"
+	mirror.Resources["/foo-3.0.0-rc.tar.gz"] = "foo300rc"
+	return mirror, local
+}
+
+func createBundle(t *testing.T, repo *V1Repository, specs []ComponentSpec) string {
+	output := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	require.NoError(t, repo.CreateBundle(specs, output))
+
+	file, err := os.Open(output)
+	require.NoError(t, err)
+	defer file.Close()
+	dir := t.TempDir()
+	require.NoError(t, utils.Untar(file, dir))
+	return dir
+}
+
+func TestBundle(t *testing.T) {
+	mirror, local := bundleMirror(t)
+	repo := NewV1Repo(mirror, Options{GOOS: "plat", GOARCH: "form"}, local)
+	dir := createBundle(t, repo, []ComponentSpec{{ID: "foo"}})
+
+	bundle, err := LoadBundle(dir)
+	require.NoError(t, err)
+	assert.Equal(t, &Bundle{
+		Platform:   "plat/form",
+		Components: []BundleComponent{{ID: "foo", Version: "v2.0.1"}},
+	}, bundle)
+	assert.FileExists(t, filepath.Join(dir, v1manifest.ManifestURLTimestamp))
+	assert.FileExists(t, filepath.Join(dir, "foo-2.0.1.tar.gz"))
+	assert.NoFileExists(t, filepath.Join(dir, "foo-3.0.0-rc.tar.gz"))
+	// the manifests cached locally are not touched by creating the bundle
+	assert.Empty(t, local.Saved)
+
+	// install into another profile trusting the same root without mirror
+	target := v1manifest.NewMockManifests()
+	target.RootDir = t.TempDir()
+	target.Manifests[v1manifest.ManifestFilenameRoot] = local.Manifests[v1manifest.ManifestFilenameRoot]
+	offline := NewV1Repo(&MockMirror{}, Options{GOOS: "plat", GOARCH: "form"}, target)
+	err = offline.InstallBundle(dir, []ComponentSpec{{ID: "foo"}})
+	require.NoError(t, err)
+	assert.Equal(t, "v2.0.1", target.Installed["foo"].Version)
+	assert.Equal(t, "foo201", target.Installed["foo"].Contents)
+	assert.Equal(t, filepath.Join(target.RootDir, "components/foo/v2.0.1/foo-2.0.1.tar.gz"), target.Installed["foo"].BinaryPath)
+	assert.Empty(t, target.Saved)
+
+	// components not in the bundle
+	err = offline.InstallBundle(dir, []ComponentSpec{{ID: "bar"}})
+	assert.ErrorContains(t, err, "component bar is not included in the bundle")
+
+	// bundle for other platforms
+	other := NewV1Repo(&MockMirror{}, Options{GOOS: "linux", GOARCH: "amd64"}, target)
+	err = other.InstallBundle(dir, []ComponentSpec{{ID: "foo"}})
+	assert.ErrorContains(t, err, "created for plat/form")
+}
+
+func TestBundleWithDownloadCache(t *testing.T) {
+	t.Setenv(localdata.EnvNameDownloadCache, t.TempDir())
+	mirror, local := bundleMirror(t)
+	repo := NewV1Repo(mirror, Options{GOOS: "plat", GOARCH: "form"}, local)
+
+	// the tarball is packed no matter if it's in the cache or not
+	for range 2 {
+		dir := createBundle(t, repo, []ComponentSpec{{ID: "foo"}})
+		data, err := os.ReadFile(filepath.Join(dir, "foo-2.0.1.tar.gz"))
+		require.NoError(t, err)
+		assert.Equal(t, "foo201", string(data))
+	}
+}
+
+func TestBundleVerify(t *testing.T) {
+	mirror, local := bundleMirror(t)
+	repo := NewV1Repo(mirror, Options{GOOS: "plat", GOARCH: "form"}, local)
+
+	// tampered tarball
+	dir := createBundle(t, repo, []ComponentSpec{{ID: "foo", Version: "v3.0.0-rc"}})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "foo-3.0.0-rc.tar.gz"), []byte("evil"), 0644))
+	err := repo.InstallBundle(dir, []ComponentSpec{{ID: "foo"}})
+	assert.ErrorContains(t, err, "validation failed")
+	assert.Empty(t, local.Installed)
+
+	// bundle from a mirror not trusted by the profile
+	dir = createBundle(t, repo, []ComponentSpec{{ID: "foo"}})
+	_, untrusted := bundleMirror(t)
+	err = NewV1Repo(&MockMirror{}, Options{GOOS: "plat", GOARCH: "form"}, untrusted).
+		InstallBundle(dir, []ComponentSpec{{ID: "foo"}})
+	assert.Error(t, err)
+	assert.Empty(t, untrusted.Installed)
+}
+
+func TestExtractBundle(t *testing.T) {
+	mirror, local := bundleMirror(t)
+	repo := NewV1Repo(mirror, Options{GOOS: "plat", GOARCH: "form"}, local)
+
+	// pack an extra file and a nested one into the bundle
+	dir := createBundle(t, repo, []ComponentSpec{{ID: "foo"}})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "evil.sh"), []byte("evil"), 0755))
+	tarball := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	pack := func() {
+		file, err := os.Create(tarball)
+		require.NoError(t, err)
+		defer file.Close()
+		require.NoError(t, utils.Tar(file, dir))
+	}
+	pack()
+
+	// only the verified tarballs are extracted besides the manifests
+	target := t.TempDir()
+	require.NoError(t, repo.ExtractBundle(tarball, target))
+	assert.FileExists(t, filepath.Join(target, BundleManifestFilename))
+	assert.FileExists(t, filepath.Join(target, "foo-2.0.1.tar.gz"))
+	assert.NoFileExists(t, filepath.Join(target, "evil.sh"))
+
+	// nothing but the manifests is extracted from an untrusted bundle
+	_, untrusted := bundleMirror(t)
+	target = t.TempDir()
+	err := NewV1Repo(&MockMirror{}, Options{GOOS: "plat", GOARCH: "form"}, untrusted).ExtractBundle(tarball, target)
+	assert.ErrorContains(t, err, "verify bundle")
+	assert.NoFileExists(t, filepath.Join(target, "foo-2.0.1.tar.gz"))
+
+	// only regular files in the top level directory are allowed
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	pack()
+	err = repo.ExtractBundle(tarball, t.TempDir())
+	assert.ErrorContains(t, err, "unexpected entry sub")
+}
@@ -0,0 +1,107 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gofrs/flock"
+	"github.com/pingcap/errors"
+	"github.com/pingcap/tiup/pkg/localdata"
+	logprinter "github.com/pingcap/tiup/pkg/logger/printer"
+	"github.com/pingcap/tiup/pkg/utils"
+)
+
+// DownloadCache is a content-addressed cache of the downloaded tarballs
+// keyed by their sha256, it can be shared between profiles and processes,
+// the entries are guarded by file locks so that a tarball is downloaded
+// only once even if it's requested concurrently.
+type DownloadCache struct {
+	dir string
+}
+
+// NewDownloadCache returns a download cache in dir
+func NewDownloadCache(dir string) *DownloadCache {
+	return &DownloadCache{dir: dir}
+}
+
+// SharedDownloadCache returns the download cache specified by
+// TIUP_DOWNLOAD_CACHE, nil is returned if it's not specified
+func SharedDownloadCache() *DownloadCache {
+	dir := os.Getenv(localdata.EnvNameDownloadCache)
+	if dir == "" {
+		return nil
+	}
+	return NewDownloadCache(dir)
+}
+
+// Path returns the path of the entry with the sha256
+func (c *DownloadCache) Path(sha256 string) string {
+	prefix := sha256
+	if len(prefix) > 2 {
+		prefix = prefix[:2]
+	}
+	return filepath.Join(c.dir, "sha256", prefix, sha256)
+}
+
+// Get copies the entry with the sha256 to target, the entry is filled by
+// calling fill with the path to write if it's not cached. The fill function
+// is responsible for verifying the file written.
+func (c *DownloadCache) Get(sha256, target string, fill func(path string) error) error {
+	path := c.Path(sha256)
+	if err := utils.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Trace(err)
+	}
+
+	lock := flock.New(path + ".lock")
+	if err := lock.Lock(); err != nil {
+		return errors.Annotatef(err, "lock download cache %s", path)
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	if utils.IsNotExist(path) {
+		tmp := path + ".tmp"
+		if err := fill(tmp); err != nil {
+			_ = os.Remove(tmp)
+			return err
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		logprinter.Verbose("Use the cached %s for %s", path, target)
+	}
+
+	if err := utils.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return errors.Trace(err)
+	}
+	if err := utils.Copy(path, target); err != nil {
+		return errors.Trace(err)
+	}
+
+	reader, err := os.Open(target)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = utils.CheckSHA256(reader, sha256)
+	reader.Close()
+	if err != nil {
+		// the entry is broken, drop it and fill the target directly
+		_ = os.Remove(path)
+		_ = os.Remove(target)
+		return fill(target)
+	}
+	return nil
+}
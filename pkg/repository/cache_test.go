@@ -0,0 +1,90 @@
+// Copyright 2026 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pingcap/tiup/pkg/localdata"
+	"github.com/pingcap/tiup/pkg/repository/v1manifest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadCache(t *testing.T) {
+	cache := NewDownloadCache(t.TempDir())
+	content := "foo201"
+	sha := hash(content)
+
+	var fills atomic.Int32
+	fill := func(path string) error {
+		fills.Add(1)
+		return os.WriteFile(path, []byte(content), 0644)
+	}
+
+	var wg sync.WaitGroup
+	targets := t.TempDir()
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, cache.Get(sha, filepath.Join(targets, string(rune('a'+i)), "foo.tar.gz"), fill))
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), fills.Load())
+	for i := range 8 {
+		data, err := os.ReadFile(filepath.Join(targets, string(rune('a'+i)), "foo.tar.gz"))
+		require.NoError(t, err)
+		require.Equal(t, content, string(data))
+	}
+
+	// the broken entry is dropped and the target is filled directly
+	require.NoError(t, os.WriteFile(cache.Path(sha), []byte("broken"), 0644))
+	target := filepath.Join(targets, "broken.tar.gz")
+	require.NoError(t, cache.Get(sha, target, fill))
+	require.Equal(t, int32(2), fills.Load())
+	data, err := os.ReadFile(target)
+	require.NoError(t, err)
+	require.Equal(t, content, string(data))
+	require.NoFileExists(t, cache.Path(sha))
+}
+
+func TestDownloadComponentWithCache(t *testing.T) {
+	t.Setenv(localdata.EnvNameDownloadCache, t.TempDir())
+	mirror := &MockMirror{Resources: map[string]string{"/foo-2.0.1.tar.gz": "foo201"}}
+	repo := NewV1Repo(mirror, Options{}, v1manifest.NewMockManifests())
+	item := versionItem()
+
+	target := filepath.Join(t.TempDir(), "foo.tar.gz")
+	require.NoError(t, repo.DownloadComponent(&item, target))
+	require.FileExists(t, SharedDownloadCache().Path(item.Hashes[v1manifest.SHA256]))
+
+	// the tarball is served by the cache shared with other profiles
+	delete(mirror.Resources, "/foo-2.0.1.tar.gz")
+	other := filepath.Join(t.TempDir(), "foo.tar.gz")
+	require.NoError(t, repo.DownloadComponent(&item, other))
+	data, err := os.ReadFile(other)
+	require.NoError(t, err)
+	require.Equal(t, "foo201", string(data))
+
+	// tarballs with wrong hash are not cached
+	bad := versionItem2()
+	mirror.Resources["/foo-2.0.2.tar.gz"] = "evil"
+	require.Error(t, repo.DownloadComponent(&bad, filepath.Join(t.TempDir(), "foo.tar.gz")))
+	require.NoFileExists(t, SharedDownloadCache().Path(bad.Hashes[v1manifest.SHA256]))
+}
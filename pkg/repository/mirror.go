@@ -83,6 +83,15 @@ type (
 		Error(url string, attempt, maxAttempts int, err error)
 	}
 
+	// DownloadProgressTracker is an optional extension interface for
+	// DownloadProgress implementations that are able to display concurrent
+	// downloads. When provided, the progress of each download is reported to
+	// a new DownloadProgress returned by Track, so that the downloads can be
+	// combined into one display.
+	DownloadProgressTracker interface {
+		Track() DownloadProgress
+	}
+
 	// MirrorOptions is used to customize the mirror download options
 	MirrorOptions struct {
 		// Context controls download cancelation. When canceled, ongoing network
@@ -377,6 +386,9 @@ func (l *httpMirror) downloadFile(url string, to string, maxSize int64) (io.Read
 	var progress DownloadProgress
 	if strings.Contains(url, ".tar.gz") {
 		progress = l.options.Progress
+		if tracker, ok := progress.(DownloadProgressTracker); ok {
+			progress = tracker.Track()
+		}
 	} else {
 		progress = DisableProgress{}
 	}
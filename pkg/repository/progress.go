@@ -16,6 +16,8 @@ package repository
 import (
 	"fmt"
 	"os"
+	"slices"
+	"sync"
 
 	"github.com/cheggaaa/pb/v3"
 	"golang.org/x/term"
@@ -33,28 +35,44 @@ func (d DisableProgress) SetCurrent(size int64) {}
 // Finish implement the DownloadProgress interface
 func (d DisableProgress) Finish() {}
 
-// ProgressBar implement the DownloadProgress interface with download progress
+// ProgressBar implement the DownloadProgress interface with download progress,
+// the concurrent downloads tracked by it are combined into one bar
 type ProgressBar struct {
 	bar  *pb.ProgressBar
 	size int64
+
+	mu       sync.Mutex
+	combined *pb.ProgressBar
+	trackers []*progressTracker
+	count    int   // number of downloads displayed in the combined bar
+	total    int64 // total size of downloads displayed in the combined bar
+	finished int64 // size of finished downloads displayed in the combined bar
 }
 
-// Start implement the DownloadProgress interface
-func (p *ProgressBar) Start(url string, size int64) {
-	p.size = size
-	p.bar = pb.Start64(size)
-	p.bar.Set(pb.Bytes, true)
+// startBar starts a progress bar of downloading name
+func startBar(name string, size int64) *pb.ProgressBar {
+	bar := pb.Start64(size)
+	bar.Set(pb.Bytes, true)
+	bar.SetTemplateString(barTemplate(name))
+	return bar
+}
 
+func barTemplate(name string) string {
 	// Check if stdout is a TTY
 	isTTY := term.IsTerminal(int(os.Stdout.Fd()))
 
 	// Use a simple template without ANSI escape sequences when stdout is not a TTY
 	if isTTY {
-		p.bar.SetTemplateString(fmt.Sprintf(`download %s {{counters . }} {{percent . }} {{speed . "%%s/s" "? MiB/s"}}`, url))
-	} else {
-		// Simple template for non-TTY output (no progress bar, just text)
-		p.bar.SetTemplateString(fmt.Sprintf(`download %s {{counters . }}`, url))
+		return fmt.Sprintf(`download %s {{counters . }} {{percent . }} {{speed . "%%s/s" "? MiB/s"}}`, name)
 	}
+	// Simple template for non-TTY output (no progress bar, just text)
+	return fmt.Sprintf(`download %s {{counters . }}`, name)
+}
+
+// Start implement the DownloadProgress interface
+func (p *ProgressBar) Start(url string, size int64) {
+	p.size = size
+	p.bar = startBar(url, size)
 }
 
 // SetCurrent implement the DownloadProgress interface
@@ -66,3 +84,72 @@ func (p *ProgressBar) SetCurrent(size int64) {
 func (p *ProgressBar) Finish() {
 	p.bar.Finish()
 }
+
+// Track implements the DownloadProgressTracker interface
+func (p *ProgressBar) Track() DownloadProgress {
+	return &progressTracker{parent: p}
+}
+
+// progressTracker reports the progress of a download to the combined bar
+type progressTracker struct {
+	parent  *ProgressBar
+	current int64
+}
+
+// Start implement the DownloadProgress interface
+func (t *progressTracker) Start(url string, size int64) {
+	p := t.parent
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.trackers = append(p.trackers, t)
+	p.count++
+	if size > 0 {
+		p.total += size
+	}
+	if p.combined == nil {
+		p.combined = startBar(url, p.total)
+		return
+	}
+	p.combined.SetTotal(p.total)
+	p.combined.SetTemplateString(barTemplate(fmt.Sprintf("%d files", p.count)))
+}
+
+// SetCurrent implement the DownloadProgress interface
+func (t *progressTracker) SetCurrent(size int64) {
+	p := t.parent
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t.current = size
+	p.refresh()
+}
+
+// Finish implement the DownloadProgress interface
+func (t *progressTracker) Finish() {
+	p := t.parent
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.trackers = slices.DeleteFunc(p.trackers, func(tracker *progressTracker) bool { return tracker == t })
+	p.finished += t.current
+	p.refresh()
+	if len(p.trackers) > 0 {
+		return
+	}
+	p.combined.Finish()
+	p.combined = nil
+	p.count, p.total, p.finished = 0, 0, 0
+}
+
+// refresh updates the combined bar, the caller must hold the lock
+func (p *ProgressBar) refresh() {
+	if p.combined == nil {
+		return
+	}
+	current := p.finished
+	for _, tracker := range p.trackers {
+		current += tracker.current
+	}
+	p.combined.SetCurrent(current)
+}
@@ -14,6 +14,7 @@
 package repository
 
 import (
+	"sync"
 	"testing"
 )
 
@@ -29,3 +30,23 @@ func TestProgress(t *testing.T) {
 		p.Finish()
 	}
 }
+
+func TestProgressTrack(t *testing.T) {
+	p := &ProgressBar{}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tracker := p.Track()
+			tracker.Start("x", 10)
+			tracker.SetCurrent(5)
+			tracker.SetCurrent(10)
+			tracker.Finish()
+		}()
+	}
+	wg.Wait()
+	if p.combined != nil || len(p.trackers) != 0 {
+		t.Fatal("the combined bar should be finished")
+	}
+}
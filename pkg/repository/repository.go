@@ -21,6 +21,7 @@ import (
 // Repository represents a local components repository that mirrored the remote Repository(either filesystem or HTTP server).
 type Repository interface {
 	Mirror() Mirror
+	PlatformString() string
 	WithOptions(opts Options) Repository
 	UpdateComponents(specs []ComponentSpec) error
 	ResolveComponentVersion(id, constraint string) (utils.Version, error)
@@ -40,6 +41,9 @@ type Repository interface {
 	LatestStableVersion(id string, withYanked bool, filter func(string) bool) (utils.Version, *v1manifest.VersionItem, error)
 	LatestNightlyVersion(id string) (utils.Version, *v1manifest.VersionItem, error)
 	ComponentVersion(id, ver string, includeYanked bool) (*v1manifest.VersionItem, error)
+	CreateBundle(specs []ComponentSpec, output string) error
+	ExtractBundle(tarball, dir string) error
+	InstallBundle(dir string, specs []ComponentSpec) error
 }
 
 // Options represents options for a repository
@@ -23,6 +23,7 @@ import (
 	"path"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -64,6 +65,9 @@ type ComponentSpec struct {
 	Version string
 	// Force is true means overwrite any existing installation.
 	Force bool
+	// Hash is the expected sha256 of the tarball, it's checked against the
+	// manifest before downloading if not empty.
+	Hash string
 }
 
 // NewV1Repo creates a new v1 repository from the given mirror
@@ -113,6 +117,19 @@ func (r *V1Repository) LocalComponentInstalled(component, version string) (bool,
 	return r.local.ComponentInstalled(component, version)
 }
 
+// maxParallelDownloads is the maximum number of components downloaded at
+// the same time
+const maxParallelDownloads = 4
+
+// pendingComponent is a component to be downloaded and installed
+type pendingComponent struct {
+	spec      ComponentSpec
+	item      *v1manifest.VersionItem
+	targetDir string
+	target    string
+	err       error
+}
+
 // UpdateComponents updates the components described by specs.
 func (r *V1Repository) UpdateComponents(specs []ComponentSpec) error {
 	err := r.ensureManifests()
@@ -124,7 +141,10 @@ func (r *V1Repository) UpdateComponents(specs []ComponentSpec) error {
 	if v := os.Getenv(localdata.EnvNameKeepSourceTarget); v == "enable" || v == "true" {
 		keepSource = true
 	}
-	var errs []string
+	var (
+		errs    []string
+		pending []pendingComponent
+	)
 	for _, spec := range specs {
 		manifest, err := r.updateComponentManifest(spec.ID, false)
 		if err != nil {
@@ -173,33 +193,60 @@ func (r *V1Repository) UpdateComponents(specs []ComponentSpec) error {
 		if err != nil {
 			return err
 		}
+		if spec.Hash != "" && versionItem.Hashes[v1manifest.SHA256] != spec.Hash {
+			errs = append(errs, fmt.Sprintf("the hash of component %s:%s is %s, but %s is expected", spec.ID, spec.Version, versionItem.Hashes[v1manifest.SHA256], spec.Hash))
+			continue
+		}
 
 		target := filepath.Join(targetDir, versionItem.URL)
-		err = r.DownloadComponent(versionItem, target)
-		if err != nil {
-			os.RemoveAll(targetDir)
-			errs = append(errs, err.Error())
+		if slices.ContainsFunc(pending, func(comp pendingComponent) bool { return comp.target == target }) {
 			continue
 		}
+		pending = append(pending, pendingComponent{
+			spec:      spec,
+			item:      versionItem,
+			targetDir: targetDir,
+			target:    target,
+		})
+	}
 
-		reader, err := os.Open(target)
+	// Download the components in parallel and install them one by one
+	var g errgroup.Group
+	g.SetLimit(maxParallelDownloads)
+	for idx := range pending {
+		comp := &pending[idx]
+		g.Go(func() error {
+			comp.err = r.DownloadComponent(comp.item, comp.target)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	for _, comp := range pending {
+		if comp.err != nil {
+			os.RemoveAll(comp.targetDir)
+			errs = append(errs, comp.err.Error())
+			continue
+		}
+
+		reader, err := os.Open(comp.target)
 		if err != nil {
-			os.RemoveAll(targetDir)
+			os.RemoveAll(comp.targetDir)
 			errs = append(errs, err.Error())
 			continue
 		}
 
-		err = r.local.InstallComponent(reader, targetDir, spec.ID, spec.Version, versionItem.URL, r.DisableDecompress)
+		err = r.local.InstallComponent(reader, comp.targetDir, comp.spec.ID, comp.spec.Version, comp.item.URL, r.DisableDecompress)
 		reader.Close()
 
 		if err != nil {
-			os.RemoveAll(targetDir)
+			os.RemoveAll(comp.targetDir)
 			errs = append(errs, err.Error())
 		}
 
 		// remove the source gzip target if expand is on && no keep source
 		if !r.DisableDecompress && !keepSource {
-			_ = os.Remove(target)
+			_ = os.Remove(comp.target)
 		}
 	}
 
@@ -487,8 +534,20 @@ func (r *V1Repository) updateComponentManifest(id string, withYanked bool) (*v1m
 }
 
 // DownloadComponent downloads the component specified by item into local file,
-// the component will be removed if hash is not correct
+// the component will be removed if hash is not correct. The shared download
+// cache is used if it's specified.
 func (r *V1Repository) DownloadComponent(item *v1manifest.VersionItem, target string) error {
+	cache := SharedDownloadCache()
+	hash := item.Hashes[v1manifest.SHA256]
+	if cache == nil || hash == "" {
+		return r.downloadComponent(item, target)
+	}
+	return cache.Get(hash, target, func(path string) error {
+		return r.downloadComponent(item, path)
+	})
+}
+
+func (r *V1Repository) downloadComponent(item *v1manifest.VersionItem, target string) error {
 	// make a tempdir such that every download will not inference each other
 	targetDir := filepath.Dir(target)
 	err := os.MkdirAll(targetDir, 0755)
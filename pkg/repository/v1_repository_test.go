@@ -652,6 +652,16 @@ func TestUpdateComponents(t *testing.T) {
 	assert.Equal(t, 1, len(local.Installed))
 	assert.Equal(t, "v3.0.0-rc", local.Installed["foo"].Version)
 	assert.Equal(t, "foo300rc", local.Installed["foo"].Contents)
+
+	// Pinned hash
+	err = repo.UpdateComponents([]ComponentSpec{{
+		ID:      "foo",
+		Version: "v2.0.1",
+		Force:   true,
+		Hash:    "0000",
+	}})
+	assert.ErrorContains(t, err, "but 0000 is expected")
+	assert.Equal(t, "v3.0.0-rc", local.Installed["foo"].Version)
 }
 
 func timestampManifest() *v1manifest.Timestamp {
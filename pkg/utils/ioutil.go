@@ -22,7 +22,6 @@ import (
 	"io"
 	"io/fs"
 	"os"
-	"path"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -148,8 +147,17 @@ func Untar(reader io.Reader, to string) error {
 
 	tr := tar.NewReader(gr)
 
-	decFile := func(hdr *tar.Header) error {
-		file := path.Join(to, hdr.Name)
+	// target returns the path to extract the entry to, the entries must not
+	// be written out of the target directory
+	target := func(name string) (string, error) {
+		file := filepath.Join(to, name)
+		if filepath.IsAbs(name) || !IsSubDir(to, file) {
+			return "", errors.Errorf("invalid entry %s in tarball: out of the target directory", name)
+		}
+		return file, nil
+	}
+
+	decFile := func(file string, hdr *tar.Header) error {
 		err := MkdirAll(filepath.Dir(file), 0o755)
 		if err != nil {
 			return err
@@ -172,17 +180,26 @@ func Untar(reader io.Reader, to string) error {
 		if err != nil {
 			return errors.Trace(err)
 		}
+		file, err := target(hdr.Name)
+		if err != nil {
+			return err
+		}
 		switch hdr.Typeflag {
 		case tar.TypeDir:
-			if err := MkdirAll(path.Join(to, hdr.Name), hdr.FileInfo().Mode()); err != nil {
+			if err := MkdirAll(file, hdr.FileInfo().Mode()); err != nil {
 				return errors.Trace(err)
 			}
 		case tar.TypeSymlink:
-			if err = os.Symlink(hdr.Linkname, filepath.Join(to, hdr.Name)); err != nil {
+			// the links pointing out of the target directory are rejected,
+			// so that no file is written out of it through them
+			if filepath.IsAbs(hdr.Linkname) || !IsSubDir(to, filepath.Join(filepath.Dir(file), hdr.Linkname)) {
+				return errors.Errorf("invalid symlink %s -> %s in tarball: out of the target directory", hdr.Name, hdr.Linkname)
+			}
+			if err = os.Symlink(hdr.Linkname, file); err != nil {
 				return errors.Trace(err)
 			}
 		default:
-			if err := decFile(hdr); err != nil {
+			if err := decFile(file, hdr); err != nil {
 				return errors.Trace(err)
 			}
 		}
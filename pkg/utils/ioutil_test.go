@@ -1,7 +1,9 @@
 package utils
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"math/rand"
 	"os"
 	"path"
@@ -71,6 +73,44 @@ func TestUntar(t *testing.T) {
 	require.True(t, IsExist(path.Join(currentDir(), "testdata", "parent", "child", "content")))
 }
 
+func TestUntarOutOfTarget(t *testing.T) {
+	tarball := func(hdrs ...*tar.Header) *bytes.Buffer {
+		buf := bytes.NewBuffer(nil)
+		gw := gzip.NewWriter(buf)
+		tw := tar.NewWriter(gw)
+		for _, hdr := range hdrs {
+			require.NoError(t, tw.WriteHeader(hdr))
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gw.Close())
+		return buf
+	}
+
+	for _, hdr := range []*tar.Header{
+		{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0o644},
+		{Name: "a/../../evil", Typeflag: tar.TypeReg, Mode: 0o644},
+		{Name: "/tmp/evil", Typeflag: tar.TypeReg, Mode: 0o644},
+		{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc"},
+		{Name: "a/link", Typeflag: tar.TypeSymlink, Linkname: "../../etc"},
+	} {
+		dir := t.TempDir()
+		err := Untar(tarball(hdr), filepath.Join(dir, "target"))
+		require.Error(t, err, hdr.Name)
+		require.NoFileExists(t, filepath.Join(dir, "evil"))
+	}
+
+	// the links inside the target directory are kept
+	dir := t.TempDir()
+	require.NoError(t, Untar(tarball(
+		&tar.Header{Name: "a/", Typeflag: tar.TypeDir, Mode: 0o755},
+		&tar.Header{Name: "b", Typeflag: tar.TypeSymlink, Linkname: "a"},
+		&tar.Header{Name: "a/c", Typeflag: tar.TypeSymlink, Linkname: "../b"},
+	), dir))
+	link, err := os.Readlink(filepath.Join(dir, "a", "c"))
+	require.NoError(t, err)
+	require.Equal(t, "../b", link)
+}
+
 func TestCopy(t *testing.T) {
 	require.Error(t, Copy(path.Join(currentDir(), "testdata", "test.tar.gz"), "/tmp/not-exists/test.tar.gz"))
 	require.NoError(t, Copy(path.Join(currentDir(), "testdata", "test.tar.gz"), "/tmp/test.tar.gz"))